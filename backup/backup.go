@@ -0,0 +1,274 @@
+// Package backup создает резервные копии базы (по расписанию, по команде администратора
+// и из CLI) и восстанавливает базу из них. Копия - снимок базы на момент создания,
+// сжатый gzip и зашифрованный ключом данных, если это включено в настройках.
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"DebtBot/config"
+	"DebtBot/db"
+	"DebtBot/logging"
+)
+
+var logger = logging.For("backup")
+
+// Имя копии: debtbot-ГГГГММДД-ЧЧММСС.db[.gz][.enc]
+const (
+	filePrefix = "debtbot-"
+	timeLayout = "20060102-150405"
+	extGzip    = ".gz"
+	extSealed  = ".enc"
+)
+
+// Назначение шифротекста для GCM: копию нельзя выдать за зашифрованное значение колонки
+const sealPurpose = "backup"
+
+// Options - настройки резервного копирования
+type Options struct {
+	Dir    string     // Папка с копиями
+	Keep   int        // Сколько последних копий хранить; 0 - все
+	Gzip   bool       // Сжимать копии
+	Cipher *db.Cipher // Шифровать копии; nil - без шифрования
+}
+
+// FromConfig собирает настройки копирования из настроек бота
+func FromConfig(cfg *config.Config) (Options, error) {
+	opts := Options{Dir: cfg.BackupDir, Keep: cfg.BackupKeep, Gzip: cfg.BackupGzip}
+	if !cfg.BackupEncrypt {
+		return opts, nil
+	}
+	keys, err := cfg.EncryptionKeys()
+	if err != nil {
+		return Options{}, err
+	}
+	opts.Cipher, err = db.NewCipher(keys)
+	return opts, err
+}
+
+// Snapshot - резервная копия в папке копий
+type Snapshot struct {
+	Path      string
+	CreatedAt time.Time
+}
+
+// Service создает копии базы
+type Service struct {
+	db   *db.DB
+	opts Options
+}
+
+func New(database *db.DB, opts Options) *Service {
+	return &Service{db: database, opts: opts}
+}
+
+// Run создает копию по расписанию; ошибки только записываются в журнал
+func (s *Service) Run() {
+	if _, err := s.Create(); err != nil {
+		logger.Error("Backup failed", "err", err)
+	}
+}
+
+// Create создает копию базы, проверяет ее и удаляет старые копии сверх Keep.
+// Возвращает путь к файлу копии.
+func (s *Service) Create() (string, error) {
+	if err := os.MkdirAll(s.opts.Dir, 0o700); err != nil {
+		return "", err
+	}
+	now := time.Now()
+
+	// Снимок делается во временный файл рядом с копиями и проверяется до упаковки
+	snapshot, err := os.CreateTemp(s.opts.Dir, ".snapshot-*.db")
+	if err != nil {
+		return "", err
+	}
+	snapshot.Close()
+	defer os.Remove(snapshot.Name())
+	if err := s.db.BackupTo(snapshot.Name()); err != nil {
+		return "", fmt.Errorf("copying database: %w", err)
+	}
+	if err := db.CheckIntegrity(snapshot.Name()); err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(snapshot.Name())
+	if err != nil {
+		return "", err
+	}
+	name := filePrefix + now.Format(timeLayout) + ".db"
+	if s.opts.Gzip {
+		var buf bytes.Buffer
+		writer := gzip.NewWriter(&buf)
+		if _, err := writer.Write(data); err != nil {
+			return "", err
+		}
+		if err := writer.Close(); err != nil {
+			return "", err
+		}
+		data, name = buf.Bytes(), name+extGzip
+	}
+	if s.opts.Cipher != nil {
+		if data, err = s.opts.Cipher.SealBytes(sealPurpose, data); err != nil {
+			return "", err
+		}
+		name += extSealed
+	}
+
+	path := filepath.Join(s.opts.Dir, name)
+	if err := writeFile(path, data); err != nil {
+		return "", err
+	}
+	logger.Info("Backup created", "path", path, "bytes", len(data))
+
+	if err := s.prune(); err != nil {
+		logger.Error("Error removing old backups", "dir", s.opts.Dir, "err", err)
+	}
+	return path, nil
+}
+
+// prune удаляет самые старые копии, оставляя Keep последних
+func (s *Service) prune() error {
+	if s.opts.Keep <= 0 {
+		return nil
+	}
+	snapshots, err := List(s.opts.Dir)
+	if err != nil {
+		return err
+	}
+	for len(snapshots) > s.opts.Keep {
+		if err := os.Remove(snapshots[0].Path); err != nil {
+			return err
+		}
+		logger.Info("Old backup removed", "path", snapshots[0].Path)
+		snapshots = snapshots[1:]
+	}
+	return nil
+}
+
+// List возвращает копии в папке от старых к новым
+func List(dir string) ([]Snapshot, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var snapshots []Snapshot
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, filePrefix) || strings.HasSuffix(name, ".tmp") {
+			continue
+		}
+		stamp, _, _ := strings.Cut(strings.TrimPrefix(name, filePrefix), ".")
+		createdAt, err := time.ParseInLocation(timeLayout, stamp, time.Local)
+		if err != nil {
+			continue
+		}
+		snapshots = append(snapshots, Snapshot{Path: filepath.Join(dir, name), CreatedAt: createdAt})
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].CreatedAt.Before(snapshots[j].CreatedAt) })
+	return snapshots, nil
+}
+
+// At возвращает последнюю копию, созданную не позже момента at
+func At(dir string, at time.Time) (Snapshot, error) {
+	snapshots, err := List(dir)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		if !snapshots[i].CreatedAt.After(at) {
+			return snapshots[i], nil
+		}
+	}
+	return Snapshot{}, fmt.Errorf("no backup in %s made at or before %s", dir, at.Format(time.DateTime))
+}
+
+// Verify распаковывает копию во временный файл и проверяет целостность базы в нем
+func Verify(path string, cipher *db.Cipher) error {
+	unpacked, err := unpack(path, cipher, filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(unpacked)
+	return db.CheckIntegrity(unpacked)
+}
+
+// Restore заменяет файл базы dbPath копией path после проверки целостности.
+// Бот должен быть остановлен. Текущий файл базы (и его журналы) сохраняется рядом
+// с суффиксом .before-restore.
+func Restore(path, dbPath string, cipher *db.Cipher) error {
+	unpacked, err := unpack(path, cipher, filepath.Dir(dbPath))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(unpacked)
+	if err := db.CheckIntegrity(unpacked); err != nil {
+		return err
+	}
+
+	// Журналы прежней базы относятся к ней, а не к восстановленной
+	for _, suffix := range []string{"", "-journal", "-wal", "-shm"} {
+		err := os.Rename(dbPath+suffix, dbPath+".before-restore"+suffix)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return os.Rename(unpacked, dbPath)
+}
+
+// unpack расшифровывает и распаковывает копию во временный файл в папке dir.
+// Формат определяется по расширениям имени файла.
+func unpack(path string, cipher *db.Cipher, dir string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	name := filepath.Base(path)
+	if strings.HasSuffix(name, extSealed) {
+		if data, err = cipher.OpenBytes(sealPurpose, data); err != nil {
+			return "", fmt.Errorf("decrypting %s: %w", name, err)
+		}
+		name = strings.TrimSuffix(name, extSealed)
+	}
+	if strings.HasSuffix(name, extGzip) {
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return "", fmt.Errorf("decompressing %s: %w", name, err)
+		}
+		if data, err = io.ReadAll(reader); err != nil {
+			return "", fmt.Errorf("decompressing %s: %w", name, err)
+		}
+	}
+
+	file, err := os.CreateTemp(dir, ".restore-*.db")
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	if _, err := file.Write(data); err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+// writeFile записывает файл целиком: сначала во временный, затем переименовывает,
+// чтобы в папке копий не появлялись недописанные файлы
+func writeFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,168 @@
+// Package banks - встроенный справочник российских банков: каноническое название, варианты
+// написания, БИК и эмодзи-"логотип". По справочнику "Сбер", "Сбербанк" и "sberbank"
+// считаются одним банком.
+package banks
+
+import (
+	_ "embed"
+	"encoding/json"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+//go:embed catalog.json
+var catalogJSON []byte
+
+// Bank - банк из справочника
+type Bank struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"` // Каноническое название
+	BIC     string   `json:"bic"`
+	Emoji   string   `json:"emoji"`
+	Aliases []string `json:"aliases"`
+}
+
+var (
+	catalog []*Bank
+	byID    = make(map[string]*Bank)
+	keys    = make(map[string]*Bank) // Нормализованные название и варианты написания
+)
+
+func init() {
+	if err := json.Unmarshal(catalogJSON, &catalog); err != nil {
+		panic("banks: некорректный catalog.json: " + err.Error())
+	}
+	for _, bank := range catalog {
+		byID[bank.ID] = bank
+		for _, name := range append([]string{bank.Name}, bank.Aliases...) {
+			keys[normalize(name)] = bank
+		}
+	}
+}
+
+// All возвращает все банки справочника
+func All() []*Bank {
+	return catalog
+}
+
+// ByID ищет банк по идентификатору
+func ByID(id string) (*Bank, bool) {
+	bank, ok := byID[id]
+	return bank, ok
+}
+
+// Canonical - название банка для отчетов: из справочника, если банк в нем есть,
+// иначе - как ввел пользователь
+func Canonical(bankID, bankName string) string {
+	if bank, ok := byID[bankID]; ok {
+		return bank.Name
+	}
+	return strings.TrimSpace(bankName)
+}
+
+// Match находит банк, если название однозначно: полностью совпадает с названием или вариантом
+// написания ("сбер", "Tinkoff") или продолжает его ("Сбербанке", "сберу")
+func Match(name string) (*Bank, bool) {
+	n := normalize(name)
+	if n == "" {
+		return nil, false
+	}
+	if bank, ok := keys[n]; ok {
+		return bank, true
+	}
+
+	var found *Bank
+	for key, bank := range keys {
+		if runeLen(key) < 4 || runeLen(n) < 4 || !(strings.HasPrefix(n, key) || strings.HasPrefix(key, n)) {
+			continue
+		}
+		if found != nil && found != bank {
+			return nil, false // Подходит несколько банков
+		}
+		found = bank
+	}
+	return found, found != nil
+}
+
+// Suggest предлагает до limit банков с похожим названием - для опечаток ("Сбербнак", "альфе")
+func Suggest(name string, limit int) []*Bank {
+	n := normalize(name)
+	if runeLen(n) < 3 {
+		return nil
+	}
+	maxDistance := max(1, runeLen(n)/3)
+
+	distances := make(map[*Bank]int)
+	for key, bank := range keys {
+		d := distance(n, key)
+		if d > maxDistance {
+			continue
+		}
+		if current, ok := distances[bank]; !ok || d < current {
+			distances[bank] = d
+		}
+	}
+
+	suggestions := make([]*Bank, 0, len(distances))
+	for bank := range distances {
+		suggestions = append(suggestions, bank)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if distances[suggestions[i]] != distances[suggestions[j]] {
+			return distances[suggestions[i]] < distances[suggestions[j]]
+		}
+		return suggestions[i].Name < suggestions[j].Name
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+// Слова, которые не различают банки
+var noiseWords = map[string]bool{
+	"банк": true, "bank": true, "пао": true, "ао": true, "оао": true, "зао": true, "ооо": true,
+	"pjsc": true, "jsc": true, "публичное": true, "акционерное": true, "общество": true,
+}
+
+// normalize: нижний регистр, "ё" -> "е", без кавычек, дефисов, пробелов и слова "банк"
+func normalize(s string) string {
+	s = strings.ReplaceAll(strings.ToLower(s), "ё", "е")
+	words := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	for _, word := range words {
+		if !noiseWords[word] {
+			b.WriteString(word)
+		}
+	}
+	return b.String()
+}
+
+// distance - расстояние Левенштейна по символам
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func runeLen(s string) int {
+	return len([]rune(s))
+}
@@ -0,0 +1,173 @@
+package bot
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"DebtBot/config"
+	"DebtBot/i18n"
+	"DebtBot/models"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// Приглашение по ссылке: https://t.me/<бот>?start=inv_<код>
+const inviteStartPrefix = "inv_"
+
+// Символы кода приглашения: без похожих друг на друга 0/O и 1/I, чтобы код было легко продиктовать
+const inviteAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+const inviteCodeLength = 8
+
+// hasAccess проверяет, может ли пользователь пользоваться ботом в режиме доступа из настроек.
+// Не создает записей в базе.
+func (b *Bot) hasAccess(userID int64) bool {
+	switch b.cfg.AccessMode {
+	case config.AccessAllowlist:
+		return b.cfg.IsAllowed(userID)
+	case config.AccessInvite:
+		if b.cfg.IsAllowed(userID) {
+			return true
+		}
+		// Кто уже воспользовался приглашением, зарегистрирован
+		exists, err := b.db.UserExists(userID)
+		if err != nil {
+			logger.Error("Error checking user", "user_id", userID, "err", err)
+		}
+		return exists
+	default:
+		return true
+	}
+}
+
+// handleNoAccess принимает код приглашения или вежливо отказывает. Пока код не принят,
+// пользователь в базе не появляется.
+func (b *Bot) handleNoAccess(message *tgbotapi.Message) {
+	userID := int64(message.From.ID)
+	// Пользователя нет в базе - язык берем из Telegram
+	tr := i18n.New(message.From.LanguageCode)
+
+	if b.cfg.AccessMode != config.AccessInvite {
+		logger.Info("Доступ запрещен", "user_id", userID)
+		b.sendMessage(message.Chat.ID, tr.T("access.private", userID), 0)
+		return
+	}
+
+	code := inviteCodeFrom(message)
+	if code == "" {
+		logger.Info("Доступ запрещен, нет приглашения", "user_id", userID)
+		b.sendMessage(message.Chat.ID, tr.T("access.invite_required"), 0)
+		return
+	}
+	ok, err := b.db.RedeemInvite(code, userID, time.Now())
+	if err != nil {
+		logger.Error("Error redeeming invite", "user_id", userID, "err", err)
+		b.sendMessage(message.Chat.ID, tr.T("error.generic"), 0)
+		return
+	}
+	if !ok {
+		logger.Info("Неверный код приглашения", "user_id", userID)
+		b.sendMessage(message.Chat.ID, tr.T("access.invite_invalid"), 0)
+		return
+	}
+
+	if err := b.db.SetTelegramLanguage(userID, message.From.LanguageCode); err != nil {
+		logger.Error("Error saving language", "user_id", userID, "err", err)
+	}
+	logger.Info("Пользователь принял приглашение", "user_id", userID)
+	b.sendMessage(message.Chat.ID, tr.T("access.welcome"), 0)
+	b.handleHelpCommand(message)
+}
+
+// handleGroupNoAccess отказывает постороннему в группе. Отвечаем только на команды,
+// чтобы не писать в чат на каждое сообщение; записей в базе не создаем.
+func (b *Bot) handleGroupNoAccess(message *tgbotapi.Message) {
+	logger.Info("Доступ запрещен в группе", "user_id", message.From.ID, "chat_id", message.Chat.ID)
+	if message.IsCommand() {
+		b.sendMessage(message.Chat.ID, i18n.New(message.From.LanguageCode).T("access.group_denied"), message.MessageID)
+	}
+}
+
+// inviteCodeFrom достает код приглашения из /start <код>, ссылки-приглашения или просто текста
+func inviteCodeFrom(message *tgbotapi.Message) string {
+	text := message.Text
+	if message.IsCommand() {
+		if message.Command() != "start" {
+			return ""
+		}
+		text = message.CommandArguments()
+	}
+	code := strings.ToUpper(strings.TrimSpace(text))
+	code = strings.TrimPrefix(code, strings.ToUpper(inviteStartPrefix))
+	if len(code) != inviteCodeLength || strings.Trim(code, inviteAlphabet) != "" {
+		return ""
+	}
+	return code
+}
+
+// handleInviteCommand выпускает код приглашения: /invite [число использований] [срок в днях]
+func (b *Bot) handleInviteCommand(message *tgbotapi.Message) {
+	adminID := int64(message.From.ID)
+	tr := b.tr(adminID)
+
+	invite := &models.Invite{CreatedBy: adminID, MaxUses: 1}
+	args := strings.Fields(message.CommandArguments())
+	if len(args) > 2 {
+		b.sendMessage(message.Chat.ID, tr.T("admin.invite_usage"), message.MessageID)
+		return
+	}
+	if len(args) > 0 {
+		uses, err := strconv.Atoi(args[0])
+		if err != nil || uses < 1 {
+			b.sendMessage(message.Chat.ID, tr.T("admin.invite_usage"), message.MessageID)
+			return
+		}
+		invite.MaxUses = uses
+	}
+	if len(args) > 1 {
+		days, err := strconv.Atoi(args[1])
+		if err != nil || days < 1 {
+			b.sendMessage(message.Chat.ID, tr.T("admin.invite_usage"), message.MessageID)
+			return
+		}
+		invite.ExpiresAt = time.Now().AddDate(0, 0, days).UTC()
+	}
+
+	code, err := newInviteCode()
+	if err == nil {
+		invite.Code = code
+		err = b.db.AddInvite(invite)
+	}
+	if err != nil {
+		logger.Error("Error creating invite", "admin_id", adminID, "err", err)
+		b.sendMessage(message.Chat.ID, tr.T("error.generic"), message.MessageID)
+		return
+	}
+	b.auditAdmin(adminID, "invite", 0, fmt.Sprintf("code=%s max_uses=%d expires_at=%s", invite.Code, invite.MaxUses, invite.ExpiresAt.Format(time.RFC3339)))
+
+	expires := tr.T("admin.invite_no_expiry")
+	if !invite.ExpiresAt.IsZero() {
+		expires = tr.T("admin.invite_expires", invite.ExpiresAt.In(time.Local).Format("02.01.2006 15:04"))
+	}
+	text := tr.N("admin.invite_created", invite.MaxUses, invite.Code, expires)
+	if b.cfg.AccessMode != config.AccessInvite {
+		text += "\n\n" + tr.T("admin.invite_mode_warning", b.cfg.AccessMode)
+	}
+	b.sendMessage(message.Chat.ID, text, message.MessageID)
+	// Ссылку отправляем отдельным сообщением без Markdown, чтобы ее было удобно переслать
+	b.sendPlainMessage(message.Chat.ID, fmt.Sprintf("https://t.me/%s?start=%s%s", b.botAPI.Self.UserName, inviteStartPrefix, invite.Code))
+}
+
+func newInviteCode() (string, error) {
+	buf := make([]byte, inviteCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	code := make([]byte, inviteCodeLength)
+	for i, c := range buf {
+		code[i] = inviteAlphabet[int(c)%len(inviteAlphabet)] // 256 делится на 32 - распределение равномерное
+	}
+	return string(code), nil
+}
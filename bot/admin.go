@@ -0,0 +1,283 @@
+package bot
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"DebtBot/banks"
+	"DebtBot/models"
+	"DebtBot/utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// Пользователь считается активным, если писал боту за этот срок
+const activeUserPeriod = 30 * 24 * time.Hour
+
+// handleAdminCommand - команды администраторов из admin_ids. Права проверяет вызывающий.
+func (b *Bot) handleAdminCommand(message *tgbotapi.Message) {
+	switch message.Command() {
+	case "stats":
+		b.handleStatsCommand(message)
+	case "broadcast":
+		b.handleBroadcastCommand(message)
+	case "user":
+		b.handleUserCommand(message)
+	case "ban":
+		b.handleBanCommand(message, true)
+	case "unban":
+		b.handleBanCommand(message, false)
+	case "jobs":
+		b.handleJobsCommand(message)
+	case "invite":
+		b.handleInviteCommand(message)
+	case "backup":
+		b.handleBackupCommand(message)
+	}
+}
+
+// auditAdmin записывает действие администратора в admin_log
+func (b *Bot) auditAdmin(adminID int64, action string, targetID int64, details string) {
+	entry := &models.AdminLogEntry{AdminID: adminID, Action: action, TargetID: targetID, Details: details}
+	if err := b.db.AddAdminLog(entry); err != nil {
+		logger.Error("Error writing admin log", "admin_id", adminID, "action", action, "err", err)
+	}
+	logger.Info("Действие администратора", "admin_id", adminID, "action", action, "target_id", targetID)
+}
+
+// isBanned проверяет, закрыт ли пользователю доступ к боту. Администраторов бан не касается.
+func (b *Bot) isBanned(userID int64) bool {
+	if b.cfg.IsAdmin(userID) {
+		return false
+	}
+	user, err := b.db.GetUser(userID)
+	return err == nil && user.Banned
+}
+
+func (b *Bot) handleStatsCommand(message *tgbotapi.Message) {
+	adminID := int64(message.From.ID)
+	tr := b.tr(adminID)
+
+	now := time.Now()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	stats, err := b.db.GetStats(now.Add(-activeUserPeriod), dayStart)
+	if err != nil {
+		logger.Error("handleStatsCommand: Ошибка при получении статистики из DB", "err", err)
+		b.sendMessage(message.Chat.ID, tr.T("error.generic"), message.MessageID)
+		return
+	}
+	b.auditAdmin(adminID, "stats", 0, "")
+	b.sendMessage(message.Chat.ID, tr.T("admin.stats",
+		stats.Users, stats.ActiveUsers, stats.BlockedUsers, stats.BannedUsers,
+		stats.Credits, stats.RemindersSentToday, stats.OutboxPending), message.MessageID)
+}
+
+// handleBroadcastCommand показывает текст рассылки так, как его увидят пользователи,
+// и отправляет только после подтверждения кнопкой
+func (b *Bot) handleBroadcastCommand(message *tgbotapi.Message) {
+	adminID := int64(message.From.ID)
+	tr := b.tr(adminID)
+
+	text := strings.TrimSpace(message.CommandArguments())
+	if text == "" {
+		b.sendMessage(message.Chat.ID, tr.T("admin.broadcast_usage"), message.MessageID)
+		return
+	}
+	recipients, err := b.db.GetBroadcastRecipients()
+	if err != nil {
+		logger.Error("handleBroadcastCommand: Ошибка при получении получателей из DB", "err", err)
+		b.sendMessage(message.Chat.ID, tr.T("error.generic"), message.MessageID)
+		return
+	}
+	b.pendingBroadcasts[adminID] = text
+
+	// Сначала предпросмотр - ровно то, что получат пользователи (с разметкой Markdown)
+	b.sendMessage(message.Chat.ID, text, 0)
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, tr.N("admin.broadcast_confirm", len(recipients)))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("admin.button_send"), "admin:broadcast:"),
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("button.cancel"), "admin:cancel:"),
+		),
+	)
+	if _, err := b.send(msg); err != nil {
+		logger.Error("Error sending broadcast confirmation", "err", err)
+	}
+}
+
+// handleAdminCallback - подтверждение или отмена рассылки
+func (b *Bot) handleAdminCallback(query *tgbotapi.CallbackQuery, action string) {
+	adminID := int64(query.From.ID)
+	tr := b.tr(adminID)
+	if !b.cfg.IsAdmin(adminID) {
+		b.answerCallback(query, "")
+		return
+	}
+
+	text, ok := b.pendingBroadcasts[adminID]
+	if !ok {
+		b.answerCallback(query, tr.T("admin.broadcast_expired"))
+		return
+	}
+	delete(b.pendingBroadcasts, adminID)
+
+	if action != "broadcast" {
+		b.answerCallback(query, "")
+		b.editMessageText(query.Message, tr.T("admin.broadcast_cancelled"))
+		return
+	}
+
+	recipients, err := b.db.GetBroadcastRecipients()
+	if err != nil {
+		logger.Error("Error getting broadcast recipients", "err", err)
+		b.answerCallback(query, tr.T("error.retry"))
+		return
+	}
+	queued := 0
+	for _, userID := range recipients {
+		msg := tgbotapi.NewMessage(userID, text)
+		msg.ParseMode = tgbotapi.ModeMarkdown
+		if b.enqueue(models.OutboxBroadcast, 0, msg) {
+			queued++
+		}
+	}
+	b.auditAdmin(adminID, "broadcast", 0, fmt.Sprintf("recipients=%d text=%q", queued, text))
+
+	b.answerCallback(query, "")
+	b.editMessageText(query.Message, tr.N("admin.broadcast_queued", queued))
+}
+
+// handleUserCommand показывает данные пользователя для поддержки: /user <id>
+func (b *Bot) handleUserCommand(message *tgbotapi.Message) {
+	adminID := int64(message.From.ID)
+	tr := b.tr(adminID)
+
+	userID, ok := commandUserID(message)
+	if !ok {
+		b.sendMessage(message.Chat.ID, tr.T("admin.user_usage"), message.MessageID)
+		return
+	}
+	user, err := b.db.GetUser(userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		b.sendMessage(message.Chat.ID, tr.T("admin.user_not_found", userID), message.MessageID)
+		return
+	}
+	if err != nil {
+		logger.Error("handleUserCommand: Ошибка при получении пользователя", "user_id", userID, "err", err)
+		b.sendMessage(message.Chat.ID, tr.T("error.generic"), message.MessageID)
+		return
+	}
+
+	credits, err := b.db.GetCreditsByUser(userID)
+	if err != nil {
+		logger.Error("handleUserCommand: Ошибка при получении кредитов", "user_id", userID, "err", err)
+	}
+	debts, err := b.db.GetOpenDebtsByUser(userID)
+	if err != nil {
+		logger.Error("handleUserCommand: Ошибка при получении долгов", "user_id", userID, "err", err)
+	}
+	payments, err := b.db.CountPaymentsByUser(userID)
+	if err != nil {
+		logger.Error("handleUserCommand: Ошибка при получении платежей", "user_id", userID, "err", err)
+	}
+	b.auditAdmin(adminID, "user", userID, "")
+
+	status := tr.T("admin.status_active")
+	switch {
+	case user.Banned:
+		status = tr.T("admin.status_banned")
+	case user.Blocked:
+		status = tr.T("admin.status_blocked")
+	}
+	lastSeen := tr.T("admin.never")
+	if !user.LastSeenAt.IsZero() {
+		lastSeen = user.LastSeenAt.In(time.Local).Format("02.01.2006 15:04")
+	}
+	timezone := user.Timezone
+	if timezone == "" {
+		timezone = tr.T("admin.server_timezone")
+	}
+
+	var text strings.Builder
+	text.WriteString(tr.T("admin.user_info", user.ID, user.CreatedAt.In(time.Local).Format("02.01.2006"), lastSeen,
+		user.Lang(), utils.EscapeMarkdown(timezone), status, len(debts), payments))
+	text.WriteString(tr.T("admin.user_credits", len(credits)))
+	for _, credit := range credits {
+		text.WriteString(tr.T("admin.user_credit", credit.ID, utils.EscapeMarkdown(banks.Canonical(credit.BankID, credit.BankName)),
+			utils.FormatMoney(credit.LoanAmount), credit.DueDate.Format("02.01.2006")))
+	}
+	b.sendMessage(message.Chat.ID, text.String(), message.MessageID)
+}
+
+// handleBanCommand закрывает или открывает пользователю доступ к боту: /ban <id>, /unban <id>
+func (b *Bot) handleBanCommand(message *tgbotapi.Message, banned bool) {
+	adminID := int64(message.From.ID)
+	tr := b.tr(adminID)
+	action := "ban"
+	if !banned {
+		action = "unban"
+	}
+
+	userID, ok := commandUserID(message)
+	if !ok {
+		b.sendMessage(message.Chat.ID, tr.T("admin."+action+"_usage"), message.MessageID)
+		return
+	}
+	if banned && b.cfg.IsAdmin(userID) {
+		b.sendMessage(message.Chat.ID, tr.T("admin.ban_admin"), message.MessageID)
+		return
+	}
+	if _, err := b.db.GetUser(userID); errors.Is(err, sql.ErrNoRows) {
+		b.sendMessage(message.Chat.ID, tr.T("admin.user_not_found", userID), message.MessageID)
+		return
+	}
+	if err := b.db.SetUserBanned(userID, banned); err != nil {
+		logger.Error("Error changing ban", "user_id", userID, "banned", banned, "err", err)
+		b.sendMessage(message.Chat.ID, tr.T("error.generic"), message.MessageID)
+		return
+	}
+	if banned {
+		// Незавершенный диалог забаненного пользователя больше не продолжится
+		delete(b.state, userID)
+		delete(b.inputData, userID)
+	}
+	b.auditAdmin(adminID, action, userID, "")
+	b.sendMessage(message.Chat.ID, tr.T("admin."+action+"_done", userID), message.MessageID)
+}
+
+// handleJobsCommand показывает состояние периодических задач
+func (b *Bot) handleJobsCommand(message *tgbotapi.Message) {
+	adminID := int64(message.From.ID)
+	tr := b.tr(adminID)
+	b.auditAdmin(adminID, "jobs", 0, "")
+
+	jobs := b.jobs.Jobs()
+	if len(jobs) == 0 {
+		b.sendMessage(message.Chat.ID, tr.T("admin.jobs_empty"), message.MessageID)
+		return
+	}
+
+	lines := []string{tr.T("admin.jobs_title")}
+	for _, job := range jobs {
+		lastRun := tr.T("admin.never")
+		if !job.LastRun.IsZero() {
+			lastRun = job.LastRun.Format("02.01 15:04:05")
+		}
+		line := tr.T("admin.job", utils.EscapeMarkdown(job.Name), lastRun, job.NextRun.Format("02.01 15:04:05"))
+		if job.Running {
+			line += tr.T("admin.job_running")
+		}
+		lines = append(lines, line)
+	}
+	b.sendMessage(message.Chat.ID, strings.Join(lines, "\n"), message.MessageID)
+}
+
+// commandUserID разбирает Telegram ID пользователя из аргументов команды
+func commandUserID(message *tgbotapi.Message) (int64, bool) {
+	userID, err := strconv.ParseInt(strings.TrimSpace(message.CommandArguments()), 10, 64)
+	return userID, err == nil && userID > 0
+}
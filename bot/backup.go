@@ -0,0 +1,37 @@
+package bot
+
+import (
+	"os"
+	"path/filepath"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// Больше Telegram не примет от бота в одном документе
+const maxDocumentSize = 50 << 20
+
+// handleBackupCommand создает резервную копию базы и присылает файл администратору
+func (b *Bot) handleBackupCommand(message *tgbotapi.Message) {
+	adminID := int64(message.From.ID)
+	tr := b.tr(adminID)
+
+	path, err := b.backups.Create()
+	if err != nil {
+		logger.Error("handleBackupCommand: Ошибка при создании резервной копии", "err", err)
+		b.sendMessage(message.Chat.ID, tr.T("admin.backup_error"), message.MessageID)
+		return
+	}
+	b.auditAdmin(adminID, "backup", 0, path)
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		logger.Error("handleBackupCommand: Ошибка при чтении резервной копии", "path", path, "err", err)
+		b.sendMessage(message.Chat.ID, tr.T("admin.backup_error"), message.MessageID)
+		return
+	}
+	if len(content) > maxDocumentSize {
+		b.sendMessage(message.Chat.ID, tr.T("admin.backup_too_large", path), message.MessageID)
+		return
+	}
+	b.sendDocument(message.Chat.ID, filepath.Base(path), content)
+}
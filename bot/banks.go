@@ -0,0 +1,56 @@
+package bot
+
+import (
+	"DebtBot/banks"
+	"DebtBot/utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// sendBankSuggestions предлагает банки из справочника вместо введенного названия
+// или оставить название как есть
+func (b *Bot) sendBankSuggestions(message *tgbotapi.Message, suggestions []*banks.Bank) {
+	userID := int64(message.From.ID)
+	tr := b.tr(userID)
+	typed := b.inputData[userID]["bank_typed"]
+
+	rows := [][]tgbotapi.InlineKeyboardButton{}
+	for _, bank := range suggestions {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(bank.Emoji+" "+bank.Name, "bank:pick:"+bank.ID),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(tr.T("bank.button_keep", typed), "bank:keep:"),
+	))
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, tr.T("bank.suggest"))
+	msg.ReplyToMessageID = message.MessageID
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	if _, err := b.send(msg); err != nil {
+		logger.Error("Error sending bank suggestions", "err", err)
+	}
+}
+
+// handleBankCallback - выбор банка из предложенных или введенное название как есть
+func (b *Bot) handleBankCallback(query *tgbotapi.CallbackQuery, action, bankID string) {
+	userID := int64(query.From.ID)
+	tr := b.tr(userID)
+	data := b.inputData[userID]
+	if b.state[userID] != "waiting_bank_name" || data["bank_typed"] == "" {
+		b.answerCallback(query, tr.T("bank.expired"))
+		return
+	}
+
+	if bank, ok := banks.ByID(bankID); ok && action == "pick" {
+		data["bank_name"] = bank.Name
+		data["bank_id"] = bank.ID
+	} else {
+		data["bank_name"] = data["bank_typed"]
+	}
+	delete(data, "bank_typed")
+	logger.Info("Пользователь выбрал банк", "user_id", userID, "bank", data["bank_name"])
+
+	b.answerCallback(query, "")
+	b.editMessageText(query.Message, tr.T("bank.chosen", utils.EscapeMarkdown(data["bank_name"])))
+	b.askNextCreditField(query.Message, userID)
+}
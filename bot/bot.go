@@ -1,153 +1,328 @@
 package bot
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
-	"log"
 	"strconv"
 	"strings"
 	"time"
 
+	"DebtBot/backup"
+	"DebtBot/banks"
 	"DebtBot/config"
 	"DebtBot/db"
+	"DebtBot/i18n"
+	"DebtBot/intent"
+	"DebtBot/metrics"
 	"DebtBot/models"
+	"DebtBot/outbox"
+	"DebtBot/schedule"
+	"DebtBot/scheduler"
+	"DebtBot/utils"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
 )
 
 type Bot struct {
+	cfg       *config.Config
 	botAPI    *tgbotapi.BotAPI
 	db        *db.DB
 	state     map[int64]string            // Состояние для каждого пользователя (для обработки ввода)
 	inputData map[int64]map[string]string // Временные данные ввода для каждого пользователя
+
+	pendingImports  map[int64][]*models.Credit // Разобранные из файла кредиты, ждущие подтверждения импорта
+	pendingPayments map[int64]*models.Payment  // Платежи из сообщений своими словами, ждущие подтверждения
+
+	intents intent.Parser  // Распознавание сообщений вне диалогов
+	outbox  *outbox.Sender // Очередь уведомлений, отправляемых по расписанию
+
+	jobs              *scheduler.Scheduler // Периодические задачи, для /jobs
+	backups           *backup.Service      // Резервные копии базы, для /backup
+	pendingBroadcasts map[int64]string     // Тексты рассылок, ждущие подтверждения администратора
+
+	webhookUpdates chan tgbotapi.Update // Обновления, принятые вебхуком (режим webhook)
 }
 
-func NewBot(cfg *config.Config, database *db.DB) (*Bot, error) {
+func NewBot(cfg *config.Config, database *db.DB, jobs *scheduler.Scheduler, backups *backup.Service) (*Bot, error) {
 	botAPI, err := tgbotapi.NewBotAPI(cfg.BotToken)
 	if err != nil {
 		return nil, fmt.Errorf("error creating bot API: %w", err)
 	}
 
 	return &Bot{
+		cfg:       cfg,
 		botAPI:    botAPI,
 		db:        database,
 		state:     make(map[int64]string),
 		inputData: make(map[int64]map[string]string),
+
+		pendingImports:  make(map[int64][]*models.Credit),
+		pendingPayments: make(map[int64]*models.Payment),
+
+		intents: intent.NewRules(),
+		outbox:  outbox.New(botAPI, database),
+
+		jobs:              jobs,
+		backups:           backups,
+		pendingBroadcasts: make(map[int64]string),
+
+		webhookUpdates: make(chan tgbotapi.Update, 100),
 	}, nil
 }
 
 func (b *Bot) Start() error {
-	u := tgbotapi.NewUpdate(0)
-	u.Timeout = 60
-
-	updates, err := b.botAPI.GetUpdatesChan(u)
+	updates, err := b.updatesChannel()
 	if err != nil {
 		return fmt.Errorf("error getting updates channel: %w", err)
 	}
 
-	log.Println("Начинаем обработку обновлений...")
+	go b.outbox.Run()
+	logger.Info("Начинаем обработку обновлений", "mode", b.cfg.Mode)
 
 	for update := range updates {
-		log.Println("Получено обновление:", update)
+		started := time.Now()
+		b.handleUpdate(update)
+		observeUpdate(update, time.Since(started))
+		metrics.SetActiveDialogs(len(b.state))
+	}
+	return nil
+}
+
+// handleUpdate обрабатывает одно обновление: сообщение, команду или нажатие кнопки
+func (b *Bot) handleUpdate(update tgbotapi.Update) {
+	requestLog := updateLogger(update)
+	requestLog.Debug("Получено обновление", updateDetails(update)...)
 
-		if update.Message != nil { // Handle messages
-			log.Println("Обновление содержит сообщение:", update.Message)
+	if update.Message != nil { // Handle messages
 
-			userID := int64(update.Message.From.ID)
-			b.db.CreateUserIfNotExist(userID) // Ensure user exists in DB
+		// Групповые чаты обрабатываются отдельно и не затрагивают личные кредиты
+		if !update.Message.Chat.IsPrivate() {
+			// В закрытом режиме посторонние не попадают в базу и через группу
+			if from := update.Message.From; from != nil && !from.IsBot {
+				if !b.hasAccess(int64(from.ID)) {
+					b.handleGroupNoAccess(update.Message)
+					return
+				}
+				// Забаненный не пользуется и групповыми командами; в чат отвечаем только на команды
+				if b.isBanned(int64(from.ID)) {
+					requestLog.Info("Сообщение забаненного пользователя в группе, игнорируем")
+					if update.Message.IsCommand() {
+						b.sendMessage(update.Message.Chat.ID, b.tr(int64(from.ID)).T("admin.banned_notice"), update.Message.MessageID)
+					}
+					return
+				}
+			}
+			b.handleGroupMessage(update.Message)
+			return
+		}
+
+		userID := int64(update.Message.From.ID)
+		// В закрытом режиме посторонние не попадают в базу
+		if !b.hasAccess(userID) {
+			b.handleNoAccess(update.Message)
+			return
+		}
+		b.db.CreateUserIfNotExist(userID) // Ensure user exists in DB
+		if err := b.db.SetTelegramLanguage(userID, update.Message.From.LanguageCode); err != nil {
+			requestLog.Error("Error saving language", "err", err)
+		}
+		if b.isBanned(userID) {
+			requestLog.Info("Сообщение забаненного пользователя, игнорируем")
+			b.sendMessage(update.Message.Chat.ID, b.tr(userID).T("admin.banned_notice"), 0)
+			return
+		}
+		// Написавший пользователь снова получает уведомления, даже если раньше блокировал бота
+		if err := b.db.MarkUserSeen(userID, time.Now()); err != nil {
+			requestLog.Error("Error marking user as seen", "err", err)
+		}
 
-			command := update.Message.Command()
-			text := update.Message.Text
+		command := update.Message.Command()
+		text := update.Message.Text
 
-			log.Printf("Команда: '%s', Текст: '%s'", command, text)
+		// Присланный файл - импорт кредитов
+		if update.Message.Document != nil {
+			requestLog.Info("Получен документ, запускаем импорт", "file", update.Message.Document.FileName)
+			b.handleImportDocument(update.Message)
+			return
+		}
 
-			switch command {
-			case "start", "help":
-				log.Println("Команда: /start или /help")
+		switch command {
+		case "start", "help":
+			if token, ok := debtTokenFromStart(update.Message.CommandArguments()); ok {
+				b.handleDebtInvite(update.Message, token)
+			} else {
 				b.handleHelpCommand(update.Message)
-			case "addcredit":
-				log.Println("Команда: /addcredit")
+			}
+		case "addcredit":
+			b.handleAddCreditCommand(update.Message)
+		case "mycredits":
+			b.handleMyCreditsCommand(update.Message)
+		case "deletecredit":
+			b.handleDeleteCreditCommand(update.Message)
+		case "newdebt":
+			b.handleNewDebtCommand(update.Message)
+		case "debts":
+			b.handleDebtsCommand(update.Message)
+		case "export":
+			b.handleExportCommand(update.Message)
+		case "import":
+			b.handleImportCommand(update.Message)
+		case "calendar":
+			b.handleCalendarCommand(update.Message)
+		case "statement":
+			b.handleStatementCommand(update.Message)
+		case "charts":
+			b.handleChartsCommand(update.Message)
+		case "summary":
+			b.handleSummaryCommand(update.Message)
+		case "income":
+			b.handleIncomeCommand(update.Message)
+		case "settings":
+			b.handleSettingsCommand(update.Message)
+		case "timezone":
+			b.handleTimezoneCommand(update.Message)
+		case "language":
+			b.handleLanguageCommand(update.Message)
+		case "reminders":
+			b.handleRemindersCommand(update.Message)
+		case "trash":
+			b.handleTrashCommand(update.Message)
+		case "mydata":
+			b.handleMyDataCommand(update.Message)
+		case "deleteme":
+			b.handleDeleteMeCommand(update.Message)
+		case "stats", "broadcast", "user", "ban", "unban", "jobs", "invite", "backup":
+			// Для остальных пользователей команды администратора не существуют
+			if b.cfg.IsAdmin(userID) {
+				b.handleAdminCommand(update.Message)
+			} else {
+				requestLog.Info("Команда администратора от пользователя без прав")
+			}
+		default:
+			// Check for button presses (text messages from reply keyboard)
+			button, _ := i18n.Button(text)
+			switch button {
+			case i18n.ButtonAddCredit:
 				b.handleAddCreditCommand(update.Message)
-			case "mycredits":
-				log.Println("Команда: /mycredits")
+			case i18n.ButtonMyCredits:
 				b.handleMyCreditsCommand(update.Message)
-			case "deletecredit":
-				log.Println("Команда: /deletecredit")
+			case i18n.ButtonDeleteCredit:
 				b.handleDeleteCreditCommand(update.Message)
+			case i18n.ButtonHelp:
+				b.handleHelpCommand(update.Message)
 			default:
-				// Check for button presses (text messages from reply keyboard)
-				switch text {
-				case "Добавить кредит":
-					log.Println("Кнопка: Добавить кредит")
-					b.handleAddCreditCommand(update.Message)
-				case "Мои кредиты":
-					log.Println("Кнопка: Мои кредиты")
-					b.handleMyCreditsCommand(update.Message)
-				case "Удалить кредит":
-					log.Println("Кнопка: Удалить кредит")
-					b.handleDeleteCreditCommand(update.Message)
-				case "Помощь":
-					log.Println("Кнопка: Помощь")
-					b.handleHelpCommand(update.Message)
-				default:
-					log.Println("Команда не распознана, проверяем состояние пользователя")
-					// Обработка ввода данных в процессе добавления кредита
-					if state, ok := b.state[userID]; ok {
-						log.Printf("Состояние пользователя %d найдено: %s, вызов handleInputData", userID, state)
-						b.handleInputData(update.Message, state)
-					} else if !strings.HasPrefix(text, "/") { // Ignore non-command messages after command flow
-						log.Println("Состояние не найдено и это не команда, отправляем 'Неизвестная команда'")
-
-					} else {
-						log.Println("Состояние не найдено, но это команда (начинается с /), игнорируем")
-					}
+				// Обработка ввода данных в процессе добавления кредита
+				if state, ok := b.state[userID]; ok {
+					requestLog.Debug("Ввод в диалоге", "state", state)
+					b.handleInputData(update.Message, state)
+				} else if text != "" && !strings.HasPrefix(text, "/") {
+					requestLog.Debug("Сообщение вне диалога, распознаем намерение")
+					b.handleFreeText(update.Message)
+				} else {
+					requestLog.Info("Неизвестная команда, игнорируем")
 				}
 			}
-		} else {
-			log.Println("Обновление без сообщения, пропускаем")
-			continue
 		}
+	} else if update.CallbackQuery != nil { // Handle inline button presses
+		userID := int64(update.CallbackQuery.From.ID)
+		if !b.hasAccess(userID) {
+			b.answerCallback(update.CallbackQuery, i18n.New(update.CallbackQuery.From.LanguageCode).T("access.denied"))
+			return
+		}
+		if b.isBanned(userID) {
+			b.answerCallback(update.CallbackQuery, b.tr(userID).T("admin.banned_notice"))
+			return
+		}
+		if err := b.db.MarkUserSeen(userID, time.Now()); err != nil {
+			requestLog.Error("Error marking user as seen", "err", err)
+		}
+		b.handleCallbackQuery(update.CallbackQuery)
+	} else {
+		requestLog.Debug("Обновление без сообщения, пропускаем")
 	}
-	return nil
 }
 
-// handleCallbackQuery больше не нужен, т.к. используем ReplyKeyboard
+// handleCallbackQuery обрабатывает inline-кнопки. Данные кнопки имеют вид "<раздел>:<действие>:<аргумент>"
+func (b *Bot) handleCallbackQuery(query *tgbotapi.CallbackQuery) {
+	if query.Message == nil {
+		b.answerCallback(query, "")
+		return
+	}
+
+	parts := strings.SplitN(query.Data, ":", 3)
+	for len(parts) < 3 {
+		parts = append(parts, "")
+	}
+
+	switch parts[0] {
+	case "debt":
+		b.handleDebtCallback(query, parts[1], parts[2])
+	case "import":
+		b.handleImportCallback(query, parts[1])
+	case "credit":
+		b.handleCreditCallback(query, parts[1])
+	case "intent":
+		b.handleIntentCallback(query, parts[1])
+	case "bank":
+		b.handleBankCallback(query, parts[1], parts[2])
+	case "remind":
+		b.handleReminderCallback(query, parts[1], parts[2])
+	case "settings":
+		b.handleSettingsCallback(query, parts[1])
+	case "language":
+		b.handleLanguageCallback(query, parts[1])
+	case "admin":
+		b.handleAdminCallback(query, parts[1])
+	case "account":
+		b.handleAccountCallback(query, parts[1])
+	case "trash":
+		b.handleTrashCallback(query, parts[1], parts[2])
+	default:
+		logger.Warn("Неизвестная кнопка", "user_id", query.From.ID, "callback", callbackName(query.Data))
+		b.answerCallback(query, "")
+	}
+}
 
 func (b *Bot) handleHelpCommand(message *tgbotapi.Message) {
-	helpText := `
-Привет! Я бот для учета твоих кредитов.
+	userID := int64(message.From.ID)
+	tr := b.tr(userID)
 
-Выберите действие:`
+	text := tr.T("help.text")
+	if b.cfg.IsAdmin(userID) {
+		text += "\n\n" + tr.T("admin.help")
+	}
+	msg := tgbotapi.NewMessage(message.Chat.ID, text)
+	msg.ReplyMarkup = mainKeyboard(tr)
+	msg.ParseMode = tgbotapi.ModeMarkdown
+	_, err := b.send(msg)
+	if err != nil {
+		logger.Error("Error sending message with buttons", "err", err)
+	}
+}
 
-	// Create ReplyKeyboardMarkup
+// mainKeyboard - reply-клавиатура с основными действиями на языке пользователя
+func mainKeyboard(tr *i18n.Localizer) tgbotapi.ReplyKeyboardMarkup {
 	keyboard := tgbotapi.NewReplyKeyboard(
 		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("➕ Добавить кредит"),
-			tgbotapi.NewKeyboardButton("💶 Мои кредиты"),
+			tgbotapi.NewKeyboardButton(tr.T(i18n.ButtonAddCredit)),
+			tgbotapi.NewKeyboardButton(tr.T(i18n.ButtonMyCredits)),
 		),
 		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("➖ Удалить кредит"),
-			tgbotapi.NewKeyboardButton("🆘 Помощь"),
+			tgbotapi.NewKeyboardButton(tr.T(i18n.ButtonDeleteCredit)),
+			tgbotapi.NewKeyboardButton(tr.T(i18n.ButtonHelp)),
 		),
 	)
 	keyboard.ResizeKeyboard = true // Optional: make keyboard smaller
-
-	msg := tgbotapi.NewMessage(message.Chat.ID, helpText)
-	msg.ReplyMarkup = keyboard
-	msg.ParseMode = tgbotapi.ModeMarkdown
-	_, err := b.botAPI.Send(msg)
-	if err != nil {
-		log.Printf("Error sending message with buttons: %v", err)
-	}
-	b.sendMessage(message.Chat.ID, helpText, message.MessageID) // Corrected sendMessage call
+	return keyboard
 }
 
 // Новая функция-обертка для handleAddCreditCommand, принимающая UserID как аргумент
 func (b *Bot) handleAddCreditCommandForCallback(message *tgbotapi.Message, userID int64) {
-	log.Printf("handleAddCreditCommandForCallback - UserID из callbackQuery.From.ID: %d", userID) // ЛОГ для новой функции
 	b.state[userID] = "waiting_bank_name"
 	b.inputData[userID] = make(map[string]string)
-	log.Printf("Состояние для пользователя %d установлено в: %s", userID, b.state[userID])
+	logger.Debug("Состояние пользователя изменено", "user_id", userID, "state", b.state[userID])
 
-	msgText := "Введите название банка:"
+	msgText := b.tr(userID).T("credit.ask_bank")
 	msg := tgbotapi.NewMessage(message.Chat.ID, msgText)
 
 	// Optionally add a cancel button if needed during input process - Inline Keyboard still possible if needed for cancel
@@ -158,9 +333,9 @@ func (b *Bot) handleAddCreditCommandForCallback(message *tgbotapi.Message, userI
 	)
 	msg.ReplyMarkup = cancelKeyboard*/
 
-	_, err := b.botAPI.Send(msg)
+	_, err := b.send(msg)
 	if err != nil {
-		log.Printf("Error sending message: %v", err)
+		logger.Error("Error sending message", "err", err)
 	}
 }
 
@@ -168,76 +343,85 @@ func (b *Bot) handleAddCreditCommandForCallback(message *tgbotapi.Message, userI
 // и использует message.From.ID как и раньше
 func (b *Bot) handleAddCreditCommand(message *tgbotapi.Message) {
 	userID := int64(message.From.ID)
-	log.Printf("handleAddCreditCommand (текстовая команда/кнопка) - UserID из message.From.ID: %d", userID) // ЛОГ для текстовой команды и кнопок
+	if args := strings.TrimSpace(message.CommandArguments()); args != "" {
+		b.handleQuickAddCredit(message, args)
+		return
+	}
 	b.state[userID] = "waiting_bank_name"
 	b.inputData[userID] = make(map[string]string)
-	log.Printf("Состояние для пользователя %d установлено в: %s", userID, b.state[userID])
+	logger.Debug("Состояние пользователя изменено", "user_id", userID, "state", b.state[userID])
 
-	msgText := "Введите название банка:"
+	msgText := b.tr(userID).T("credit.ask_bank")
 	b.sendMessage(message.Chat.ID, msgText, message.MessageID)
 }
 
 func (b *Bot) handleInputData(message *tgbotapi.Message, state string) {
 	userID := int64(message.From.ID)
 	text := message.Text
-	log.Printf("handleInputData вызвана для пользователя %d, состояние: %s, текст: %s", userID, state, text)
+	logger.Debug("handleInputData", "user_id", userID, "state", state, "text", text)
+	tr := b.tr(userID)
 
 	switch state {
 	case "waiting_bank_name":
-		b.inputData[userID]["bank_name"] = text
-		b.state[userID] = "waiting_loan_amount"
-		log.Printf("Состояние пользователя %d изменено на: %s, банк: %s", userID, b.state[userID], text)
-		b.sendMessage(message.Chat.ID, "Введите сумму кредита:", message.MessageID)
+		_, err := utils.ValidateBankName(text)
+		if err != nil {
+			b.sendMessage(message.Chat.ID, tr.T("credit.ask_bank_text"), message.MessageID)
+			return
+		}
+		if bank, ok := banks.Match(text); ok {
+			b.inputData[userID]["bank_name"] = bank.Name
+			b.inputData[userID]["bank_id"] = bank.ID
+		} else if suggestions := banks.Suggest(text, 3); len(suggestions) > 0 {
+			// Похоже на опечатку - предлагаем банки из справочника, состояние не меняется
+			b.inputData[userID]["bank_typed"] = strings.TrimSpace(text)
+			b.sendBankSuggestions(message, suggestions)
+			return
+		} else {
+			b.inputData[userID]["bank_name"] = strings.TrimSpace(text)
+		}
+		delete(b.inputData[userID], "bank_typed")
+		b.askNextCreditField(message, userID)
+		logger.Debug("Состояние пользователя изменено", "user_id", userID, "state", b.state[userID], "bank", text)
 
 	case "waiting_loan_amount":
-		_, err := strconv.ParseFloat(text, 64)
+		amount, err := utils.ParseAmount(text)
 		if err != nil {
-			b.sendMessage(message.Chat.ID, "Некорректная сумма. Введите число, например, 10000.50", message.MessageID)
+			b.sendMessage(message.Chat.ID, tr.T("error.amount"), message.MessageID)
 			return
 		}
-		b.inputData[userID]["loan_amount"] = text
-		b.state[userID] = "waiting_due_date"
-		log.Printf("Состояние пользователя %d изменено на: %s, сумма: %s", userID, b.state[userID], text)
-		b.sendMessage(message.Chat.ID, "Введите дату платежа в формате ГГГГ-ММ-ДД (например, 2024-12-31):", message.MessageID)
+		b.inputData[userID]["loan_amount"] = strconv.FormatFloat(amount, 'f', -1, 64)
+		b.askNextCreditField(message, userID)
+		logger.Debug("Состояние пользователя изменено", "user_id", userID, "state", b.state[userID], "amount", text)
 
 	case "waiting_due_date":
-		_, err := time.Parse("2006-01-02", text)
+		dueDate, err := utils.ParseDate(text)
 		if err != nil {
-			b.sendMessage(message.Chat.ID, "Некорректный формат даты. Используйте ГГГГ-ММ-ДД (например, 2024-12-31)", message.MessageID)
+			b.sendMessage(message.Chat.ID, tr.T("error.date"), message.MessageID)
 			return
 		}
-		b.inputData[userID]["due_date"] = text
+		b.inputData[userID]["due_date"] = dueDate.Format(utils.DateLayout)
+		b.askNextCreditField(message, userID)
 
-		credit := &models.Credit{
-			UserID:     userID,
-			BankName:   b.inputData[userID]["bank_name"],
-			LoanAmount: parseFloat(b.inputData[userID]["loan_amount"]),
-			DueDate:    parseDate(b.inputData[userID]["due_date"]),
-		}
+	case "waiting_credit_confirm":
+		b.sendMessage(message.Chat.ID, tr.T("credit.use_buttons"), message.MessageID)
 
-		err = b.db.AddCredit(credit)
-		if err != nil {
-			log.Printf("Error adding credit to DB: %v", err)
-			b.sendMessage(message.Chat.ID, "Ошибка при сохранении кредита. Попробуйте еще раз.", message.MessageID)
-		} else {
-			b.sendMessage(message.Chat.ID, "Кредит успешно добавлен!", message.MessageID)
-		}
+	case "waiting_income":
+		b.handleIncomeInput(message)
 
-		delete(b.state, userID)
-		delete(b.inputData, userID)
-		log.Printf("Состояние и данные пользователя %d сброшены", userID)
+	case "waiting_debt_direction", "waiting_debt_amount", "waiting_debt_description", "waiting_debt_due_date":
+		b.handleDebtInput(message, state)
 
 	case "waiting_credit_to_delete": // <--- Обработка выбора кредита для удаления
 		creditIndex, err := strconv.Atoi(text)
 		if err != nil {
-			b.sendMessage(message.Chat.ID, "Пожалуйста, введите номер кредита для удаления.", message.MessageID)
+			b.sendMessage(message.Chat.ID, tr.T("credit.delete_ask_number"), message.MessageID)
 			return
 		}
 
 		creditsToDelete, ok := b.inputData[userID]["credits_to_delete"]
 		if !ok {
-			log.Printf("Error: credits_to_delete data not found for user %d", userID)
-			b.sendMessage(message.Chat.ID, "Произошла ошибка, попробуйте еще раз.", message.MessageID)
+			logger.Error("credits_to_delete data not found", "user_id", userID)
+			b.sendMessage(message.Chat.ID, tr.T("error.generic"), message.MessageID)
 			delete(b.state, userID)
 			delete(b.inputData, userID)
 			return
@@ -245,54 +429,46 @@ func (b *Bot) handleInputData(message *tgbotapi.Message, state string) {
 
 		creditIDs := strings.Split(creditsToDelete, ",") // Assuming IDs are stored as comma-separated string
 		if creditIndex <= 0 || creditIndex > len(creditIDs) {
-			b.sendMessage(message.Chat.ID, "Неверный номер кредита. Пожалуйста, выберите номер из списка.", message.MessageID)
+			b.sendMessage(message.Chat.ID, tr.T("credit.delete_bad_number"), message.MessageID)
 			return
 		}
 
 		creditIDToDelete, err := strconv.Atoi(creditIDs[creditIndex-1]) // Get the correct credit ID
 		if err != nil {
-			log.Printf("Error converting credit ID to int: %v", err)
-			b.sendMessage(message.Chat.ID, "Произошла ошибка, попробуйте еще раз.", message.MessageID)
+			logger.Error("Error converting credit ID to int", "user_id", userID, "err", err)
+			b.sendMessage(message.Chat.ID, tr.T("error.generic"), message.MessageID)
 			delete(b.state, userID)
 			delete(b.inputData, userID)
 			return
 		}
 
-		err = b.db.DeleteCredit(creditIDToDelete) // <--- Вызов функции удаления из БД
-		if err != nil {
-			log.Printf("Error deleting credit from DB: %v", err)
-			b.sendMessage(message.Chat.ID, "Ошибка при удалении кредита. Попробуйте еще раз.", message.MessageID)
-		} else {
-			b.sendMessage(message.Chat.ID, "Кредит успешно удален!", message.MessageID)
-		}
+		b.deleteCredit(message, userID, creditIDToDelete) // Кредит попадает в корзину, удаление можно отменить
 
 		delete(b.state, userID)
 		delete(b.inputData, userID)
-		log.Printf("Состояние и данные пользователя %d сброшены после удаления кредита", userID)
+		logger.Debug("Состояние и данные пользователя сброшены после удаления кредита", "user_id", userID)
 	}
 }
 
 // НОВАЯ функция-обертка для handleMyCreditsCommand, вызываемая из CallbackQuery
 func (b *Bot) handleMyCreditsCommandForCallback(message *tgbotapi.Message, userID int64) {
-	log.Printf("handleMyCreditsCommandForCallback - UserID из callbackQuery.From.ID: %d", userID) // ЛОГ
-	credits, err := b.db.GetCreditsByUser(userID)                                                 // <-- ИСПОЛЬЗУЕМ ПЕРЕДАННЫЙ userID
+	tr := b.tr(userID)
+	credits, err := b.db.GetCreditsByUser(userID) // <-- ИСПОЛЬЗУЕМ ПЕРЕДАННЫЙ userID
 	if err != nil {
-		log.Printf("handleMyCreditsCommandForCallback: Ошибка при получении кредитов из DB: %v", err)
-		b.sendMessage(message.Chat.ID, "Ошибка при получении списка кредитов.", message.MessageID)
+		logger.Error("handleMyCreditsCommandForCallback: Ошибка при получении кредитов из DB", "user_id", userID, "err", err)
+		b.sendMessage(message.Chat.ID, tr.T("credits.load_error"), message.MessageID)
 		return
 	}
 
 	if len(credits) == 0 {
-		b.sendMessage(message.Chat.ID, "У вас пока нет добавленных кредитов. Используйте /addcredit чтобы добавить.", message.MessageID)
+		b.sendMessage(message.Chat.ID, tr.T("credits.empty"), message.MessageID)
 		return
 	}
 
-	formattedCredits := "*Ваши кредиты:*\n\n"
+	now := time.Now()
+	formattedCredits := tr.T("credits.title")
 	for _, credit := range credits {
-		formattedCredits += fmt.Sprintf("🏦 *Банк:* %s\n", credit.BankName)
-		formattedCredits += fmt.Sprintf("💰 *Сумма кредита:* %.2f ₽\n ", credit.LoanAmount)
-		formattedCredits += fmt.Sprintf("📅 *Дата платежа:* %s\n", credit.DueDate.Format("02.01.2006"))
-		formattedCredits += "---\n"
+		formattedCredits += creditItem(tr, credit, now)
 	}
 
 	b.sendMessage(message.Chat.ID, formattedCredits, message.MessageID)
@@ -301,52 +477,65 @@ func (b *Bot) handleMyCreditsCommandForCallback(message *tgbotapi.Message, userI
 
 // handleMyCreditsCommand теперь вызывается ТОЛЬКО при получении текстовой команды /mycredits
 func (b *Bot) handleMyCreditsCommand(message *tgbotapi.Message) {
-	userID := int64(message.From.ID)                                                                 // <-- UserID из message.From.ID для текстовой команды
-	log.Printf("handleMyCreditsCommand (текстовая команда) - UserID из message.From.ID: %d", userID) // ЛОГ
+	userID := int64(message.From.ID) // <-- UserID из message.From.ID для текстовой команды
+	tr := b.tr(userID)
 	credits, err := b.db.GetCreditsByUser(userID)
 	if err != nil {
-		log.Printf("handleMyCreditsCommand (текстовая команда): Ошибка при получении кредитов из DB: %v", err)
-		b.sendMessage(message.Chat.ID, "Ошибка при получении списка кредитов.", message.MessageID)
+		logger.Error("handleMyCreditsCommand: Ошибка при получении кредитов из DB", "user_id", userID, "err", err)
+		b.sendMessage(message.Chat.ID, tr.T("credits.load_error"), message.MessageID)
 		return
 	}
 
 	if len(credits) == 0 {
-		b.sendMessage(message.Chat.ID, "У вас пока нет добавленных кредитов. Используйте /addcredit чтобы добавить.", message.MessageID)
+		b.sendMessage(message.Chat.ID, tr.T("credits.empty"), message.MessageID)
 		return
 	}
 
-	formattedCredits := "*Ваши кредиты:*\n\n"
+	now := time.Now()
+	formattedCredits := tr.T("credits.title")
 	for _, credit := range credits {
-		formattedCredits += fmt.Sprintf("🏦 *Банк:* %s\n", credit.BankName)
-		formattedCredits += fmt.Sprintf("💰 *Сумма кредита:* %.2f ₽\n ", credit.LoanAmount)
-		formattedCredits += fmt.Sprintf("📅 *Дата платежа:* %s\n", credit.DueDate.Format("02.01.2006"))
-		formattedCredits += "---\n"
+		formattedCredits += creditItem(tr, credit, now)
 	}
 
 	b.sendMessage(message.Chat.ID, formattedCredits, message.MessageID)
 }
 
+// Карточка кредита в /mycredits: дата ближайшего платежа по графику, ставка и срок, если указаны
+func creditItem(tr *i18n.Localizer, credit *models.Credit, now time.Time) string {
+	text := tr.T("credits.item", credit.BankName, credit.LoanAmount, schedule.NextOrLast(credit, now).Date.Format("02.01.2006"))
+	if credit.InterestRate > 0 {
+		text += tr.T("credit.confirm_rate", credit.InterestRate)
+	}
+	if credit.TermMonths > 0 {
+		text += tr.N("credit.confirm_term", credit.TermMonths)
+	}
+	return text + tr.T("credits.item_end")
+}
+
 // Новая функция-обертка для handleDeleteCreditCommand, вызываемая из CallbackQuery
 func (b *Bot) handleDeleteCreditCommandForCallback(message *tgbotapi.Message, userID int64) {
-	log.Printf("handleDeleteCreditCommandForCallback - UserID из callbackQuery.From.ID: %d", userID) // ЛОГ
+	tr := b.tr(userID)
 	credits, err := b.db.GetCreditsByUser(userID)
 	if err != nil {
-		log.Printf("handleDeleteCreditCommandForCallback: Ошибка при получении кредитов из DB: %v", err)
-		b.sendMessage(message.Chat.ID, "Ошибка при получении списка кредитов для удаления.", message.MessageID)
+		logger.Error("handleDeleteCreditCommandForCallback: Ошибка при получении кредитов из DB", "user_id", userID, "err", err)
+		b.sendMessage(message.Chat.ID, tr.T("credits.load_error"), message.MessageID)
 		return
 	}
 
 	if len(credits) == 0 {
-		b.sendMessage(message.Chat.ID, "У вас нет кредитов для удаления. Используйте /addcredit чтобы добавить.", message.MessageID)
+		b.sendMessage(message.Chat.ID, tr.T("credit.delete_empty"), message.MessageID)
 		return
 	}
 
-	formattedCredits := "Выберите номер кредита для удаления:\n\n"
+	formattedCredits := tr.T("credit.delete_title")
 	var creditIDs []string // To store credit IDs for later deletion
 	for i, credit := range credits {
-		formattedCredits += fmt.Sprintf("%d. 🏦 *Банк:* %s, 💰 *Сумма кредита:* %.2f ₽, 📅 *Дата платежа:* %s\n", i+1, credit.BankName, credit.LoanAmount, credit.DueDate.Format("02.01.2006"))
+		formattedCredits += tr.T("credit.delete_item", i+1, credit.BankName, credit.LoanAmount, credit.DueDate.Format("02.01.2006"))
 		creditIDs = append(creditIDs, strconv.Itoa(credit.ID)) // Store credit IDs as strings
 	}
+	// Номер из списка вводится следующим сообщением
+	b.state[userID] = "waiting_credit_to_delete"
+	b.inputData[userID] = map[string]string{"credits_to_delete": strings.Join(creditIDs, ",")}
 
 	b.sendMessage(message.Chat.ID, formattedCredits, message.MessageID)
 }
@@ -354,25 +543,28 @@ func (b *Bot) handleDeleteCreditCommandForCallback(message *tgbotapi.Message, us
 // handleDeleteCreditCommand теперь вызывается ТОЛЬКО при получении текстовой команды /deletecredit
 func (b *Bot) handleDeleteCreditCommand(message *tgbotapi.Message) {
 	userID := int64(message.From.ID)
-	log.Printf("handleDeleteCreditCommand (текстовая команда) - UserID из message.From.ID: %d", userID) // ЛОГ
+	tr := b.tr(userID)
 	credits, err := b.db.GetCreditsByUser(userID)
 	if err != nil {
-		log.Printf("handleDeleteCreditCommand (текстовая команда): Ошибка при получении кредитов из DB: %v", err)
-		b.sendMessage(message.Chat.ID, "Ошибка при получении списка кредитов для удаления.", message.MessageID)
+		logger.Error("handleDeleteCreditCommand: Ошибка при получении кредитов из DB", "user_id", userID, "err", err)
+		b.sendMessage(message.Chat.ID, tr.T("credits.load_error"), message.MessageID)
 		return
 	}
 
 	if len(credits) == 0 {
-		b.sendMessage(message.Chat.ID, "У вас нет кредитов для удаления. Используйте /addcredit чтобы добавить.", message.MessageID)
+		b.sendMessage(message.Chat.ID, tr.T("credit.delete_empty"), message.MessageID)
 		return
 	}
 
-	formattedCredits := "Выберите номер кредита для удаления:\n\n"
+	formattedCredits := tr.T("credit.delete_title")
 	var creditIDs []string // To store credit IDs for later deletion
 	for i, credit := range credits {
-		formattedCredits += fmt.Sprintf("%d. 🏦 *Банк:* %s, 💰 *Сумма кредита:* %.2f ₽, 📅 *Дата платежа:* %s\n", i+1, credit.BankName, credit.LoanAmount, credit.DueDate.Format("02.01.2006"))
+		formattedCredits += tr.T("credit.delete_item", i+1, credit.BankName, credit.LoanAmount, credit.DueDate.Format("02.01.2006"))
 		creditIDs = append(creditIDs, strconv.Itoa(credit.ID)) // Store credit IDs as strings
 	}
+	// Номер из списка вводится следующим сообщением
+	b.state[userID] = "waiting_credit_to_delete"
+	b.inputData[userID] = map[string]string{"credits_to_delete": strings.Join(creditIDs, ",")}
 
 	b.sendMessage(message.Chat.ID, formattedCredits, message.MessageID)
 }
@@ -380,21 +572,32 @@ func (b *Bot) handleDeleteCreditCommand(message *tgbotapi.Message) {
 func (b *Bot) SendNotifications() {
 	credits, err := b.db.GetCreditsDueTomorrow()
 	if err != nil {
-		log.Printf("Error getting credits due tomorrow: %v", err)
+		logger.Error("Error getting credits due tomorrow", "err", err)
 		return
 	}
 
 	for _, credit := range credits {
-		user, err := b.db.GetUser(credit.UserID)
+		_, err := b.db.GetUser(credit.UserID)
 		if err != nil {
-			log.Printf("Error getting user %d: %v", credit.UserID, err)
+			logger.Error("Error getting user", "user_id", credit.UserID, "err", err)
 			continue
 		}
 
-		notificationText := fmt.Sprintf("🔔 *Напоминание о платеже по кредиту!*\n\nБанк: %s\nСумма: %.2f\nДата платежа: %s\n\nНе забудьте оплатить кредит завтра!",
-			credit.BankName, credit.LoanAmount, credit.DueDate.Format("02.01.2006"))
-		b.sendMessage(user.ID, notificationText, 0) // No reply for notifications
+		metrics.ReminderEvent(metrics.ReminderScheduled)
+		b.sendReminder(credit)
 	}
+
+	b.sendDebtNotifications()
+}
+
+// tr возвращает переводчик на язык пользователя: выбранный в /language или язык Telegram
+func (b *Bot) tr(userID int64) *i18n.Localizer {
+	user, err := b.db.GetUser(userID)
+	if err != nil {
+		logger.Error("Error getting language", "user_id", userID, "err", err)
+		return i18n.New("")
+	}
+	return i18n.New(user.Lang())
 }
 
 // Modified sendMessage function to accept replyToMessageID
@@ -404,10 +607,48 @@ func (b *Bot) sendMessage(chatID int64, text string, replyToMessageID int) {
 	if replyToMessageID != 0 {
 		msg.ReplyToMessageID = replyToMessageID // Set reply to message ID if provided
 	}
-	_, err := b.botAPI.Send(msg)
+	_, err := b.send(msg)
+	if err != nil {
+		logger.Error("Error sending message", "err", err)
+	}
+}
+
+// sendPlainMessage отправляет текст без Markdown (ссылки, пользовательский ввод)
+func (b *Bot) sendPlainMessage(chatID int64, text string) {
+	msg := tgbotapi.NewMessage(chatID, text)
+	_, err := b.send(msg)
+	if err != nil {
+		logger.Error("Error sending message", "err", err)
+	}
+}
+
+// editMessageText заменяет текст сообщения (и убирает inline-кнопки)
+func (b *Bot) editMessageText(message *tgbotapi.Message, text string) {
+	edit := tgbotapi.NewEditMessageText(message.Chat.ID, message.MessageID, text)
+	edit.ParseMode = tgbotapi.ModeMarkdown
+	_, err := b.send(edit)
+	if err != nil {
+		logger.Error("Error editing message", "err", err)
+	}
+}
+
+// answerCallback убирает "часики" с нажатой inline-кнопки, при необходимости показывая текст
+func (b *Bot) answerCallback(query *tgbotapi.CallbackQuery, text string) {
+	_, err := b.botAPI.AnswerCallbackQuery(tgbotapi.NewCallback(query.ID, text))
+	if err != nil {
+		metrics.SendFailed()
+		logger.Warn("Error answering callback query", "err", err)
+	}
+}
+
+// newToken генерирует случайный секрет для ссылок (приглашения, подписка на календарь)
+func newToken() (string, error) {
+	buf := make([]byte, 12)
+	_, err := rand.Read(buf)
 	if err != nil {
-		log.Printf("Error sending message: %v", err)
+		return "", err
 	}
+	return hex.EncodeToString(buf), nil
 }
 
 // Вспомогательные функции для парсинга
@@ -417,6 +658,6 @@ func parseFloat(s string) float64 {
 }
 
 func parseDate(s string) time.Time {
-	t, _ := time.Parse("2006-01-02", s) // Игнорируем ошибку, т.к. валидация была раньше
+	t, _ := utils.ParseDate(s) // Игнорируем ошибку, т.к. валидация была раньше
 	return t
 }
@@ -0,0 +1,60 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"DebtBot/ical"
+	"DebtBot/schedule"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// /calendar - файл .ics с предстоящими платежами и ссылка на подписку.
+// /calendar reset - выпустить новую ссылку, старая перестанет работать.
+func (b *Bot) handleCalendarCommand(message *tgbotapi.Message) {
+	userID := int64(message.From.ID)
+	tr := b.tr(userID)
+	credits, err := b.db.GetCreditsByUser(userID)
+	if err != nil {
+		logger.Error("handleCalendarCommand: Ошибка при получении кредитов из DB", "user_id", userID, "err", err)
+		b.sendMessage(message.Chat.ID, tr.T("credits.load_error"), message.MessageID)
+		return
+	}
+
+	if len(credits) == 0 {
+		b.sendMessage(message.Chat.ID, tr.T("credits.empty"), message.MessageID)
+		return
+	}
+
+	b.sendDocument(message.Chat.ID, "debtbot.ics", ical.PaymentsCalendar(tr, schedule.ForCredits(credits), time.Now()))
+
+	// Подписка возможна, только если HTTP-сервер бота доступен снаружи
+	if b.cfg.PublicURL == "" {
+		return
+	}
+
+	user, err := b.db.GetUser(userID)
+	if err != nil {
+		logger.Error("handleCalendarCommand: Ошибка при получении пользователя", "user_id", userID, "err", err)
+		return
+	}
+
+	token := user.CalendarToken
+	if token == "" || strings.TrimSpace(message.CommandArguments()) == "reset" {
+		token, err = newToken()
+		if err != nil {
+			logger.Error("Error generating calendar token", "err", err)
+			return
+		}
+		err = b.db.SetCalendarToken(userID, token)
+		if err != nil {
+			logger.Error("Error saving calendar token", "user_id", userID, "err", err)
+			return
+		}
+	}
+
+	link := fmt.Sprintf("%s/calendar/%s.ics", strings.TrimRight(b.cfg.PublicURL, "/"), token)
+	b.sendMessage(message.Chat.ID, tr.T("calendar.subscribe"), 0)
+	b.sendPlainMessage(message.Chat.ID, link)
+}
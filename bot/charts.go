@@ -0,0 +1,56 @@
+package bot
+
+import (
+	"time"
+
+	"DebtBot/charts"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// На сколько месяцев вперед строить графики
+const chartMonths = 12
+
+// /charts - графики остатка долга, платежей по месяцам и долей банков
+func (b *Bot) handleChartsCommand(message *tgbotapi.Message) {
+	userID := int64(message.From.ID)
+	tr := b.tr(userID)
+	credits, err := b.db.GetCreditsByUser(userID)
+	if err != nil {
+		logger.Error("handleChartsCommand: Ошибка при получении кредитов из DB", "err", err)
+		b.sendMessage(message.Chat.ID, tr.T("credits.load_error"), message.MessageID)
+		return
+	}
+
+	if len(credits) == 0 {
+		b.sendMessage(message.Chat.ID, tr.T("credits.empty"), message.MessageID)
+		return
+	}
+
+	now := time.Now()
+	images := []struct {
+		name   string
+		render func() ([]byte, error)
+	}{
+		{"balance.png", func() ([]byte, error) { return charts.RemainingBalance(tr, credits, now, chartMonths) }},
+		{"monthly.png", func() ([]byte, error) { return charts.MonthlyLoad(tr, credits, now, chartMonths) }},
+		{"banks.png", func() ([]byte, error) { return charts.BankShare(tr, credits) }},
+	}
+
+	for _, image := range images {
+		content, err := image.render()
+		if err != nil {
+			logger.Error("handleChartsCommand: Ошибка при построении графика", "chart", image.name, "err", err)
+			continue
+		}
+		b.sendPhoto(message.Chat.ID, image.name, content)
+	}
+}
+
+// sendPhoto отправляет изображение из памяти
+func (b *Bot) sendPhoto(chatID int64, name string, content []byte) {
+	photo := tgbotapi.NewPhotoUpload(chatID, tgbotapi.FileBytes{Name: name, Bytes: content})
+	_, err := b.send(photo)
+	if err != nil {
+		logger.Error("Error sending photo", "chart", name, "err", err)
+	}
+}
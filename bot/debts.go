@@ -0,0 +1,380 @@
+package bot
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"DebtBot/i18n"
+	"DebtBot/models"
+	"DebtBot/utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// Префикс параметра /start для приглашений подтвердить долг
+const debtStartPrefix = "debt_"
+
+// /newdebt - начало диалога создания долга между двумя людьми
+func (b *Bot) handleNewDebtCommand(message *tgbotapi.Message) {
+	userID := int64(message.From.ID)
+	b.state[userID] = "waiting_debt_direction"
+	b.inputData[userID] = make(map[string]string)
+	logger.Debug("Состояние пользователя изменено", "user_id", userID, "state", b.state[userID])
+	tr := b.tr(userID)
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, tr.T("debt.ask_direction"))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("debt.button_lent"), "debt:dir:lent"),
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("debt.button_borrowed"), "debt:dir:borrowed"),
+		),
+	)
+	_, err := b.send(msg)
+	if err != nil {
+		logger.Error("Error sending message", "err", err)
+	}
+}
+
+// Шаги диалога /newdebt после выбора направления
+func (b *Bot) handleDebtInput(message *tgbotapi.Message, state string) {
+	userID := int64(message.From.ID)
+	text := message.Text
+	tr := b.tr(userID)
+
+	switch state {
+	case "waiting_debt_direction":
+		b.sendMessage(message.Chat.ID, tr.T("debt.use_buttons"), message.MessageID)
+
+	case "waiting_debt_amount":
+		amount, err := utils.ParseAmount(text)
+		if err != nil {
+			b.sendMessage(message.Chat.ID, tr.T("error.amount"), message.MessageID)
+			return
+		}
+		b.inputData[userID]["amount"] = strconv.FormatFloat(amount, 'f', -1, 64)
+		b.state[userID] = "waiting_debt_description"
+		b.sendMessage(message.Chat.ID, tr.T("debt.ask_description"), message.MessageID)
+
+	case "waiting_debt_description":
+		b.inputData[userID]["description"] = text
+		b.state[userID] = "waiting_debt_due_date"
+		b.sendMessage(message.Chat.ID, tr.T("debt.ask_due_date"), message.MessageID)
+
+	case "waiting_debt_due_date":
+		dueDate, err := utils.ParseDate(text)
+		if err != nil {
+			b.sendMessage(message.Chat.ID, tr.T("error.date"), message.MessageID)
+			return
+		}
+
+		token, err := newToken()
+		if err != nil {
+			logger.Error("Error generating debt token", "err", err)
+			b.sendMessage(message.Chat.ID, tr.T("error.generic"), message.MessageID)
+			return
+		}
+
+		debt := &models.Debt{
+			Token:       token,
+			CreatorID:   userID,
+			Amount:      parseFloat(b.inputData[userID]["amount"]),
+			Description: b.inputData[userID]["description"],
+			DueDate:     dueDate,
+			Status:      models.DebtPending,
+		}
+		if b.inputData[userID]["direction"] == "lent" {
+			debt.LenderID = userID
+		} else {
+			debt.BorrowerID = userID
+		}
+
+		delete(b.state, userID)
+		delete(b.inputData, userID)
+
+		err = b.db.AddDebt(debt)
+		if err != nil {
+			logger.Error("Error adding debt to DB", "err", err)
+			b.sendMessage(message.Chat.ID, tr.T("debt.save_error"), message.MessageID)
+			return
+		}
+
+		link := fmt.Sprintf("https://t.me/%s?start=%s%s", b.botAPI.Self.UserName, debtStartPrefix, debt.Token)
+		b.sendMessage(message.Chat.ID, tr.T("debt.created", formatDebt(tr, debt, userID)), message.MessageID)
+		// Ссылку отправляем отдельным сообщением без Markdown, чтобы ее было удобно переслать
+		b.sendPlainMessage(message.Chat.ID, link)
+	}
+}
+
+// Открытие deep link приглашения: /start debt_<token>
+func (b *Bot) handleDebtInvite(message *tgbotapi.Message, token string) {
+	userID := int64(message.From.ID)
+	tr := b.tr(userID)
+	debt, err := b.db.GetDebtByToken(token)
+	if errors.Is(err, sql.ErrNoRows) {
+		b.sendMessage(message.Chat.ID, tr.T("debt.invite_not_found"), message.MessageID)
+		return
+	}
+	if err != nil {
+		logger.Error("Error getting debt by token", "err", err)
+		b.sendMessage(message.Chat.ID, tr.T("error.generic"), message.MessageID)
+		return
+	}
+
+	if debt.CreatorID == userID {
+		b.sendMessage(message.Chat.ID, tr.T("debt.invite_own"), message.MessageID)
+		return
+	}
+	if debt.Status != models.DebtPending {
+		b.sendMessage(message.Chat.ID, tr.T("debt.invite_processed"), message.MessageID)
+		return
+	}
+
+	// Показываем долг с точки зрения второй стороны
+	preview := *debt
+	if preview.LenderID == 0 {
+		preview.LenderID = userID
+	} else {
+		preview.BorrowerID = userID
+	}
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, tr.T("debt.invite", formatDebt(tr, &preview, userID)))
+	msg.ParseMode = tgbotapi.ModeMarkdown
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("debt.button_accept"), "debt:accept:"+debt.Token),
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("debt.button_reject"), "debt:reject:"+debt.Token),
+		),
+	)
+	_, err = b.send(msg)
+	if err != nil {
+		logger.Error("Error sending message", "err", err)
+	}
+}
+
+// /debts - список непогашенных долгов с кнопками погашения
+func (b *Bot) handleDebtsCommand(message *tgbotapi.Message) {
+	userID := int64(message.From.ID)
+	tr := b.tr(userID)
+	debts, err := b.db.GetOpenDebtsByUser(userID)
+	if err != nil {
+		logger.Error("handleDebtsCommand: Ошибка при получении долгов из DB", "err", err)
+		b.sendMessage(message.Chat.ID, tr.T("debts.load_error"), message.MessageID)
+		return
+	}
+
+	if len(debts) == 0 {
+		b.sendMessage(message.Chat.ID, tr.T("debts.empty"), message.MessageID)
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, tr.T("debts.title"), message.MessageID)
+	for _, debt := range debts {
+		msg := tgbotapi.NewMessage(message.Chat.ID, formatDebt(tr, debt, userID))
+		msg.ParseMode = tgbotapi.ModeMarkdown
+		if debt.Status == models.DebtActive {
+			msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+				tgbotapi.NewInlineKeyboardRow(
+					tgbotapi.NewInlineKeyboardButtonData(tr.T("debt.button_settle"), fmt.Sprintf("debt:settle:%d", debt.ID)),
+				),
+			)
+		}
+		_, err = b.send(msg)
+		if err != nil {
+			logger.Error("Error sending message", "err", err)
+		}
+	}
+}
+
+// Обработка inline-кнопок, относящихся к долгам: debt:<action>:<arg>
+func (b *Bot) handleDebtCallback(query *tgbotapi.CallbackQuery, action, arg string) {
+	userID := int64(query.From.ID)
+	tr := b.tr(userID)
+
+	switch action {
+	case "dir":
+		if b.state[userID] != "waiting_debt_direction" {
+			b.answerCallback(query, tr.T("debt.dialog_finished"))
+			return
+		}
+		b.inputData[userID]["direction"] = arg
+		b.state[userID] = "waiting_debt_amount"
+		b.answerCallback(query, "")
+		b.sendMessage(query.Message.Chat.ID, tr.T("debt.ask_amount"), 0)
+
+	case "accept", "reject":
+		debt, err := b.db.GetDebtByToken(arg)
+		if err != nil {
+			logger.Error("Error getting debt by token", "err", err)
+			b.answerCallback(query, tr.T("debt.invite_not_found"))
+			return
+		}
+		if debt.Status != models.DebtPending || debt.CreatorID == userID {
+			b.answerCallback(query, tr.T("debt.invite_processed"))
+			return
+		}
+
+		if action == "accept" {
+			if debt.LenderID == 0 {
+				debt.LenderID = userID
+			} else {
+				debt.BorrowerID = userID
+			}
+			debt.Status = models.DebtActive
+		} else {
+			debt.Status = models.DebtRejected
+		}
+
+		err = b.db.UpdateDebt(debt, userID)
+		if err != nil {
+			logger.Error("Error updating debt", "debt_id", debt.ID, "err", err)
+			b.answerCallback(query, tr.T("error.retry"))
+			return
+		}
+
+		b.answerCallback(query, "")
+		if debt.Status == models.DebtActive {
+			b.editMessageText(query.Message, tr.T("debt.accepted", formatDebt(tr, debt, userID)))
+			creatorTr := b.tr(debt.CreatorID)
+			b.sendMessage(debt.CreatorID, creatorTr.T("debt.accepted_other", formatDebt(creatorTr, debt, debt.CreatorID)), 0)
+		} else {
+			b.editMessageText(query.Message, tr.T("debt.rejected"))
+			creatorTr := b.tr(debt.CreatorID)
+			b.sendMessage(debt.CreatorID, creatorTr.T("debt.rejected_other", formatDebt(creatorTr, debt, debt.CreatorID)), 0)
+		}
+
+	case "settle", "settle_ok", "settle_no":
+		debtID, err := strconv.Atoi(arg)
+		if err != nil {
+			b.answerCallback(query, tr.T("error.bad_button"))
+			return
+		}
+		debt, err := b.db.GetDebt(debtID)
+		if err != nil || !debt.IsParty(userID) {
+			b.answerCallback(query, tr.T("debt.not_found"))
+			return
+		}
+		b.handleDebtSettlement(query, debt, action)
+	}
+}
+
+// Погашение требует подтверждения второй стороны. Если вторая сторона удалила аккаунт,
+// подтверждать некому - долг погашается сразу.
+func (b *Bot) handleDebtSettlement(query *tgbotapi.CallbackQuery, debt *models.Debt, action string) {
+	userID := int64(query.From.ID)
+	other := debt.Counterparty(userID)
+	tr, otherTr := b.tr(userID), b.tr(other)
+
+	switch {
+	case action == "settle" && other == 0:
+		if debt.Status != models.DebtActive && debt.Status != models.DebtSettleRequested {
+			b.answerCallback(query, tr.T("debt.settle_requested_already"))
+			return
+		}
+		debt.Status = models.DebtSettled
+		debt.SettleRequestedBy = 0
+	case action == "settle":
+		if debt.Status != models.DebtActive {
+			b.answerCallback(query, tr.T("debt.settle_requested_already"))
+			return
+		}
+		debt.Status = models.DebtSettleRequested
+		debt.SettleRequestedBy = userID
+	default: // settle_ok, settle_no
+		if debt.Status != models.DebtSettleRequested || debt.SettleRequestedBy == userID {
+			b.answerCallback(query, tr.T("debt.nothing_to_confirm"))
+			return
+		}
+		if action == "settle_ok" {
+			debt.Status = models.DebtSettled
+		} else {
+			debt.Status = models.DebtActive
+			debt.SettleRequestedBy = 0
+		}
+	}
+
+	err := b.db.UpdateDebt(debt, userID)
+	if err != nil {
+		logger.Error("Error updating debt", "debt_id", debt.ID, "err", err)
+		b.answerCallback(query, tr.T("error.retry"))
+		return
+	}
+	b.answerCallback(query, "")
+
+	switch debt.Status {
+	case models.DebtSettleRequested:
+		b.editMessageText(query.Message, tr.T("debt.settle_waiting", formatDebt(tr, debt, userID)))
+		msg := tgbotapi.NewMessage(other, otherTr.T("debt.settle_confirm", formatDebt(otherTr, debt, other)))
+		msg.ParseMode = tgbotapi.ModeMarkdown
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(otherTr.T("debt.button_settle_ok"), fmt.Sprintf("debt:settle_ok:%d", debt.ID)),
+				tgbotapi.NewInlineKeyboardButtonData(otherTr.T("debt.button_settle_no"), fmt.Sprintf("debt:settle_no:%d", debt.ID)),
+			),
+		)
+		_, err = b.send(msg)
+		if err != nil {
+			logger.Error("Error sending message", "err", err)
+		}
+	case models.DebtSettled:
+		b.editMessageText(query.Message, tr.T("debt.settled", formatDebt(tr, debt, userID)))
+		if other != 0 {
+			b.sendMessage(other, otherTr.T("debt.settled_other", formatDebt(otherTr, debt, other)), 0)
+		}
+	case models.DebtActive:
+		b.editMessageText(query.Message, tr.T("debt.settle_declined", formatDebt(tr, debt, userID)))
+		b.sendMessage(other, otherTr.T("debt.settle_declined_other", formatDebt(otherTr, debt, other)), 0)
+	}
+}
+
+// Напоминания обеим сторонам о долгах с датой возврата завтра
+func (b *Bot) sendDebtNotifications() {
+	debts, err := b.db.GetDebtsDueTomorrow()
+	if err != nil {
+		logger.Error("Error getting debts due tomorrow", "err", err)
+		return
+	}
+
+	for _, debt := range debts {
+		for _, userID := range []int64{debt.LenderID, debt.BorrowerID} {
+			if userID == 0 {
+				continue // Вторая сторона удалила аккаунт
+			}
+			tr := b.tr(userID)
+			b.notify(userID, models.OutboxDebtReminder, tr.T("debt.reminder", formatDebt(tr, debt, userID)))
+		}
+	}
+}
+
+// Описание долга с точки зрения пользователя userID
+func formatDebt(tr *i18n.Localizer, debt *models.Debt, userID int64) string {
+	direction := tr.T("debt.you_owe")
+	if debt.LenderID == userID {
+		direction = tr.T("debt.owed_to_you")
+	}
+
+	text := tr.T("debt.format_amount", direction, debt.Amount)
+	if debt.Description != "" {
+		text += tr.T("debt.format_description", utils.EscapeMarkdown(debt.Description))
+	}
+	text += tr.T("debt.format_due_date", debt.DueDate.Format("02.01.2006"))
+	text += tr.T("debt.format_status", debtStatusTitle(tr, debt.Status))
+	return text
+}
+
+func debtStatusTitle(tr *i18n.Localizer, status string) string {
+	switch status {
+	case models.DebtPending, models.DebtActive, models.DebtRejected, models.DebtSettleRequested, models.DebtSettled:
+		return tr.T("debt.status." + status)
+	}
+	return status
+}
+
+// Аргумент /start с приглашением подтвердить долг
+func debtTokenFromStart(args string) (string, bool) {
+	if !strings.HasPrefix(args, debtStartPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(args, debtStartPrefix), true
+}
@@ -0,0 +1,50 @@
+package bot
+
+import (
+	"DebtBot/export"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// /export - выгрузка кредитов, графика и истории платежей в CSV и XLSX
+func (b *Bot) handleExportCommand(message *tgbotapi.Message) {
+	userID := int64(message.From.ID)
+	tr := b.tr(userID)
+	data, err := export.Load(b.db, userID)
+	if err != nil {
+		logger.Error("handleExportCommand: Ошибка при получении данных из DB", "err", err)
+		b.sendMessage(message.Chat.ID, tr.T("export.error"), message.MessageID)
+		return
+	}
+
+	if len(data.Credits) == 0 && len(data.Payments) == 0 {
+		b.sendMessage(message.Chat.ID, tr.T("export.empty"), message.MessageID)
+		return
+	}
+
+	tables := export.Tables(data)
+	workbook, err := export.XLSX(tables)
+	if err != nil {
+		logger.Error("handleExportCommand: Ошибка при формировании XLSX", "err", err)
+		b.sendMessage(message.Chat.ID, tr.T("export.error"), message.MessageID)
+		return
+	}
+	b.sendDocument(message.Chat.ID, "debtbot.xlsx", workbook)
+
+	for _, table := range tables {
+		content, err := export.CSV(table)
+		if err != nil {
+			logger.Error("handleExportCommand: Ошибка при формировании CSV", "table", table.Name, "err", err)
+			continue
+		}
+		b.sendDocument(message.Chat.ID, table.Name+".csv", content)
+	}
+}
+
+// sendDocument отправляет файл из памяти как документ Telegram
+func (b *Bot) sendDocument(chatID int64, name string, content []byte) {
+	doc := tgbotapi.NewDocumentUpload(chatID, tgbotapi.FileBytes{Name: name, Bytes: content})
+	_, err := b.send(doc)
+	if err != nil {
+		logger.Error("Error sending document", "file", name, "err", err)
+	}
+}
@@ -0,0 +1,385 @@
+package bot
+
+import (
+	"database/sql"
+	"errors"
+	"sort"
+	"strings"
+	"unicode/utf16"
+
+	"DebtBot/i18n"
+	"DebtBot/input"
+	"DebtBot/ledger"
+	"DebtBot/models"
+	"DebtBot/utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// handleGroupMessage обрабатывает все сообщения из групповых чатов.
+// Данные группы хранятся отдельно от личных кредитов участников.
+// Отвечаем на языке Telegram того, кто написал сообщение.
+func (b *Bot) handleGroupMessage(message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	tr := i18n.New("")
+	if message.From != nil {
+		tr = i18n.New(message.From.LanguageCode)
+	}
+	err := b.db.UpsertGroup(&models.Group{ID: chatID, Title: message.Chat.Title})
+	if err != nil {
+		logger.Error("Error saving group", "chat_id", chatID, "err", err)
+		return
+	}
+
+	if message.From != nil && !message.From.IsBot {
+		b.rememberGroupMember(chatID, message.From)
+	}
+
+	if message.NewChatMembers != nil {
+		for _, user := range *message.NewChatMembers {
+			if user.ID == b.botAPI.Self.ID {
+				logger.Info("Бот добавлен в группу", "chat_id", chatID, "title", message.Chat.Title)
+				b.sendMessage(chatID, tr.T("group.help"), 0)
+				continue
+			}
+			if !user.IsBot && b.hasAccess(int64(user.ID)) {
+				b.rememberGroupMember(chatID, &user)
+			}
+		}
+	}
+
+	if message.From == nil {
+		return
+	}
+
+	switch message.Command() {
+	case "start", "help":
+		b.sendMessage(chatID, tr.T("group.help"), message.MessageID)
+	case "split":
+		b.handleSplitCommand(tr, message)
+	case "paid":
+		b.handleGroupPaidCommand(tr, message)
+	case "balance":
+		b.handleGroupBalanceCommand(tr, message)
+	case "settle":
+		b.handleGroupSettleCommand(tr, message)
+	case "addcredit", "mycredits", "deletecredit":
+		b.sendMessage(chatID, tr.T("group.private_only"), message.MessageID)
+	}
+}
+
+func (b *Bot) rememberGroupMember(groupID int64, user *tgbotapi.User) {
+	err := b.db.UpsertGroupMember(&models.GroupMember{
+		GroupID:   groupID,
+		UserID:    int64(user.ID),
+		Username:  user.UserName,
+		FirstName: user.FirstName,
+	})
+	if err != nil {
+		logger.Error("Error saving group member", "user_id", user.ID, "chat_id", groupID, "err", err)
+	}
+}
+
+// /split 3000 @a @b описание
+func (b *Bot) handleSplitCommand(tr *i18n.Localizer, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	payerID := int64(message.From.ID)
+
+	args, mentioned, err := b.parseGroupCommand(tr, message)
+	if err != nil {
+		b.sendMessage(chatID, err.Error(), message.MessageID)
+		return
+	}
+
+	amount, description, err := parseGroupAmount(args)
+	if err != nil {
+		b.sendMessage(chatID, tr.T("group.split_usage"), message.MessageID)
+		return
+	}
+
+	// Без упоминаний делим на всех известных участников группы
+	participants := []int64{payerID}
+	if len(mentioned) == 0 {
+		members, err := b.db.GetGroupMembers(chatID)
+		if err != nil {
+			logger.Error("Error getting group members", "chat_id", chatID, "err", err)
+			b.sendMessage(chatID, tr.T("group.members_error"), message.MessageID)
+			return
+		}
+		for _, member := range members {
+			participants = appendUnique(participants, member.UserID)
+		}
+	} else {
+		for _, member := range mentioned {
+			participants = appendUnique(participants, member.UserID)
+		}
+	}
+
+	if len(participants) < 2 {
+		b.sendMessage(chatID, tr.T("group.split_nobody"), message.MessageID)
+		return
+	}
+
+	shares := []*models.GroupExpenseShare{}
+	for i, shareAmount := range ledger.SplitEqually(amount, len(participants)) {
+		shares = append(shares, &models.GroupExpenseShare{UserID: participants[i], Amount: shareAmount})
+	}
+
+	expense := &models.GroupExpense{
+		GroupID:     chatID,
+		PayerID:     payerID,
+		Amount:      amount,
+		Description: description,
+	}
+	err = b.db.AddGroupExpense(expense, shares)
+	if err != nil {
+		logger.Error("Error adding group expense", "chat_id", chatID, "err", err)
+		b.sendMessage(chatID, tr.T("group.split_error"), message.MessageID)
+		return
+	}
+
+	b.sendMessage(chatID, tr.N("group.split_done", len(participants), amount, amount/float64(len(participants))), message.MessageID)
+}
+
+// /paid @a 1000 - возврат долга конкретному участнику
+func (b *Bot) handleGroupPaidCommand(tr *i18n.Localizer, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	payerID := int64(message.From.ID)
+
+	args, mentioned, err := b.parseGroupCommand(tr, message)
+	if err != nil {
+		b.sendMessage(chatID, err.Error(), message.MessageID)
+		return
+	}
+
+	amount, _, err := parseGroupAmount(args)
+	if err != nil || len(mentioned) != 1 || mentioned[0].UserID == payerID {
+		b.sendMessage(chatID, tr.T("group.paid_usage"), message.MessageID)
+		return
+	}
+
+	recipient := mentioned[0]
+	expense := &models.GroupExpense{
+		GroupID:     chatID,
+		PayerID:     payerID,
+		Amount:      amount,
+		Description: tr.T("group.repayment"),
+	}
+	shares := []*models.GroupExpenseShare{{UserID: recipient.UserID, Amount: amount}}
+	err = b.db.AddGroupExpense(expense, shares)
+	if err != nil {
+		logger.Error("Error adding group repayment", "chat_id", chatID, "err", err)
+		b.sendMessage(chatID, tr.T("group.paid_error"), message.MessageID)
+		return
+	}
+
+	b.sendMessage(chatID, tr.T("group.paid_done",
+		memberName(tr, memberFromUser(chatID, message.From)), memberName(tr, recipient), amount), message.MessageID)
+}
+
+func (b *Bot) handleGroupBalanceCommand(tr *i18n.Localizer, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	balances, members, err := b.groupBalances(chatID)
+	if err != nil {
+		logger.Error("Error calculating group balances", "chat_id", chatID, "err", err)
+		b.sendMessage(chatID, tr.T("group.balance_error"), message.MessageID)
+		return
+	}
+
+	if len(balances) == 0 {
+		b.sendMessage(chatID, tr.T("group.settled"), message.MessageID)
+		return
+	}
+
+	userIDs := make([]int64, 0, len(balances))
+	for userID := range balances {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Slice(userIDs, func(i, j int) bool { return balances[userIDs[i]] > balances[userIDs[j]] })
+
+	text := tr.T("group.balance_title")
+	for _, userID := range userIDs {
+		balance := balances[userID]
+		if balance > 0 {
+			text += tr.T("group.balance_positive", memberName(tr, members[userID]), balance)
+		} else {
+			text += tr.T("group.balance_negative", memberName(tr, members[userID]), -balance)
+		}
+	}
+	b.sendMessage(chatID, text, message.MessageID)
+}
+
+func (b *Bot) handleGroupSettleCommand(tr *i18n.Localizer, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	text, err := b.groupSettlementText(tr, chatID)
+	if err != nil {
+		logger.Error("Error calculating group settlement", "chat_id", chatID, "err", err)
+		b.sendMessage(chatID, tr.T("group.settle_error"), message.MessageID)
+		return
+	}
+	if text == "" {
+		b.sendMessage(chatID, tr.T("group.settled"), message.MessageID)
+		return
+	}
+	b.sendMessage(chatID, text, message.MessageID)
+}
+
+// SendGroupReminders напоминает каждой группе с открытыми долгами, кто кому сколько должен
+func (b *Bot) SendGroupReminders() {
+	groups, err := b.db.GetGroups()
+	if err != nil {
+		logger.Error("Error getting groups", "err", err)
+		return
+	}
+
+	// Язык участников группы неизвестен - напоминание на языке по умолчанию
+	tr := i18n.New("")
+	for _, group := range groups {
+		text, err := b.groupSettlementText(tr, group.ID)
+		if err != nil {
+			logger.Error("Error calculating group settlement", "chat_id", group.ID, "err", err)
+			continue
+		}
+		if text == "" {
+			continue
+		}
+		b.notify(group.ID, models.OutboxGroupReminder, tr.T("group.reminder", text))
+	}
+}
+
+// Текст с минимальным списком переводов; пустая строка, если все в расчете
+func (b *Bot) groupSettlementText(tr *i18n.Localizer, groupID int64) (string, error) {
+	balances, members, err := b.groupBalances(groupID)
+	if err != nil {
+		return "", err
+	}
+
+	transfers := ledger.Simplify(balances)
+	if len(transfers) == 0 {
+		return "", nil
+	}
+
+	text := tr.T("group.settle_title")
+	for _, transfer := range transfers {
+		text += tr.T("group.settle_item", memberName(tr, members[transfer.From]), memberName(tr, members[transfer.To]), transfer.Amount)
+	}
+	text += tr.T("group.settle_hint")
+	return text, nil
+}
+
+func (b *Bot) groupBalances(groupID int64) (map[int64]float64, map[int64]*models.GroupMember, error) {
+	expenses, err := b.db.GetGroupExpenses(groupID)
+	if err != nil {
+		return nil, nil, err
+	}
+	shares, err := b.db.GetGroupExpenseShares(groupID)
+	if err != nil {
+		return nil, nil, err
+	}
+	members, err := b.db.GetGroupMembers(groupID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	membersByID := make(map[int64]*models.GroupMember, len(members))
+	for _, member := range members {
+		membersByID[member.UserID] = member
+	}
+	return ledger.Balances(expenses, shares), membersByID, nil
+}
+
+// parseGroupCommand вырезает из текста команду и упоминания участников.
+// Возвращает оставшийся текст и упомянутых участников группы.
+func (b *Bot) parseGroupCommand(tr *i18n.Localizer, message *tgbotapi.Message) (string, []*models.GroupMember, error) {
+	chatID := message.Chat.ID
+	text := utf16.Encode([]rune(message.Text)) // Смещения сущностей Telegram считаются в UTF-16
+	removed := make([]bool, len(text))
+	mentioned := []*models.GroupMember{}
+
+	if message.Entities != nil {
+		for _, entity := range *message.Entities {
+			if entity.Offset < 0 || entity.Offset+entity.Length > len(text) {
+				continue
+			}
+			value := string(utf16.Decode(text[entity.Offset : entity.Offset+entity.Length]))
+
+			switch entity.Type {
+			case "mention":
+				username := strings.TrimPrefix(value, "@")
+				member, err := b.db.GetGroupMemberByUsername(chatID, username)
+				if errors.Is(err, sql.ErrNoRows) {
+					return "", nil, errors.New(tr.T("group.unknown_member", username))
+				}
+				if err != nil {
+					logger.Error("Error finding group member", "username", username, "chat_id", chatID, "err", err)
+					return "", nil, errors.New(tr.T("group.member_error"))
+				}
+				mentioned = append(mentioned, member)
+			case "text_mention":
+				if entity.User == nil {
+					continue
+				}
+				b.rememberGroupMember(chatID, entity.User)
+				mentioned = append(mentioned, memberFromUser(chatID, entity.User))
+			case "bot_command":
+			default:
+				continue
+			}
+
+			for i := entity.Offset; i < entity.Offset+entity.Length; i++ {
+				removed[i] = true
+			}
+		}
+	}
+
+	rest := make([]uint16, 0, len(text))
+	for i, unit := range text {
+		if !removed[i] {
+			rest = append(rest, unit)
+		}
+	}
+	return strings.TrimSpace(string(utf16.Decode(rest))), mentioned, nil
+}
+
+// Первое слово - сумма, остальное - описание траты
+func parseGroupAmount(args string) (float64, string, error) {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		return 0, "", errors.New("empty amount")
+	}
+	amount, err := input.Amount(fields[0])
+	if err != nil {
+		return 0, "", err
+	}
+	if amount <= 0 {
+		return 0, "", errors.New("amount must be positive")
+	}
+	return amount, strings.Join(fields[1:], " "), nil
+}
+
+func memberFromUser(groupID int64, user *tgbotapi.User) *models.GroupMember {
+	return &models.GroupMember{
+		GroupID:   groupID,
+		UserID:    int64(user.ID),
+		Username:  user.UserName,
+		FirstName: user.FirstName,
+	}
+}
+
+func memberName(tr *i18n.Localizer, member *models.GroupMember) string {
+	if member == nil {
+		return tr.T("group.someone")
+	}
+	if member.Username != "" {
+		return utils.EscapeMarkdown("@" + member.Username)
+	}
+	return utils.EscapeMarkdown(member.FirstName)
+}
+
+func appendUnique(ids []int64, id int64) []int64 {
+	for _, existing := range ids {
+		if existing == id {
+			return ids
+		}
+	}
+	return append(ids, id)
+}
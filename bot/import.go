@@ -0,0 +1,158 @@
+package bot
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"DebtBot/importer"
+	"DebtBot/utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// Максимальный размер загружаемого файла
+const maxImportFileSize = 1 << 20
+
+// Сколько строк каждого вида показывать в предпросмотре
+const maxPreviewRows = 20
+
+// /import - инструкция и шаблон файла для импорта кредитов
+func (b *Bot) handleImportCommand(message *tgbotapi.Message) {
+	tr := b.tr(int64(message.From.ID))
+	b.sendMessage(message.Chat.ID, tr.T("import.help"), message.MessageID)
+	b.sendDocument(message.Chat.ID, "import_template.csv", []byte(importer.Template))
+}
+
+// Обработка присланного документа: разбор, проверка и предпросмотр перед импортом
+func (b *Bot) handleImportDocument(message *tgbotapi.Message) {
+	userID := int64(message.From.ID)
+	document := message.Document
+	tr := b.tr(userID)
+
+	ext := strings.ToLower(filepath.Ext(document.FileName))
+	if ext != ".csv" && ext != ".xlsx" {
+		b.sendMessage(message.Chat.ID, tr.T("import.bad_format"), message.MessageID)
+		return
+	}
+	if document.FileSize > maxImportFileSize {
+		b.sendMessage(message.Chat.ID, tr.T("import.too_big"), message.MessageID)
+		return
+	}
+
+	content, err := b.downloadFile(document.FileID)
+	if err != nil {
+		logger.Error("Error downloading file", "file_id", document.FileID, "err", err)
+		b.sendMessage(message.Chat.ID, tr.T("import.download_error"), message.MessageID)
+		return
+	}
+
+	var result *importer.Result
+	if ext == ".xlsx" {
+		result, err = importer.ParseXLSX(tr, content)
+	} else {
+		result, err = importer.ParseCSV(tr, content)
+	}
+	if err != nil {
+		b.sendMessage(message.Chat.ID, tr.T("import.parse_error", utils.EscapeMarkdown(err.Error())), message.MessageID)
+		return
+	}
+
+	credits := result.Valid()
+	invalid := result.Invalid()
+
+	text := tr.T("import.preview", len(result.Rows), len(credits), len(invalid))
+	for i, credit := range credits {
+		if i == maxPreviewRows {
+			text += tr.T("import.more", len(credits)-maxPreviewRows)
+			break
+		}
+		if i == 0 {
+			text += tr.T("import.valid_title")
+		}
+		text += tr.T("import.valid_row", utils.EscapeMarkdown(credit.BankName), credit.LoanAmount, credit.DueDate.Format("02.01.2006"))
+	}
+	for i, row := range invalid {
+		if i == maxPreviewRows {
+			text += tr.N("import.more_invalid", len(invalid)-maxPreviewRows)
+			break
+		}
+		if i == 0 {
+			text += tr.T("import.invalid_title")
+		}
+		text += tr.T("import.invalid_row", row.Line, strings.Join(row.Errors, "; "))
+	}
+
+	if len(credits) == 0 {
+		b.sendMessage(message.Chat.ID, text+tr.T("import.nothing"), message.MessageID)
+		return
+	}
+
+	for _, credit := range credits {
+		credit.UserID = userID
+	}
+	b.pendingImports[userID] = credits
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, text)
+	msg.ParseMode = tgbotapi.ModeMarkdown
+	msg.ReplyToMessageID = message.MessageID
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("import.button_confirm", len(credits)), "import:confirm:"),
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("button.cancel"), "import:cancel:"),
+		),
+	)
+	_, err = b.send(msg)
+	if err != nil {
+		logger.Error("Error sending message", "err", err)
+	}
+}
+
+// Подтверждение или отмена импорта из предпросмотра
+func (b *Bot) handleImportCallback(query *tgbotapi.CallbackQuery, action string) {
+	userID := int64(query.From.ID)
+	tr := b.tr(userID)
+	credits, ok := b.pendingImports[userID]
+	if !ok {
+		b.answerCallback(query, tr.T("import.finished"))
+		return
+	}
+	delete(b.pendingImports, userID)
+
+	if action != "confirm" {
+		b.answerCallback(query, "")
+		b.editMessageText(query.Message, tr.T("import.cancelled"))
+		return
+	}
+
+	err := b.db.AddCredits(credits)
+	if err != nil {
+		logger.Error("Error importing credits", "user_id", userID, "err", err)
+		b.answerCallback(query, tr.T("import.error_short"))
+		b.editMessageText(query.Message, tr.T("import.error"))
+		return
+	}
+
+	b.answerCallback(query, "")
+	b.editMessageText(query.Message, tr.N("import.done", len(credits)))
+}
+
+// downloadFile скачивает файл, присланный пользователем
+func (b *Bot) downloadFile(fileID string) ([]byte, error) {
+	url, err := b.botAPI.GetFileDirectURL(fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxImportFileSize))
+}
@@ -0,0 +1,179 @@
+package bot
+
+import (
+	"strings"
+	"time"
+
+	"DebtBot/banks"
+	"DebtBot/intent"
+	"DebtBot/models"
+	"DebtBot/schedule"
+	"DebtBot/utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// handleFreeText выполняет действие по сообщению, написанному своими словами.
+// Действия, меняющие данные, выполняются только после подтверждения кнопкой.
+func (b *Bot) handleFreeText(message *tgbotapi.Message) {
+	userID := int64(message.From.ID)
+	tr := b.tr(userID)
+
+	recognized, err := b.intents.Parse(message.Text)
+	if err != nil {
+		logger.Error("Error recognizing intent", "user_id", userID, "err", err)
+		b.sendMessage(message.Chat.ID, tr.T("error.generic"), message.MessageID)
+		return
+	}
+	logger.Info("Распознано намерение", "user_id", userID, "intent", recognized.Kind, "bank", recognized.BankName, "amount", recognized.Amount)
+
+	switch recognized.Kind {
+	case intent.Help:
+		b.handleHelpCommand(message)
+	case intent.ListCredits:
+		b.handleMyCreditsCommand(message)
+	case intent.Summary:
+		b.handleSummaryCommand(message)
+	case intent.Debts:
+		b.handleDebtsCommand(message)
+	case intent.AddCredit:
+		// Быстрое добавление само спросит недостающее и попросит подтверждение
+		b.handleQuickAddCredit(message, recognized.Args)
+	case intent.Payment:
+		b.handlePaymentIntent(message, recognized)
+	default:
+		b.sendMessage(message.Chat.ID, tr.T("intent.unknown"), message.MessageID)
+	}
+}
+
+// handlePaymentIntent находит кредит по названию банка и просит подтвердить платеж
+func (b *Bot) handlePaymentIntent(message *tgbotapi.Message, recognized intent.Intent) {
+	userID := int64(message.From.ID)
+	tr := b.tr(userID)
+
+	credits, err := b.db.GetCreditsByUser(userID)
+	if err != nil {
+		logger.Error("handlePaymentIntent: Ошибка при получении кредитов из DB", "user_id", userID, "err", err)
+		b.sendMessage(message.Chat.ID, tr.T("credits.load_error"), message.MessageID)
+		return
+	}
+	if len(credits) == 0 {
+		b.sendMessage(message.Chat.ID, tr.T("credits.empty"), message.MessageID)
+		return
+	}
+
+	credit := matchCreditByBank(credits, recognized.BankName)
+	if credit == nil && recognized.BankName == "" {
+		b.sendMessage(message.Chat.ID, tr.T("intent.payment_ask_bank"), message.MessageID)
+		return
+	}
+	if credit == nil {
+		b.sendMessage(message.Chat.ID, tr.T("intent.payment_not_found", utils.EscapeMarkdown(recognized.BankName)), message.MessageID)
+		return
+	}
+
+	amount := recognized.Amount
+	if amount == 0 {
+		amount = schedule.NextOrLast(credit, time.Now()).Amount
+	}
+	b.pendingPayments[userID] = &models.Payment{UserID: userID, CreditID: credit.ID, Amount: amount}
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, tr.T("intent.payment_confirm", utils.FormatMoney(amount), utils.EscapeMarkdown(credit.BankName)))
+	msg.ParseMode = tgbotapi.ModeMarkdown
+	msg.ReplyToMessageID = message.MessageID
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("intent.button_confirm"), "intent:confirm:"),
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("button.cancel"), "intent:cancel:"),
+		),
+	)
+	if _, err := b.send(msg); err != nil {
+		logger.Error("Error sending payment confirmation", "err", err)
+	}
+}
+
+// handleIntentCallback обрабатывает подтверждение платежа, распознанного из текста
+func (b *Bot) handleIntentCallback(query *tgbotapi.CallbackQuery, action string) {
+	userID := int64(query.From.ID)
+	tr := b.tr(userID)
+	payment, ok := b.pendingPayments[userID]
+	if !ok {
+		b.answerCallback(query, tr.T("intent.expired"))
+		return
+	}
+	delete(b.pendingPayments, userID)
+
+	if action != "confirm" {
+		b.answerCallback(query, "")
+		b.editMessageText(query.Message, tr.T("intent.cancelled"))
+		return
+	}
+
+	payment.PaidAt = time.Now()
+	if err := b.db.AddPayment(payment); err != nil {
+		logger.Error("Error saving payment", "user_id", userID, "err", err)
+		b.answerCallback(query, tr.T("error.retry"))
+		return
+	}
+	b.answerCallback(query, "")
+	b.editMessageText(query.Message, tr.T("intent.payment_done", utils.FormatMoney(payment.Amount)))
+}
+
+// matchCreditByBank ищет кредит по названию банка, как его написал пользователь: "тинькофф",
+// "сбер", "сберу". Слова сравниваются по общему началу, чтобы не мешали падежи.
+// Если подходит несколько кредитов, берется первый (кредиты отсортированы по дате платежа).
+func matchCreditByBank(credits []*models.Credit, bank string) *models.Credit {
+	if bank == "" {
+		if len(credits) == 1 {
+			return credits[0]
+		}
+		return nil
+	}
+
+	// Сначала по справочнику: "tinkoff" находит кредит, записанный как "Т-Банк"
+	if known, ok := banks.Match(bank); ok {
+		for _, credit := range credits {
+			if credit.BankID == known.ID {
+				return credit
+			}
+		}
+	}
+
+	query := strings.Fields(strings.ToLower(bank))
+	for _, credit := range credits {
+		name := strings.Fields(strings.ToLower(credit.BankName))
+		if wordsMatch(query, name) {
+			return credit
+		}
+	}
+	return nil
+}
+
+// wordsMatch - каждое слово запроса похоже на какое-нибудь слово названия
+func wordsMatch(query, name []string) bool {
+	for _, q := range query {
+		found := false
+		for _, n := range name {
+			if similarWord(q, n) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// similarWord - одно слово продолжает другое или у них общее начало не короче четырех букв
+func similarWord(a, b string) bool {
+	if strings.HasPrefix(a, b) || strings.HasPrefix(b, a) {
+		return true
+	}
+	ra, rb := []rune(a), []rune(b)
+	common := 0
+	for common < len(ra) && common < len(rb) && ra[common] == rb[common] {
+		common++
+	}
+	return common >= 4
+}
@@ -0,0 +1,59 @@
+package bot
+
+import (
+	"DebtBot/i18n"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// /language - выбор языка интерфейса
+func (b *Bot) handleLanguageCommand(message *tgbotapi.Message) {
+	tr := b.tr(int64(message.From.ID))
+
+	row := []tgbotapi.InlineKeyboardButton{}
+	for _, lang := range i18n.Supported {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(i18n.New(string(lang)).T("language.name"), "language:"+string(lang)+":"))
+	}
+	row = append(row, tgbotapi.NewInlineKeyboardButtonData(tr.T("language.auto"), "language:auto:"))
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, tr.T("language.choose"))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(row)
+	_, err := b.send(msg)
+	if err != nil {
+		logger.Error("Error sending message", "err", err)
+	}
+}
+
+// Выбор языка кнопкой: language:<код>: или language:auto: - язык из настроек Telegram
+func (b *Bot) handleLanguageCallback(query *tgbotapi.CallbackQuery, code string) {
+	userID := int64(query.From.ID)
+
+	language := ""
+	if code != "auto" {
+		lang, ok := i18n.Parse(code)
+		if !ok {
+			b.answerCallback(query, "")
+			return
+		}
+		language = string(lang)
+	}
+
+	err := b.db.SetLanguage(userID, language)
+	if err != nil {
+		logger.Error("Error saving language", "user_id", userID, "err", err)
+		b.answerCallback(query, b.tr(userID).T("error.retry"))
+		return
+	}
+	b.answerCallback(query, "")
+
+	// Сообщение и клавиатура - уже на новом языке
+	tr := b.tr(userID)
+	b.editMessageText(query.Message, tr.T("language.saved", tr.T("language.name")))
+
+	msg := tgbotapi.NewMessage(query.Message.Chat.ID, tr.T("help.text"))
+	msg.ParseMode = tgbotapi.ModeMarkdown
+	msg.ReplyMarkup = mainKeyboard(tr)
+	_, err = b.send(msg)
+	if err != nil {
+		logger.Error("Error sending message with buttons", "err", err)
+	}
+}
@@ -0,0 +1,54 @@
+package bot
+
+import (
+	"log/slog"
+	"strings"
+
+	"DebtBot/logging"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+var logger = logging.For("bot")
+
+// updateLogger - журнал обработки обновления: все записи несут update_id, user_id и команду
+func updateLogger(update tgbotapi.Update) *slog.Logger {
+	attrs := []any{"update_id", update.UpdateID}
+	switch {
+	case update.Message != nil:
+		if update.Message.From != nil {
+			attrs = append(attrs, "user_id", update.Message.From.ID)
+		}
+		attrs = append(attrs, "chat_id", update.Message.Chat.ID)
+		if command := update.Message.Command(); command != "" {
+			attrs = append(attrs, "command", command)
+		}
+	case update.CallbackQuery != nil:
+		attrs = append(attrs, "user_id", update.CallbackQuery.From.ID, "callback", callbackName(update.CallbackQuery.Data))
+	}
+	return logger.With(attrs...)
+}
+
+// callbackName - раздел и действие кнопки ("debt:accept") без аргумента: в аргументе
+// бывают коды приглашений и другие секреты, которым не место в журнале
+func callbackName(data string) string {
+	parts := strings.SplitN(data, ":", 3)
+	if len(parts) > 2 {
+		parts = parts[:2]
+	}
+	return strings.Join(parts, ":")
+}
+
+// updateDetails - содержимое обновления для журнала. Текст и аргументы команды
+// скрываются, если журнал бота не в режиме debug.
+func updateDetails(update tgbotapi.Update) []any {
+	if update.Message == nil {
+		return nil
+	}
+	details := []any{"message_id", update.Message.MessageID}
+	if update.Message.IsCommand() {
+		details = append(details, "args", update.Message.CommandArguments())
+	} else if update.Message.Text != "" {
+		details = append(details, "text", update.Message.Text)
+	}
+	return details
+}
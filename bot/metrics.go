@@ -0,0 +1,68 @@
+package bot
+
+import (
+	"strings"
+	"time"
+
+	"DebtBot/metrics"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// Команды, которые попадают в метрики под своим именем. Остальные считаются как "unknown",
+// чтобы произвольный текст после "/" не порождал новые ряды метрик.
+var knownCommands = map[string]bool{
+	"start": true, "help": true, "addcredit": true, "mycredits": true, "deletecredit": true,
+	"newdebt": true, "debts": true, "export": true, "import": true, "calendar": true,
+	"statement": true, "charts": true, "summary": true, "income": true, "settings": true,
+	"timezone": true, "language": true, "reminders": true, "mydata": true, "deleteme": true, "trash": true,
+	"stats": true, "broadcast": true, "user": true, "ban": true, "unban": true, "jobs": true, "invite": true, "backup": true,
+}
+
+// observeUpdate учитывает обновление в метриках: тип и время обработки
+func observeUpdate(update tgbotapi.Update, duration time.Duration) {
+	kind, command := classifyUpdate(update)
+	metrics.UpdateReceived(kind)
+	if command != "" {
+		metrics.ObserveCommand(command, duration)
+	}
+}
+
+// classifyUpdate возвращает тип обновления и метку команды для гистограммы времени обработки.
+// Нажатия inline-кнопок учитываются по разделу: "callback:credit", "callback:remind".
+func classifyUpdate(update tgbotapi.Update) (kind, command string) {
+	switch {
+	case update.Message != nil && !update.Message.Chat.IsPrivate():
+		return "group_message", ""
+	case update.Message != nil && update.Message.IsCommand():
+		command = update.Message.Command()
+		if !knownCommands[command] {
+			command = "unknown"
+		}
+		return "command", command
+	case update.Message != nil:
+		return "message", "text"
+	case update.CallbackQuery != nil:
+		section, _, _ := strings.Cut(update.CallbackQuery.Data, ":")
+		if !knownCallbacks[section] {
+			section = "unknown"
+		}
+		return "callback_query", "callback:" + section
+	default:
+		return "other", ""
+	}
+}
+
+// Разделы inline-кнопок из handleCallbackQuery
+var knownCallbacks = map[string]bool{
+	"debt": true, "import": true, "credit": true, "intent": true, "bank": true,
+	"remind": true, "settings": true, "language": true, "admin": true, "account": true, "trash": true,
+}
+
+// send отправляет запрос в Telegram и учитывает ошибки в метриках
+func (b *Bot) send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	msg, err := b.botAPI.Send(c)
+	if err != nil {
+		metrics.SendFailed()
+	}
+	return msg, err
+}
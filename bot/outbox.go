@@ -0,0 +1,83 @@
+package bot
+
+import (
+	"strings"
+	"time"
+
+	"DebtBot/banks"
+	"DebtBot/models"
+	"DebtBot/utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// Сколько последних напоминаний показывает /reminders
+const remindersShown = 10
+
+// enqueue ставит уведомление в очередь отправки; false - не удалось сохранить его в базе
+func (b *Bot) enqueue(kind string, creditID int, msg tgbotapi.MessageConfig) bool {
+	if err := b.outbox.Enqueue(kind, creditID, msg); err != nil {
+		logger.Error("Error enqueueing message", "chat_id", msg.ChatID, "kind", kind, "err", err)
+		return false
+	}
+	return true
+}
+
+// notify ставит в очередь уведомление с текстом в Markdown
+func (b *Bot) notify(chatID int64, kind, text string) {
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = tgbotapi.ModeMarkdown
+	b.enqueue(kind, 0, msg)
+}
+
+// handleRemindersCommand показывает последние напоминания о платежах и их доставку
+func (b *Bot) handleRemindersCommand(message *tgbotapi.Message) {
+	userID := int64(message.From.ID)
+	tr := b.tr(userID)
+
+	deliveries, err := b.db.GetReminderDeliveries(userID, remindersShown)
+	if err != nil {
+		logger.Error("handleRemindersCommand: Ошибка при получении напоминаний из DB", "user_id", userID, "err", err)
+		b.sendMessage(message.Chat.ID, tr.T("error.generic"), message.MessageID)
+		return
+	}
+	if len(deliveries) == 0 {
+		b.sendMessage(message.Chat.ID, tr.T("reminders.empty"), message.MessageID)
+		return
+	}
+
+	credits, err := b.db.GetCreditsByUser(userID)
+	if err != nil {
+		logger.Error("handleRemindersCommand: Ошибка при получении кредитов из DB", "user_id", userID, "err", err)
+	}
+	bankNames := make(map[int]string, len(credits))
+	for _, credit := range credits {
+		bankNames[credit.ID] = banks.Canonical(credit.BankID, credit.BankName)
+	}
+
+	location := time.Local
+	if user, err := b.db.GetUser(userID); err == nil {
+		location = user.Location()
+	}
+	lines := []string{tr.T("reminders.title")}
+	for _, delivery := range deliveries {
+		bank, ok := bankNames[delivery.CreditID]
+		if !ok {
+			bank = tr.T("reminders.deleted_credit")
+		}
+		var status string
+		switch {
+		case delivery.Status == models.OutboxSent:
+			status = tr.T("reminders.status_sent", delivery.SentAt.In(location).Format("02.01 15:04"))
+		case delivery.Status == models.OutboxBlocked:
+			status = tr.T("reminders.status_blocked")
+		case delivery.Status == models.OutboxFailed:
+			status = tr.T("reminders.status_failed")
+		case delivery.Attempts > 0:
+			status = tr.N("reminders.status_retrying", delivery.Attempts)
+		default:
+			status = tr.T("reminders.status_pending")
+		}
+		lines = append(lines, tr.T("reminders.line", delivery.CreatedAt.In(location).Format("02.01 15:04"), utils.EscapeMarkdown(bank), status))
+	}
+	b.sendMessage(message.Chat.ID, strings.Join(lines, "\n"), message.MessageID)
+}
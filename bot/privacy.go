@@ -0,0 +1,72 @@
+package bot
+
+import (
+	"DebtBot/export"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// /mydata - выгрузка всего, что бот хранит о пользователе, одним JSON-файлом
+func (b *Bot) handleMyDataCommand(message *tgbotapi.Message) {
+	userID := int64(message.From.ID)
+	tr := b.tr(userID)
+
+	data, err := export.LoadPersonal(b.db, userID)
+	if err != nil {
+		logger.Error("handleMyDataCommand: Ошибка при получении данных из DB", "user_id", userID, "err", err)
+		b.sendMessage(message.Chat.ID, tr.T("export.error"), message.MessageID)
+		return
+	}
+	content, err := export.JSON(data)
+	if err != nil {
+		logger.Error("handleMyDataCommand: Ошибка при формировании JSON", "user_id", userID, "err", err)
+		b.sendMessage(message.Chat.ID, tr.T("export.error"), message.MessageID)
+		return
+	}
+	logger.Info("Выгрузка персональных данных", "user_id", userID)
+	b.sendDocument(message.Chat.ID, "mydata.json", content)
+}
+
+// /deleteme - безвозвратное удаление аккаунта после подтверждения кнопкой
+func (b *Bot) handleDeleteMeCommand(message *tgbotapi.Message) {
+	tr := b.tr(int64(message.From.ID))
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, tr.T("account.delete_confirm"))
+	msg.ParseMode = tgbotapi.ModeMarkdown
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("account.button_delete"), "account:delete:"),
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("button.cancel"), "account:cancel:"),
+		),
+	)
+	if _, err := b.send(msg); err != nil {
+		logger.Error("Error sending account deletion confirmation", "err", err)
+	}
+}
+
+// handleAccountCallback - подтверждение или отмена удаления аккаунта
+func (b *Bot) handleAccountCallback(query *tgbotapi.CallbackQuery, action string) {
+	userID := int64(query.From.ID)
+	tr := b.tr(userID)
+
+	if action != "delete" {
+		b.answerCallback(query, "")
+		b.editMessageText(query.Message, tr.T("account.delete_cancelled"))
+		return
+	}
+
+	if err := b.db.DeleteUserData(userID); err != nil {
+		logger.Error("Error deleting user data", "user_id", userID, "err", err)
+		b.answerCallback(query, tr.T("error.retry"))
+		return
+	}
+	// Незавершенные диалоги и подтверждения тоже относятся к данным пользователя
+	delete(b.state, userID)
+	delete(b.inputData, userID)
+	delete(b.pendingImports, userID)
+	delete(b.pendingPayments, userID)
+	delete(b.pendingBroadcasts, userID)
+	logger.Info("Пользователь удалил аккаунт", "user_id", userID)
+
+	b.answerCallback(query, "")
+	b.editMessageText(query.Message, tr.T("account.deleted"))
+}
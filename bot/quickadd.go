@@ -0,0 +1,363 @@
+package bot
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"DebtBot/banks"
+	"DebtBot/i18n"
+	"DebtBot/input"
+	"DebtBot/models"
+	"DebtBot/utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// creditSpec - кредит, описанный одной строкой:
+//
+//	/addcredit Сбербанк 150000 2025-03-15 12.9% 36м
+//	/addcredit "Тинькофф Банк" 1,5 млн каждое 15 число 3г
+//
+// Нулевые поля не указаны и спрашиваются в диалоге.
+type creditSpec struct {
+	BankName     string
+	Amount       float64
+	DueDate      time.Time
+	InterestRate float64 // % годовых
+	TermMonths   int
+}
+
+var (
+	// Срок: "36м", "36 мес", "3г", "3 года", "36mo", "3y". "м" здесь - месяцы, миллионы пишутся как "млн".
+	termToken = regexp.MustCompile(`^(\d{1,3})(м|мес|месяц|месяца|месяцев|m|mo|months?|г|год|года|лет|y|yr|years?)\.?$`)
+	// Разряд суммы, записанный отдельным словом: "150 000"
+	thousandsToken = regexp.MustCompile(`^\d{3}([.,]\d{1,2})?$`)
+)
+
+// Слова, с которых начинается дата, а не название банка
+var dateStartWords = []string{"сегодня", "завтра", "послезавтра", "через", "каждое", "каждого", "today", "tomorrow", "every"}
+
+// handleQuickAddCredit добавляет кредит по строке из /addcredit: недостающие поля спрашиваются
+// в обычном диалоге, перед сохранением пользователь подтверждает разобранный кредит
+func (b *Bot) handleQuickAddCredit(message *tgbotapi.Message, args string) {
+	userID := int64(message.From.ID)
+	tr := b.tr(userID)
+
+	spec, unknown := parseCreditSpec(args, time.Now())
+	if len(unknown) > 0 {
+		logger.Info("Не удалось разобрать /addcredit", "user_id", userID, "args", unknown)
+		b.sendMessage(message.Chat.ID, tr.T("credit.quick_unknown", utils.EscapeMarkdown(strings.Join(unknown, " "))), message.MessageID)
+		return
+	}
+
+	data := map[string]string{"confirm": "1"}
+	if bank, ok := banks.Match(spec.BankName); ok {
+		data["bank_name"] = bank.Name
+		data["bank_id"] = bank.ID
+	} else if spec.BankName != "" {
+		data["bank_name"] = spec.BankName
+	}
+	if spec.Amount > 0 {
+		data["loan_amount"] = strconv.FormatFloat(spec.Amount, 'f', -1, 64)
+	}
+	if !spec.DueDate.IsZero() {
+		data["due_date"] = spec.DueDate.Format(utils.DateLayout)
+	}
+	if spec.InterestRate > 0 {
+		data["interest_rate"] = strconv.FormatFloat(spec.InterestRate, 'f', -1, 64)
+	}
+	if spec.TermMonths > 0 {
+		data["term_months"] = strconv.Itoa(spec.TermMonths)
+	}
+	b.inputData[userID] = data
+
+	// Название похоже на банк из справочника с опечаткой - сначала уточняем банк
+	if data["bank_id"] == "" && spec.BankName != "" {
+		if suggestions := banks.Suggest(spec.BankName, 3); len(suggestions) > 0 {
+			delete(data, "bank_name")
+			data["bank_typed"] = spec.BankName
+			b.state[userID] = "waiting_bank_name"
+			b.sendBankSuggestions(message, suggestions)
+			return
+		}
+	}
+	b.askNextCreditField(message, userID)
+}
+
+// askNextCreditField спрашивает первое незаполненное поле кредита, а когда заполнены все -
+// просит подтверждение (быстрое добавление) или сразу сохраняет кредит (диалог)
+func (b *Bot) askNextCreditField(message *tgbotapi.Message, userID int64) {
+	tr := b.tr(userID)
+	data := b.inputData[userID]
+
+	switch {
+	case data["bank_name"] == "":
+		b.state[userID] = "waiting_bank_name"
+		b.sendMessage(message.Chat.ID, tr.T("credit.ask_bank"), message.MessageID)
+	case data["loan_amount"] == "":
+		b.state[userID] = "waiting_loan_amount"
+		b.sendMessage(message.Chat.ID, tr.T("credit.ask_amount"), message.MessageID)
+	case data["due_date"] == "":
+		b.state[userID] = "waiting_due_date"
+		b.sendMessage(message.Chat.ID, tr.T("credit.ask_due_date"), message.MessageID)
+	case data["confirm"] != "":
+		b.state[userID] = "waiting_credit_confirm"
+		msg := tgbotapi.NewMessage(message.Chat.ID, creditConfirmText(tr, creditFromInput(userID, data)))
+		msg.ParseMode = tgbotapi.ModeMarkdown
+		msg.ReplyToMessageID = message.MessageID
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(tr.T("credit.button_save"), "credit:save:"),
+				tgbotapi.NewInlineKeyboardButtonData(tr.T("button.cancel"), "credit:cancel:"),
+			),
+		)
+		if _, err := b.send(msg); err != nil {
+			logger.Error("Error sending credit confirmation", "err", err)
+		}
+	default:
+		if err := b.saveCreditInput(userID); err != nil {
+			b.sendMessage(message.Chat.ID, tr.T("credit.save_error"), message.MessageID)
+		} else {
+			b.sendMessage(message.Chat.ID, tr.T("credit.added"), message.MessageID)
+		}
+	}
+}
+
+// handleCreditCallback обрабатывает кнопки подтверждения быстрого добавления кредита
+func (b *Bot) handleCreditCallback(query *tgbotapi.CallbackQuery, action string) {
+	userID := int64(query.From.ID)
+	tr := b.tr(userID)
+	if b.state[userID] != "waiting_credit_confirm" {
+		b.answerCallback(query, tr.T("credit.confirm_expired"))
+		return
+	}
+
+	if action != "save" {
+		delete(b.state, userID)
+		delete(b.inputData, userID)
+		b.answerCallback(query, "")
+		b.editMessageText(query.Message, tr.T("credit.cancelled"))
+		return
+	}
+
+	if err := b.saveCreditInput(userID); err != nil {
+		b.answerCallback(query, "")
+		b.editMessageText(query.Message, tr.T("credit.save_error"))
+		return
+	}
+	b.answerCallback(query, "")
+	b.editMessageText(query.Message, tr.T("credit.added"))
+}
+
+// saveCreditInput сохраняет кредит из данных диалога и сбрасывает состояние пользователя
+func (b *Bot) saveCreditInput(userID int64) error {
+	credit := creditFromInput(userID, b.inputData[userID])
+	delete(b.state, userID)
+	delete(b.inputData, userID)
+	logger.Debug("Состояние и данные пользователя сброшены", "user_id", userID)
+
+	err := b.db.AddCredit(credit)
+	if err != nil {
+		logger.Error("Error adding credit to DB", "user_id", userID, "err", err)
+	}
+	return err
+}
+
+func creditFromInput(userID int64, data map[string]string) *models.Credit {
+	termMonths, _ := strconv.Atoi(data["term_months"])
+	return &models.Credit{
+		UserID:       userID,
+		BankName:     data["bank_name"],
+		LoanAmount:   parseFloat(data["loan_amount"]),
+		DueDate:      parseDate(data["due_date"]),
+		InterestRate: parseFloat(data["interest_rate"]),
+		TermMonths:   termMonths,
+		BankID:       data["bank_id"],
+	}
+}
+
+func creditConfirmText(tr *i18n.Localizer, credit *models.Credit) string {
+	text := tr.T("credit.confirm", utils.EscapeMarkdown(credit.BankName), utils.FormatMoney(credit.LoanAmount), credit.DueDate.Format("02.01.2006"))
+	if credit.InterestRate > 0 {
+		text += tr.T("credit.confirm_rate", credit.InterestRate)
+	}
+	if credit.TermMonths > 0 {
+		text += tr.N("credit.confirm_term", credit.TermMonths)
+	}
+	return text
+}
+
+// parseCreditSpec разбирает аргументы /addcredit. Второй результат - слова, которые не удалось понять.
+func parseCreditSpec(args string, now time.Time) (creditSpec, []string) {
+	var spec creditSpec
+	tokens, quoted := tokenize(args)
+
+	// Название банка - в кавычках или все слова до первой суммы/даты
+	i := 0
+	if len(tokens) > 0 && quoted[0] {
+		spec.BankName = tokens[0]
+		i = 1
+	} else {
+		for i < len(tokens) && !startsValue(tokens[i]) {
+			i++
+		}
+		spec.BankName = strings.Join(tokens[:i], " ")
+	}
+
+	// Ставку и срок узнаем по одному слову, остальное - суммы и даты из нескольких слов
+	var rest, unknown []string
+	for k := i; k < len(tokens); k++ {
+		token := tokens[k]
+		// Дата словами разбирается целиком вместе с суммами: "через 3 года" - дата, а не срок
+		if isDateStartWord(token) {
+			if n, _, ok := matchDate(tokens[k:], now); ok {
+				rest = append(rest, tokens[k:k+n]...)
+				k += n - 1
+				continue
+			}
+		}
+		if rate, ok := parseRate(token); ok && spec.InterestRate == 0 {
+			spec.InterestRate = rate
+		} else if months, ok := parseTerm(token); ok && spec.TermMonths == 0 {
+			spec.TermMonths = months
+		} else if k+1 < len(tokens) && spec.TermMonths == 0 {
+			// Срок через пробел: "36 мес", "3 года"
+			if months, ok := parseTerm(token + tokens[k+1]); ok {
+				spec.TermMonths = months
+				k++
+			} else {
+				rest = append(rest, token)
+			}
+		} else {
+			rest = append(rest, token)
+		}
+	}
+
+	for j := 0; j < len(rest); {
+		if n, date, ok := matchDate(rest[j:], now); ok && spec.DueDate.IsZero() {
+			spec.DueDate = date
+			j += n
+			continue
+		}
+		if n, amount, ok := matchAmount(rest[j:]); ok && spec.Amount == 0 {
+			spec.Amount = amount
+			j += n
+			continue
+		}
+		unknown = append(unknown, rest[j])
+		j++
+	}
+	return spec, unknown
+}
+
+// tokenize делит строку на слова; текст в кавычках ("...", «...») - одно слово
+func tokenize(s string) ([]string, []bool) {
+	var tokens []string
+	var quoted []bool
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		switch {
+		case unicode.IsSpace(runes[i]):
+			i++
+		case runes[i] == '"' || runes[i] == '«':
+			closing := '"'
+			if runes[i] == '«' {
+				closing = '»'
+			}
+			end := i + 1
+			for end < len(runes) && runes[end] != closing {
+				end++
+			}
+			if token := strings.TrimSpace(string(runes[i+1 : end])); token != "" {
+				tokens = append(tokens, token)
+				quoted = append(quoted, true)
+			}
+			i = end + 1
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) {
+				end++
+			}
+			tokens = append(tokens, string(runes[i:end]))
+			quoted = append(quoted, false)
+			i = end
+		}
+	}
+	return tokens, quoted
+}
+
+// startsValue - слово начинает сумму, дату, ставку или срок, а не название банка
+func startsValue(token string) bool {
+	r := []rune(token)[0]
+	if unicode.IsDigit(r) {
+		return true
+	}
+	return isDateStartWord(token)
+}
+
+// isDateStartWord - слово, с которого начинается дата словами: "через", "каждое", "завтра"
+func isDateStartWord(token string) bool {
+	return slices.Contains(dateStartWords, strings.ToLower(token))
+}
+
+// parseRate разбирает ставку "12.9%" или "12,9%"
+func parseRate(token string) (float64, bool) {
+	number, ok := strings.CutSuffix(token, "%")
+	if !ok {
+		return 0, false
+	}
+	rate, err := input.Amount(number)
+	if err != nil || rate <= 0 || rate >= 1000 {
+		return 0, false
+	}
+	return rate, true
+}
+
+// parseTerm разбирает срок кредита в месяцах или годах
+func parseTerm(token string) (int, bool) {
+	m := termToken.FindStringSubmatch(strings.ToLower(token))
+	if m == nil {
+		return 0, false
+	}
+	n, _ := strconv.Atoi(m[1])
+	unit := m[2]
+	if strings.HasPrefix(unit, "г") || strings.HasPrefix(unit, "л") || strings.HasPrefix(unit, "y") {
+		n *= 12
+	}
+	if n <= 0 || n > 600 {
+		return 0, false
+	}
+	return n, true
+}
+
+// matchDate ищет дату в начале tokens, начиная с самой длинной: "каждое 15 число", "31 декабря 2025"
+func matchDate(tokens []string, now time.Time) (int, time.Time, bool) {
+	for n := min(4, len(tokens)); n > 0; n-- {
+		if date, err := input.Date(strings.Join(tokens[:n], " "), now); err == nil {
+			return n, date, true
+		}
+	}
+	return 0, time.Time{}, false
+}
+
+// matchAmount ищет сумму в начале tokens: "150000", "150 000 ₽", "1,5 млн"
+func matchAmount(tokens []string) (int, float64, bool) {
+	n := 1
+	for n < len(tokens) && thousandsToken.MatchString(tokens[n]) {
+		n++
+	}
+	// Валюта или множитель отдельным словом
+	if n < len(tokens) && !startsValue(tokens[n]) {
+		if amount, err := utils.ParseAmount(strings.Join(tokens[:n+1], " ")); err == nil {
+			return n + 1, amount, true
+		}
+	}
+	amount, err := utils.ParseAmount(strings.Join(tokens[:n], " "))
+	if err != nil {
+		return 0, 0, false
+	}
+	return n, amount, true
+}
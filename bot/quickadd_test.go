@@ -0,0 +1,135 @@
+package bot
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// Понедельник, 19 октября 2026
+var testNow = time.Date(2026, time.October, 19, 15, 30, 0, 0, time.Local)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseCreditSpec(t *testing.T) {
+	tests := []struct {
+		in   string
+		want creditSpec
+	}{
+		{"Сбербанк 150000 2025-03-15 12.9% 36м",
+			creditSpec{BankName: "Сбербанк", Amount: 150000, DueDate: date(2025, time.March, 15), InterestRate: 12.9, TermMonths: 36}},
+		{`"Тинькофф Банк" 1,5 млн каждое 15 число 3г`,
+			creditSpec{BankName: "Тинькофф Банк", Amount: 1500000, DueDate: date(2026, time.November, 15), TermMonths: 36}},
+		{"«Альфа Банк» 200 000 31.12.2026",
+			creditSpec{BankName: "Альфа Банк", Amount: 200000, DueDate: date(2026, time.December, 31)}},
+		{"Альфа Банк 200 000 31.12.2026 10,5%",
+			creditSpec{BankName: "Альфа Банк", Amount: 200000, DueDate: date(2026, time.December, 31), InterestRate: 10.5}},
+
+		// Срок через пробел и в годах
+		{"ВТБ 300000 завтра 36 мес", creditSpec{BankName: "ВТБ", Amount: 300000, DueDate: date(2026, time.October, 20), TermMonths: 36}},
+		{"ВТБ 300000 завтра 3 года", creditSpec{BankName: "ВТБ", Amount: 300000, DueDate: date(2026, time.October, 20), TermMonths: 36}},
+		{"ВТБ 300000 завтра 5 лет", creditSpec{BankName: "ВТБ", Amount: 300000, DueDate: date(2026, time.October, 20), TermMonths: 60}},
+		{"VTB 300000 tomorrow 24mo", creditSpec{BankName: "VTB", Amount: 300000, DueDate: date(2026, time.October, 20), TermMonths: 24}},
+		{"VTB 300000 tomorrow 2y", creditSpec{BankName: "VTB", Amount: 300000, DueDate: date(2026, time.October, 20), TermMonths: 24}},
+
+		// "м" после целого числа - месяцы, миллионы - "млн" или дробь с "m"
+		{"Сбер 2 млн 31.12.2026 36м", creditSpec{BankName: "Сбер", Amount: 2000000, DueDate: date(2026, time.December, 31), TermMonths: 36}},
+		{"Сбер 1.5m 31.12.2026 12м", creditSpec{BankName: "Сбер", Amount: 1500000, DueDate: date(2026, time.December, 31), TermMonths: 12}},
+		{"Сбер 36м 15к 31.12.2026", creditSpec{BankName: "Сбер", Amount: 15000, DueDate: date(2026, time.December, 31), TermMonths: 36}},
+
+		// Дата словами из нескольких слов не путается со сроком
+		{"Сбер 100000 через 3 года", creditSpec{BankName: "Сбер", Amount: 100000, DueDate: date(2029, time.October, 19)}},
+		{"Сбер 100000 через 2 недели 12 мес", creditSpec{BankName: "Сбер", Amount: 100000, DueDate: date(2026, time.November, 2), TermMonths: 12}},
+		{"Сбер через месяц 100000", creditSpec{BankName: "Сбер", Amount: 100000, DueDate: date(2026, time.November, 19)}},
+
+		// Неполные строки - недостающее спросят в диалоге
+		{"Сбербанк", creditSpec{BankName: "Сбербанк"}},
+		{"150000", creditSpec{Amount: 150000}},
+		{"", creditSpec{}},
+	}
+	for _, tt := range tests {
+		got, unknown := parseCreditSpec(tt.in, testNow)
+		if len(unknown) > 0 {
+			t.Errorf("parseCreditSpec(%q): unexpected unknown words %q", tt.in, unknown)
+		}
+		if got.BankName != tt.want.BankName || got.Amount != tt.want.Amount || !got.DueDate.Equal(tt.want.DueDate) ||
+			got.InterestRate != tt.want.InterestRate || got.TermMonths != tt.want.TermMonths {
+			t.Errorf("parseCreditSpec(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseCreditSpecUnknown(t *testing.T) {
+	tests := []struct {
+		in      string
+		unknown []string
+	}{
+		{"Сбер 100000 31.12.2026 абв", []string{"абв"}},
+		{"Сбер 100000 200000", []string{"200000"}}, // Вторая сумма
+		{"Сбер 100000 12% 15%", []string{"15%"}},   // Вторая ставка
+		{"Сбер 100000 36м 12м", []string{"12м"}},   // Второй срок
+		{"Сбер 100000 0%", []string{"0%"}},         // Ставка должна быть больше нуля
+		{"Сбер 100000 999м", []string{"999м"}},     // Срок больше 50 лет
+	}
+	for _, tt := range tests {
+		_, unknown := parseCreditSpec(tt.in, testNow)
+		if !slices.Equal(unknown, tt.unknown) {
+			t.Errorf("parseCreditSpec(%q) unknown = %q, want %q", tt.in, unknown, tt.unknown)
+		}
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		in     string
+		tokens []string
+		quoted []bool
+	}{
+		{"Сбер 100 000", []string{"Сбер", "100", "000"}, []bool{false, false, false}},
+		{`"Тинькофф Банк" 1 млн`, []string{"Тинькофф Банк", "1", "млн"}, []bool{true, false, false}},
+		{"«Альфа Банк»  5к", []string{"Альфа Банк", "5к"}, []bool{true, false}},
+		{`"  " 5к`, []string{"5к"}, []bool{false}},                    // Пустые кавычки пропускаются
+		{`"Банк без конца`, []string{"Банк без конца"}, []bool{true}}, // Незакрытая кавычка - до конца строки
+		{"", nil, nil},
+	}
+	for _, tt := range tests {
+		tokens, quoted := tokenize(tt.in)
+		if !slices.Equal(tokens, tt.tokens) || !slices.Equal(quoted, tt.quoted) {
+			t.Errorf("tokenize(%q) = %q, %v, want %q, %v", tt.in, tokens, quoted, tt.tokens, tt.quoted)
+		}
+	}
+}
+
+func TestParseTerm(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   int
+		wantOK bool
+	}{
+		{"36м", 36, true},
+		{"36М", 36, true},
+		{"36мес", 36, true},
+		{"36мес.", 36, true},
+		{"12месяцев", 12, true},
+		{"3г", 36, true},
+		{"3года", 36, true},
+		{"5лет", 60, true},
+		{"24mo", 24, true},
+		{"24months", 24, true},
+		{"2y", 24, true},
+		{"50лет", 600, true},
+		{"51год", 0, false}, // Больше 600 месяцев
+		{"0м", 0, false},
+		{"1.5м", 0, false}, // Дробное - это сумма в миллионах, а не срок
+		{"млн", 0, false},
+		{"36", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseTerm(tt.in)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parseTerm(%q) = %d, %v, want %d, %v", tt.in, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
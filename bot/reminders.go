@@ -0,0 +1,183 @@
+package bot
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"DebtBot/i18n"
+	"DebtBot/metrics"
+	"DebtBot/models"
+	"DebtBot/schedule"
+	"DebtBot/utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// На сколько откладывается напоминание кнопкой "Через 2 часа"
+const snoozeDelay = 2 * time.Hour
+
+// sendReminder ставит в очередь напоминание о платеже с кнопками действий
+func (b *Bot) sendReminder(credit *models.Credit) {
+	tr := b.tr(credit.UserID)
+	msg := tgbotapi.NewMessage(credit.UserID, reminderText(tr, credit, time.Now()))
+	msg.ParseMode = tgbotapi.ModeMarkdown
+	msg.ReplyMarkup = reminderKeyboard(tr, credit.ID)
+	if !b.enqueue(models.OutboxReminder, credit.ID, msg) {
+		metrics.ReminderEvent(metrics.ReminderFailed)
+	}
+}
+
+// SendSnoozedReminders повторно отправляет отложенные напоминания, время которых наступило
+func (b *Bot) SendSnoozedReminders() {
+	snoozes, err := b.db.GetDueSnoozes(time.Now())
+	if err != nil {
+		logger.Error("Error getting snoozed reminders", "err", err)
+		return
+	}
+
+	for _, snooze := range snoozes {
+		credit, err := b.db.GetCredit(snooze.CreditID)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			// Кредит удалили, пока напоминание было отложено
+		case err != nil:
+			logger.Error("Error getting credit for snoozed reminder", "credit_id", snooze.CreditID, "err", err)
+			continue
+		case !credit.Muted:
+			b.sendReminder(credit)
+		}
+
+		if err := b.db.DeleteSnooze(snooze.ID); err != nil {
+			logger.Error("Error deleting snooze", "snooze_id", snooze.ID, "err", err)
+		}
+	}
+}
+
+// Кнопки под напоминанием: remind:<действие>:<ID кредита>
+func (b *Bot) handleReminderCallback(query *tgbotapi.CallbackQuery, action, arg string) {
+	userID := int64(query.From.ID)
+	tr := b.tr(userID)
+	creditID, err := strconv.Atoi(arg)
+	if err != nil {
+		b.answerCallback(query, "")
+		return
+	}
+
+	credit, err := b.db.GetCredit(creditID)
+	if err != nil || credit.UserID != userID {
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			logger.Error("Error getting credit", "user_id", userID, "credit_id", creditID, "err", err)
+		}
+		b.answerCallback(query, tr.T("reminder.credit_not_found"))
+		b.editMessageText(query.Message, tr.T("reminder.credit_deleted"))
+		return
+	}
+
+	now := time.Now()
+	location := time.Local
+	if user, err := b.db.GetUser(userID); err == nil {
+		location = user.Location()
+	}
+
+	var status string
+	keyboard := (*tgbotapi.InlineKeyboardMarkup)(nil)
+	switch action {
+	case "paid":
+		payment := &models.Payment{
+			UserID:   userID,
+			CreditID: credit.ID,
+			Amount:   schedule.NextOrLast(credit, now).Amount,
+			PaidAt:   now,
+		}
+		// Платеж, записанный после отправки напоминания, уже закрывает его: повторное
+		// нажатие ничего не добавляет
+		var added bool
+		added, err = b.db.AddPaymentOnce(payment, time.Unix(int64(query.Message.Date), 0))
+		if err == nil && !added {
+			b.answerCallback(query, tr.T("reminder.already_paid"))
+			return
+		}
+		status = tr.T("reminder.paid", now.In(location).Format("02.01 15:04"), utils.FormatMoney(payment.Amount))
+	case "later":
+		remindAt := now.Add(snoozeDelay)
+		err = b.db.AddSnooze(&models.Snooze{UserID: userID, CreditID: credit.ID, RemindAt: remindAt.UTC()})
+		status = tr.T("reminder.later", remindAt.In(location).Format("15:04"))
+	case "tomorrow":
+		local := now.In(location)
+		// Завтра в то же время, когда приходят ежедневные напоминания
+		hour, minute := b.cfg.NotificationClock()
+		remindAt := time.Date(local.Year(), local.Month(), local.Day()+1, hour, minute, 0, 0, location)
+		err = b.db.AddSnooze(&models.Snooze{UserID: userID, CreditID: credit.ID, RemindAt: remindAt.UTC()})
+		status = tr.T("reminder.tomorrow", remindAt.Format("15:04"))
+	case "mute":
+		err = b.db.SetCreditMuted(credit.ID, true)
+		status = tr.T("reminder.muted")
+		unmute := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("reminder.button_unmute"), fmt.Sprintf("remind:unmute:%d", credit.ID)),
+		))
+		keyboard = &unmute
+	case "unmute":
+		err = b.db.SetCreditMuted(credit.ID, false)
+		status = tr.T("reminder.unmuted")
+	default:
+		b.answerCallback(query, "")
+		return
+	}
+	if err != nil {
+		logger.Error("Error handling reminder action", "user_id", userID, "action", action, "credit_id", credit.ID, "err", err)
+		b.answerCallback(query, tr.T("error.retry"))
+		return
+	}
+	if action == "later" || action == "tomorrow" {
+		metrics.ReminderEvent(metrics.ReminderScheduled)
+	}
+	b.answerCallback(query, "")
+
+	// Текст напоминания остается, под ним - что выбрал пользователь; кнопки убираем
+	edit := tgbotapi.NewEditMessageText(query.Message.Chat.ID, query.Message.MessageID, reminderText(tr, credit, now)+"\n\n"+status)
+	edit.ParseMode = tgbotapi.ModeMarkdown
+	edit.ReplyMarkup = keyboard
+	_, err = b.send(edit)
+	if err != nil {
+		logger.Error("Error editing message", "err", err)
+	}
+}
+
+func reminderText(tr *i18n.Localizer, credit *models.Credit, now time.Time) string {
+	installment := schedule.NextOrLast(credit, now)
+	when := tr.T("reminder.when_soon")
+	days := int(dateOnly(installment.Date).Sub(dateOnly(now)).Hours() / 24)
+	switch {
+	case days == 0:
+		when = tr.T("reminder.when_today")
+	case days == 1:
+		when = tr.T("reminder.when_tomorrow")
+	case days < 0:
+		when = tr.T("reminder.when_overdue")
+	}
+	return tr.T("reminder.text", utils.EscapeMarkdown(credit.BankName), installment.Amount, installment.Date.Format("02.01.2006"), when)
+}
+
+func reminderKeyboard(tr *i18n.Localizer, creditID int) tgbotapi.InlineKeyboardMarkup {
+	data := func(action string) string {
+		return fmt.Sprintf("remind:%s:%d", action, creditID)
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("reminder.button_paid"), data("paid")),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("reminder.button_later"), data("later")),
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("reminder.button_tomorrow"), data("tomorrow")),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("reminder.button_mute"), data("mute")),
+		),
+	)
+}
+
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
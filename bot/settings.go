@@ -0,0 +1,209 @@
+package bot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"DebtBot/digest"
+	"DebtBot/i18n"
+	"DebtBot/models"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// Час (по времени пользователя), в который приходят сводки
+const digestHour = 9
+
+// /settings - подписка на сводки и часовой пояс
+func (b *Bot) handleSettingsCommand(message *tgbotapi.Message) {
+	userID := int64(message.From.ID)
+	user, err := b.db.GetUser(userID)
+	if err != nil {
+		logger.Error("handleSettingsCommand: Ошибка при получении пользователя", "user_id", userID, "err", err)
+		b.sendMessage(message.Chat.ID, i18n.New(message.From.LanguageCode).T("settings.error"), message.MessageID)
+		return
+	}
+	tr := i18n.New(user.Lang())
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, settingsText(tr, user))
+	msg.ParseMode = tgbotapi.ModeMarkdown
+	msg.ReplyMarkup = settingsKeyboard(tr, user)
+	_, err = b.send(msg)
+	if err != nil {
+		logger.Error("Error sending message", "err", err)
+	}
+}
+
+// Переключение сводок кнопками под сообщением /settings
+func (b *Bot) handleSettingsCallback(query *tgbotapi.CallbackQuery, action string) {
+	userID := int64(query.From.ID)
+	user, err := b.db.GetUser(userID)
+	if err != nil {
+		logger.Error("Error getting user", "user_id", userID, "err", err)
+		b.answerCallback(query, i18n.New(query.From.LanguageCode).T("error.retry"))
+		return
+	}
+	tr := i18n.New(user.Lang())
+
+	switch action {
+	case "weekly":
+		user.WeeklyDigest = !user.WeeklyDigest
+	case "monthly":
+		user.MonthlyDigest = !user.MonthlyDigest
+	default:
+		b.answerCallback(query, "")
+		return
+	}
+
+	err = b.db.SetDigestSettings(userID, user.WeeklyDigest, user.MonthlyDigest)
+	if err != nil {
+		logger.Error("Error saving digest settings", "user_id", userID, "err", err)
+		b.answerCallback(query, tr.T("error.retry"))
+		return
+	}
+	b.answerCallback(query, tr.T("settings.saved"))
+
+	edit := tgbotapi.NewEditMessageText(query.Message.Chat.ID, query.Message.MessageID, settingsText(tr, user))
+	edit.ParseMode = tgbotapi.ModeMarkdown
+	keyboard := settingsKeyboard(tr, user)
+	edit.ReplyMarkup = &keyboard
+	_, err = b.send(edit)
+	if err != nil {
+		logger.Error("Error editing message", "err", err)
+	}
+}
+
+// /timezone Europe/Moscow или /timezone +3
+func (b *Bot) handleTimezoneCommand(message *tgbotapi.Message) {
+	userID := int64(message.From.ID)
+	tr := b.tr(userID)
+	args := strings.TrimSpace(message.CommandArguments())
+	if args == "" {
+		b.sendMessage(message.Chat.ID, tr.T("timezone.usage"), message.MessageID)
+		return
+	}
+
+	timezone, err := parseTimezone(args)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, tr.T("timezone.unknown"), message.MessageID)
+		return
+	}
+
+	err = b.db.SetTimezone(userID, timezone)
+	if err != nil {
+		logger.Error("Error saving timezone", "user_id", userID, "err", err)
+		b.sendMessage(message.Chat.ID, tr.T("timezone.save_error"), message.MessageID)
+		return
+	}
+
+	location, _ := time.LoadLocation(timezone)
+	b.sendMessage(message.Chat.ID, tr.T("timezone.saved", time.Now().In(location).Format("15:04")), message.MessageID)
+}
+
+// SendDigests отправляет недельные (по понедельникам) и месячные (1-го числа) сводки.
+// Запускается каждый час; сводка уходит, когда у пользователя наступает digestHour.
+func (b *Bot) SendDigests() {
+	users, err := b.db.GetDigestSubscribers()
+	if err != nil {
+		logger.Error("Error getting digest subscribers", "err", err)
+		return
+	}
+
+	now := time.Now()
+	for _, user := range users {
+		local := now.In(user.Location())
+		if local.Hour() != digestHour {
+			continue
+		}
+		today := local.Format("2006-01-02")
+
+		weekly := user.WeeklyDigest && local.Weekday() == time.Monday && user.LastWeeklyDigest != today
+		monthly := user.MonthlyDigest && local.Day() == 1 && user.LastMonthlyDigest != today
+		if !weekly && !monthly {
+			continue
+		}
+
+		credits, err := b.db.GetCreditsByUser(user.ID)
+		if err != nil {
+			logger.Error("Error getting credits for digest", "user_id", user.ID, "err", err)
+			continue
+		}
+
+		tr := i18n.New(user.Lang())
+		if weekly {
+			if text := digest.Weekly(tr, credits, local); text != "" {
+				b.notify(user.ID, models.OutboxDigest, text)
+			}
+			if err := b.db.MarkWeeklyDigestSent(user.ID, today); err != nil {
+				logger.Error("Error marking weekly digest", "user_id", user.ID, "err", err)
+			}
+		}
+
+		if monthly {
+			payments, err := b.db.GetPaymentsByUser(user.ID)
+			if err != nil {
+				logger.Error("Error getting payments for digest", "user_id", user.ID, "err", err)
+				continue
+			}
+			if text := digest.Monthly(tr, credits, payments, user.MonthlyIncome, local); text != "" {
+				b.notify(user.ID, models.OutboxDigest, text)
+			}
+			if err := b.db.MarkMonthlyDigestSent(user.ID, today); err != nil {
+				logger.Error("Error marking monthly digest", "user_id", user.ID, "err", err)
+			}
+		}
+	}
+}
+
+func settingsText(tr *i18n.Localizer, user *models.User) string {
+	timezone := user.Timezone
+	if timezone == "" {
+		timezone = tr.T("settings.timezone_default")
+	}
+	return tr.T("settings.text", digestHour, onOff(tr, user.WeeklyDigest), digestHour, onOff(tr, user.MonthlyDigest), timezone, tr.T("language.name"))
+}
+
+func settingsKeyboard(tr *i18n.Localizer, user *models.User) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(toggleLabel(tr.T("settings.button_weekly"), user.WeeklyDigest), "settings:weekly:"),
+			tgbotapi.NewInlineKeyboardButtonData(toggleLabel(tr.T("settings.button_monthly"), user.MonthlyDigest), "settings:monthly:"),
+		),
+	)
+}
+
+func onOff(tr *i18n.Localizer, enabled bool) string {
+	if enabled {
+		return tr.T("settings.on")
+	}
+	return tr.T("settings.off")
+}
+
+func toggleLabel(title string, enabled bool) string {
+	if enabled {
+		return "✅ " + title
+	}
+	return "⬜ " + title
+}
+
+// parseTimezone принимает имя IANA ("Europe/Moscow") или смещение от UTC ("+3", "UTC+5")
+func parseTimezone(s string) (string, error) {
+	offset := strings.TrimPrefix(strings.ToUpper(s), "UTC")
+	offset = strings.TrimPrefix(offset, "GMT")
+	if hours, err := strconv.Atoi(offset); err == nil && offset != "" {
+		if hours < -12 || hours > 14 {
+			return "", fmt.Errorf("offset out of range: %d", hours)
+		}
+		// В зонах Etc/GMT знак смещения инвертирован: Etc/GMT-3 - это UTC+3
+		if hours == 0 {
+			return "Etc/GMT", nil
+		}
+		return fmt.Sprintf("Etc/GMT%+d", -hours), nil
+	}
+
+	if _, err := time.LoadLocation(s); err != nil || s == "Local" {
+		return "", fmt.Errorf("unknown timezone %q", s)
+	}
+	return s, nil
+}
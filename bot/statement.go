@@ -0,0 +1,82 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"DebtBot/i18n"
+	"DebtBot/statement"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// /statement [ГГГГ-ММ] - PDF-выписка за месяц (по умолчанию - за текущий)
+func (b *Bot) handleStatementCommand(message *tgbotapi.Message) {
+	userID := int64(message.From.ID)
+	tr := b.tr(userID)
+	month := time.Now()
+	if args := strings.TrimSpace(message.CommandArguments()); args != "" {
+		parsed, err := time.Parse("2006-01", args)
+		if err != nil {
+			b.sendMessage(message.Chat.ID, tr.T("statement.usage"), message.MessageID)
+			return
+		}
+		month = parsed
+	}
+
+	content, err := b.buildStatement(tr, userID, month)
+	if err != nil {
+		logger.Error("handleStatementCommand: Ошибка при формировании выписки", "user_id", userID, "err", err)
+		b.sendMessage(message.Chat.ID, tr.T("statement.error"), message.MessageID)
+		return
+	}
+	if content == nil {
+		b.sendMessage(message.Chat.ID, tr.T("credits.empty"), message.MessageID)
+		return
+	}
+	b.sendDocument(message.Chat.ID, statementFileName(month), content)
+}
+
+// SendMonthlyStatements рассылает всем пользователям с кредитами выписку за прошедший месяц
+func (b *Bot) SendMonthlyStatements() {
+	users, err := b.db.GetUsers()
+	if err != nil {
+		logger.Error("Error getting users", "err", err)
+		return
+	}
+
+	month := statement.MonthStart(time.Now()).AddDate(0, -1, 0)
+	for _, user := range users {
+		tr := i18n.New(user.Lang())
+		content, err := b.buildStatement(tr, user.ID, month)
+		if err != nil {
+			logger.Error("Error building statement", "user_id", user.ID, "err", err)
+			continue
+		}
+		if content == nil {
+			continue
+		}
+		b.sendMessage(user.ID, tr.T("statement.monthly", tr.Month(month.Month()), month.Year()), 0)
+		b.sendDocument(user.ID, statementFileName(month), content)
+	}
+}
+
+// buildStatement возвращает PDF или nil, если у пользователя нет кредитов
+func (b *Bot) buildStatement(tr *i18n.Localizer, userID int64, month time.Time) ([]byte, error) {
+	credits, err := b.db.GetCreditsByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(credits) == 0 {
+		return nil, nil
+	}
+	payments, err := b.db.GetPaymentsByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	return statement.Render(tr, statement.Build(credits, payments, month, time.Now()))
+}
+
+func statementFileName(month time.Time) string {
+	return fmt.Sprintf("statement-%s.pdf", month.Format("2006-01"))
+}
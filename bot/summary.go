@@ -0,0 +1,127 @@
+package bot
+
+import (
+	"strings"
+	"time"
+
+	"DebtBot/i18n"
+	"DebtBot/input"
+	"DebtBot/summary"
+	"DebtBot/utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// /income [сумма] - указать среднемесячный доход для расчета долговой нагрузки
+func (b *Bot) handleIncomeCommand(message *tgbotapi.Message) {
+	userID := int64(message.From.ID)
+	args := strings.TrimSpace(message.CommandArguments())
+	if args == "" {
+		tr := b.tr(userID)
+		b.state[userID] = "waiting_income"
+		b.inputData[userID] = make(map[string]string)
+		b.sendMessage(message.Chat.ID, tr.T("income.ask"), message.MessageID)
+		return
+	}
+	b.saveIncome(message, args)
+}
+
+// Ввод дохода в диалоге после /income без аргументов
+func (b *Bot) handleIncomeInput(message *tgbotapi.Message) {
+	userID := int64(message.From.ID)
+	if b.saveIncome(message, message.Text) {
+		delete(b.state, userID)
+		delete(b.inputData, userID)
+	}
+}
+
+func (b *Bot) saveIncome(message *tgbotapi.Message, text string) bool {
+	userID := int64(message.From.ID)
+	tr := b.tr(userID)
+	income, err := input.Amount(text)
+	if err != nil || income < 0 {
+		b.sendMessage(message.Chat.ID, tr.T("income.bad_amount"), message.MessageID)
+		return false
+	}
+
+	err = b.db.SetMonthlyIncome(userID, income)
+	if err != nil {
+		logger.Error("Error saving income", "user_id", userID, "err", err)
+		b.sendMessage(message.Chat.ID, tr.T("income.save_error"), message.MessageID)
+		return false
+	}
+
+	if income == 0 {
+		b.sendMessage(message.Chat.ID, tr.T("income.deleted"), message.MessageID)
+	} else {
+		b.sendMessage(message.Chat.ID, tr.T("income.saved", income), message.MessageID)
+	}
+	return true
+}
+
+// /summary - сводка по долгам и показатель долговой нагрузки (ПДН)
+func (b *Bot) handleSummaryCommand(message *tgbotapi.Message) {
+	userID := int64(message.From.ID)
+	tr := b.tr(userID)
+	user, err := b.db.GetUser(userID)
+	if err != nil {
+		logger.Error("handleSummaryCommand: Ошибка при получении пользователя", "user_id", userID, "err", err)
+		b.sendMessage(message.Chat.ID, tr.T("summary.error"), message.MessageID)
+		return
+	}
+	credits, err := b.db.GetCreditsByUser(userID)
+	if err != nil {
+		logger.Error("handleSummaryCommand: Ошибка при получении кредитов из DB", "user_id", userID, "err", err)
+		b.sendMessage(message.Chat.ID, tr.T("summary.error"), message.MessageID)
+		return
+	}
+	payments, err := b.db.GetPaymentsByUser(userID)
+	if err != nil {
+		logger.Error("handleSummaryCommand: Ошибка при получении платежей из DB", "user_id", userID, "err", err)
+		b.sendMessage(message.Chat.ID, tr.T("summary.error"), message.MessageID)
+		return
+	}
+
+	if len(credits) == 0 {
+		b.sendMessage(message.Chat.ID, tr.T("credits.empty"), message.MessageID)
+		return
+	}
+
+	s := summary.Calculate(credits, payments, user.MonthlyIncome, time.Now())
+	b.sendMessage(message.Chat.ID, formatSummary(tr, s), message.MessageID)
+}
+
+func formatSummary(tr *i18n.Localizer, s summary.Summary) string {
+	text := tr.T("summary.title")
+	text += tr.N("summary.credits", s.Credits)
+	text += tr.T("summary.outstanding", utils.FormatMoney(s.Outstanding))
+	text += tr.T("summary.this_month", utils.FormatMoney(s.ThisMonth))
+	text += tr.T("summary.next_30_days", utils.FormatMoney(s.Next30Days))
+	text += tr.T("summary.monthly_payments", utils.FormatMoney(s.MonthlyPayments))
+
+	if len(s.ByBank) > 1 {
+		text += tr.T("summary.by_bank")
+		for _, bank := range s.ByBank {
+			emoji := bank.Emoji
+			if emoji == "" {
+				emoji = "🏦"
+			}
+			text += tr.T("summary.bank_item", emoji, utils.EscapeMarkdown(bank.Name), utils.FormatMoney(bank.Outstanding))
+		}
+	}
+
+	if s.Level == summary.LevelUnknown {
+		text += tr.T("summary.no_income")
+		return text
+	}
+
+	text += tr.T("summary.load", s.Load*100, utils.FormatMoney(s.Income))
+	switch s.Level {
+	case summary.LevelCritical:
+		text += tr.T("summary.level_critical")
+	case summary.LevelWarning:
+		text += tr.T("summary.level_warning")
+	default:
+		text += tr.T("summary.level_normal")
+	}
+	return text
+}
@@ -0,0 +1,123 @@
+package bot
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"DebtBot/utils"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+const (
+	undoWindow         = 5 * time.Minute // Сколько действует кнопка "Отменить" после удаления кредита
+	trashRetentionDays = 30              // Сколько дней кредит хранится в корзине до окончательного удаления
+)
+
+// deleteCredit перемещает кредит в корзину и предлагает отменить удаление
+func (b *Bot) deleteCredit(message *tgbotapi.Message, userID int64, creditID int) {
+	tr := b.tr(userID)
+	if err := b.db.DeleteCredit(creditID); err != nil {
+		logger.Error("Error deleting credit from DB", "user_id", userID, "credit_id", creditID, "err", err)
+		b.sendMessage(message.Chat.ID, tr.T("credit.delete_error"), message.MessageID)
+		return
+	}
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, tr.N("credit.deleted", trashRetentionDays))
+	msg.ReplyToMessageID = message.MessageID
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(tr.T("trash.button_undo"), fmt.Sprintf("trash:undo:%d", creditID)),
+	))
+	if _, err := b.send(msg); err != nil {
+		logger.Error("Error sending message with undo button", "err", err)
+	}
+}
+
+// /trash - удаленные кредиты с кнопками восстановления
+func (b *Bot) handleTrashCommand(message *tgbotapi.Message) {
+	userID := int64(message.From.ID)
+	tr := b.tr(userID)
+
+	credits, err := b.db.GetDeletedCredits(userID)
+	if err != nil {
+		logger.Error("handleTrashCommand: Ошибка при получении кредитов из DB", "user_id", userID, "err", err)
+		b.sendMessage(message.Chat.ID, tr.T("credits.load_error"), message.MessageID)
+		return
+	}
+	if len(credits) == 0 {
+		b.sendMessage(message.Chat.ID, tr.T("trash.empty"), message.MessageID)
+		return
+	}
+
+	text := tr.N("trash.title", trashRetentionDays)
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for i, credit := range credits {
+		text += tr.T("trash.item", i+1, utils.EscapeMarkdown(credit.BankName), utils.FormatMoney(credit.LoanAmount),
+			credit.DeletedAt.In(time.Local).Format("02.01.2006 15:04"))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(tr.T("trash.button_restore", i+1, credit.BankName), fmt.Sprintf("trash:restore:%d", credit.ID)),
+		))
+	}
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, text)
+	msg.ParseMode = tgbotapi.ModeMarkdown
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	if _, err := b.send(msg); err != nil {
+		logger.Error("Error sending trash", "err", err)
+	}
+}
+
+// Кнопки корзины: trash:undo:<ID кредита> под сообщением об удалении и trash:restore:<ID кредита> в /trash
+func (b *Bot) handleTrashCallback(query *tgbotapi.CallbackQuery, action, arg string) {
+	userID := int64(query.From.ID)
+	tr := b.tr(userID)
+	creditID, err := strconv.Atoi(arg)
+	if err != nil || (action != "undo" && action != "restore") {
+		b.answerCallback(query, tr.T("error.bad_button"))
+		return
+	}
+
+	// Отменить удаление можно только сразу; позже - через /trash
+	if action == "undo" && time.Since(time.Unix(int64(query.Message.Date), 0)) > undoWindow {
+		b.answerCallback(query, tr.T("trash.undo_expired"))
+		b.editMessageText(query.Message, tr.T("trash.undo_expired"))
+		return
+	}
+
+	restored, err := b.db.RestoreCredit(userID, creditID)
+	if err != nil {
+		logger.Error("Error restoring credit", "user_id", userID, "credit_id", creditID, "err", err)
+		b.answerCallback(query, tr.T("error.retry"))
+		return
+	}
+	if !restored {
+		b.answerCallback(query, tr.T("trash.not_found"))
+		return
+	}
+
+	credit, err := b.db.GetCredit(creditID)
+	if err != nil {
+		logger.Error("Error getting restored credit", "user_id", userID, "credit_id", creditID, "err", err)
+		b.answerCallback(query, "")
+		return
+	}
+	b.answerCallback(query, "")
+	text := tr.T("trash.restored", utils.EscapeMarkdown(credit.BankName), utils.FormatMoney(credit.LoanAmount))
+	if action == "undo" {
+		b.editMessageText(query.Message, text)
+	} else {
+		b.sendMessage(query.Message.Chat.ID, text, 0)
+	}
+}
+
+// PurgeTrash окончательно удаляет кредиты, пролежавшие в корзине дольше trashRetentionDays
+func (b *Bot) PurgeTrash() {
+	purged, err := b.db.PurgeDeletedCredits(time.Now().AddDate(0, 0, -trashRetentionDays))
+	if err != nil {
+		logger.Error("Error purging trash", "err", err)
+		return
+	}
+	if purged > 0 {
+		logger.Info("Корзина очищена", "credits", purged)
+	}
+}
@@ -0,0 +1,90 @@
+package bot
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"DebtBot/config"
+	"DebtBot/metrics"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+// Ограничение размера обновления, присланного на вебхук
+const maxUpdateSize = 1 << 20
+
+// Заголовок, в котором Telegram присылает secret_token, заданный при установке вебхука
+const secretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// updatesChannel возвращает канал обновлений в режиме из настроек: long polling или вебхук
+func (b *Bot) updatesChannel() (tgbotapi.UpdatesChannel, error) {
+	if b.cfg.Mode == config.ModeWebhook {
+		// SetWebhook библиотеки не умеет передавать secret_token, поэтому запрос собирается вручную
+		params := url.Values{}
+		params.Add("url", b.cfg.WebhookURL)
+		params.Add("secret_token", b.cfg.WebhookSecret)
+		if _, err := b.botAPI.MakeRequest("setWebhook", params); err != nil {
+			return nil, fmt.Errorf("error setting webhook: %w", err)
+		}
+		logger.Info("Вебхук установлен") // Адрес и секрет вебхука в журнал не пишутся
+		metrics.PollSucceeded()
+		return b.webhookUpdates, nil
+	}
+
+	// Пока у бота установлен вебхук, Telegram не отдает обновления через getUpdates
+	if _, err := b.botAPI.RemoveWebhook(); err != nil {
+		return nil, fmt.Errorf("error removing webhook: %w", err)
+	}
+	return b.pollUpdates(), nil
+}
+
+// pollUpdates получает обновления через long polling. В отличие от GetUpdatesChan библиотеки
+// отмечает каждый успешный запрос, чтобы /healthz и /readyz видели, что связь с Telegram есть.
+func (b *Bot) pollUpdates() tgbotapi.UpdatesChannel {
+	updates := make(chan tgbotapi.Update, 100)
+	go func() {
+		config := tgbotapi.NewUpdate(0)
+		config.Timeout = 60
+		for {
+			received, err := b.botAPI.GetUpdates(config)
+			if err != nil {
+				logger.Warn("Error getting updates, retrying in 3 seconds", "err", err)
+				time.Sleep(3 * time.Second)
+				continue
+			}
+			metrics.PollSucceeded()
+			for _, update := range received {
+				if update.UpdateID >= config.Offset {
+					config.Offset = update.UpdateID + 1
+					updates <- update
+				}
+			}
+		}
+	}()
+	return updates
+}
+
+// WebhookHandler принимает обновления от Telegram в режиме webhook. Запросы без секрета
+// из настройки webhook_secret отклоняются: адрес вебхука мог попасть в журналы прокси.
+func (b *Bot) WebhookHandler() http.Handler {
+	secret := []byte(b.cfg.WebhookSecret)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(secret) == 0 || subtle.ConstantTimeCompare([]byte(r.Header.Get(secretTokenHeader)), secret) != 1 {
+			logger.Warn("Webhook request with invalid secret token", "remote_addr", r.RemoteAddr)
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		var update tgbotapi.Update
+		if err := json.NewDecoder(io.LimitReader(r.Body, maxUpdateSize)).Decode(&update); err != nil {
+			logger.Warn("Error decoding webhook update", "err", err)
+			http.Error(w, "bad update", http.StatusBadRequest)
+			return
+		}
+		metrics.PollSucceeded()
+		b.webhookUpdates <- update
+	})
+}
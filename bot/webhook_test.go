@@ -0,0 +1,42 @@
+package bot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"DebtBot/config"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+func TestWebhookHandlerSecret(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret string // Секрет из настроек
+		header string // Значение X-Telegram-Bot-Api-Secret-Token; пусто - заголовка нет
+		want   int
+	}{
+		{"верный секрет", "s3cret", "s3cret", http.StatusOK},
+		{"нет заголовка", "s3cret", "", http.StatusForbidden},
+		{"неверный секрет", "s3cret", "s3cre", http.StatusForbidden},
+		{"секрет не настроен", "", "", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		b := &Bot{cfg: &config.Config{WebhookSecret: tt.secret}, webhookUpdates: make(chan tgbotapi.Update, 1)}
+		r := httptest.NewRequest(http.MethodPost, "/telegram", strings.NewReader(`{"update_id": 42}`))
+		if tt.header != "" {
+			r.Header.Set(secretTokenHeader, tt.header)
+		}
+		w := httptest.NewRecorder()
+		b.WebhookHandler().ServeHTTP(w, r)
+
+		if w.Code != tt.want {
+			t.Errorf("%s: status %d, want %d", tt.name, w.Code, tt.want)
+		}
+		accepted := len(b.webhookUpdates) == 1
+		if accepted != (tt.want == http.StatusOK) {
+			t.Errorf("%s: update accepted = %v", tt.name, accepted)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+// Package charts рисует графики по кредитам пользователя в PNG
+package charts
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"DebtBot/banks"
+	"DebtBot/fonts"
+	"DebtBot/i18n"
+	"DebtBot/models"
+	"DebtBot/schedule"
+	"github.com/golang/freetype/truetype"
+	"github.com/wcharczuk/go-chart/v2"
+	"github.com/wcharczuk/go-chart/v2/drawing"
+)
+
+// Размер изображений: Telegram показывает фото шириной до 1280 пикселей
+const (
+	width  = 1024
+	height = 600
+)
+
+// RemainingBalance - остаток долга по месяцам, сложенный по банкам
+func RemainingBalance(tr *i18n.Localizer, credits []*models.Credit, from time.Time, months int) ([]byte, error) {
+	font, err := truetype.Parse(fonts.Regular)
+	if err != nil {
+		return nil, err
+	}
+
+	start := monthStart(from)
+	points := make([]time.Time, months+1)
+	for i := range points {
+		points[i] = start.AddDate(0, i, 0)
+	}
+
+	// Остаток банка на начало месяца - основной долг в еще не наступивших платежах
+	banks := bankNames(credits)
+	remaining := make(map[string][]float64, len(banks))
+	for _, bank := range banks {
+		remaining[bank] = make([]float64, len(points))
+	}
+	for _, installment := range schedule.ForCredits(credits) {
+		for i, point := range points {
+			if !installment.Date.Before(point) {
+				remaining[installment.BankName][i] += installment.Principal
+			}
+		}
+	}
+
+	// Стек рисуем накопленными суммами: сначала самая высокая область, поверх - более низкие
+	cumulative := make([]float64, len(points))
+	series := []chart.Series{}
+	for i, bank := range banks {
+		values := make([]float64, len(points))
+		for j := range points {
+			cumulative[j] += remaining[bank][j]
+			values[j] = cumulative[j]
+		}
+		color := chart.GetDefaultColor(i)
+		series = append([]chart.Series{chart.TimeSeries{
+			Name:    bank,
+			XValues: points,
+			YValues: values,
+			Style: chart.Style{
+				StrokeColor: color,
+				FillColor:   color.WithAlpha(200),
+				StrokeWidth: 2,
+			},
+		}}, series...)
+	}
+
+	graph := chart.Chart{
+		Title:  tr.T("charts.balance_title"),
+		Font:   font,
+		Width:  width,
+		Height: height,
+		Background: chart.Style{
+			Padding: chart.Box{Top: 50, Left: 20, Right: 20, Bottom: 20},
+		},
+		XAxis: chart.XAxis{
+			ValueFormatter: monthFormatter(tr),
+		},
+		YAxis: chart.YAxis{
+			ValueFormatter: moneyFormatter,
+		},
+		Series: series,
+	}
+	graph.Elements = []chart.Renderable{chart.LegendLeft(&graph)}
+	return render(graph)
+}
+
+// MonthlyLoad - сумма платежей по месяцам
+func MonthlyLoad(tr *i18n.Localizer, credits []*models.Credit, from time.Time, months int) ([]byte, error) {
+	font, err := truetype.Parse(fonts.Regular)
+	if err != nil {
+		return nil, err
+	}
+
+	start := monthStart(from)
+	totals := make([]float64, months)
+	for _, installment := range schedule.ForCredits(credits) {
+		index := monthsBetween(start, installment.Date)
+		if index >= 0 && index < months {
+			totals[index] += installment.Amount
+		}
+	}
+
+	bars := make([]chart.Value, months)
+	for i := range bars {
+		month := start.AddDate(0, i, 0)
+		bars[i] = chart.Value{
+			Label: monthLabel(tr, month),
+			Value: totals[i],
+			Style: chart.Style{FillColor: chart.GetDefaultColor(0), StrokeColor: chart.GetDefaultColor(0)},
+		}
+	}
+
+	graph := chart.BarChart{
+		Title:    tr.T("charts.monthly_title"),
+		Font:     font,
+		Width:    width,
+		Height:   height,
+		BarWidth: 50,
+		Background: chart.Style{
+			Padding: chart.Box{Top: 50, Left: 20, Right: 20, Bottom: 20},
+		},
+		YAxis: chart.YAxis{
+			ValueFormatter: moneyFormatter,
+		},
+		Bars: bars,
+	}
+	return render(graph)
+}
+
+// BankShare - доля каждого банка в сумме долга
+func BankShare(tr *i18n.Localizer, credits []*models.Credit) ([]byte, error) {
+	font, err := truetype.Parse(fonts.Regular)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]float64)
+	total := 0.0
+	for _, credit := range credits {
+		totals[banks.Canonical(credit.BankID, credit.BankName)] += credit.LoanAmount
+		total += credit.LoanAmount
+	}
+
+	values := []chart.Value{}
+	for i, bank := range bankNames(credits) {
+		values = append(values, chart.Value{
+			Label: fmt.Sprintf("%s %.0f%%", bank, totals[bank]/total*100),
+			Value: totals[bank],
+			Style: chart.Style{FillColor: chart.GetDefaultColor(i), StrokeColor: drawing.ColorWhite},
+		})
+	}
+
+	graph := chart.PieChart{
+		Title:  tr.T("charts.banks_title"),
+		Font:   font,
+		Width:  height,
+		Height: height,
+		Values: values,
+	}
+	return render(graph)
+}
+
+// Общий интерфейс графиков go-chart
+type renderable interface {
+	Render(rp chart.RendererProvider, w io.Writer) error
+}
+
+func render(graph renderable) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := graph.Render(chart.PNG, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Названия банков, отсортированные по убыванию суммы долга
+func bankNames(credits []*models.Credit) []string {
+	totals := make(map[string]float64)
+	for _, credit := range credits {
+		totals[banks.Canonical(credit.BankID, credit.BankName)] += credit.LoanAmount
+	}
+	names := make([]string, 0, len(totals))
+	for name := range totals {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if totals[names[i]] != totals[names[j]] {
+			return totals[names[i]] > totals[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func monthsBetween(from, to time.Time) int {
+	return (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+}
+
+// monthLabel - подпись месяца на оси: "окт 24", "Oct 24"
+func monthLabel(tr *i18n.Localizer, t time.Time) string {
+	return fmt.Sprintf("%s %02d", tr.MonthShort(t.Month()), t.Year()%100)
+}
+
+func monthFormatter(tr *i18n.Localizer) chart.ValueFormatter {
+	return func(v interface{}) string {
+		switch value := v.(type) {
+		case time.Time:
+			return monthLabel(tr, value)
+		case float64:
+			return monthLabel(tr, time.Unix(0, int64(value)).UTC())
+		}
+		return ""
+	}
+}
+
+func moneyFormatter(v interface{}) string {
+	if value, ok := v.(float64); ok {
+		return fmt.Sprintf("%.0f", value)
+	}
+	return ""
+}
@@ -0,0 +1,487 @@
+// Package config собирает настройки бота из нескольких источников. Каждый следующий
+// источник переопределяет предыдущий:
+//
+//	значения по умолчанию < YAML-файл < .env < переменные окружения < флаги командной строки
+//
+// YAML-файл задается флагом -config или переменной CONFIG_FILE; по умолчанию читается
+// config.yaml, если он есть. Файлы .env и config.yaml необязательны.
+package config
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"DebtBot/logging"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Режимы получения обновлений от Telegram
+const (
+	ModePolling = "polling"
+	ModeWebhook = "webhook"
+)
+
+// Символы, которые Telegram допускает в secret_token вебхука
+var webhookSecretPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+
+// Кто может пользоваться ботом
+const (
+	AccessOpen      = "open"      // Любой пользователь Telegram
+	AccessAllowlist = "allowlist" // Только allowed_ids и администраторы
+	AccessInvite    = "invite"    // Кроме allowed_ids - по коду приглашения из /invite
+)
+
+const defaultConfigFile = "config.yaml"
+
+type Config struct {
+	BotToken string `yaml:"bot_token"`
+
+	DBDriver string `yaml:"db_driver"` // Драйвер database/sql; пока поддерживается только sqlite3
+	DBDSN    string `yaml:"db_dsn"`    // Для SQLite - путь к файлу базы
+
+	Mode          string `yaml:"mode"`           // polling или webhook
+	WebhookURL    string `yaml:"webhook_url"`    // Внешний HTTPS-адрес вебхука; путь адреса обслуживает HTTP-сервер бота
+	WebhookSecret string `yaml:"webhook_secret"` // Секрет, который Telegram присылает с каждым обновлением в X-Telegram-Bot-Api-Secret-Token
+
+	HTTPAddr  string `yaml:"http_addr"`  // Адрес HTTP-сервера бота (например, ":8080"); пусто - сервер не запускается
+	PublicURL string `yaml:"public_url"` // Внешний адрес HTTP-сервера для ссылок на подписку календаря
+
+	MetricsAddr string `yaml:"metrics_addr"` // Адрес внутреннего сервера /metrics, /healthz, /readyz; пусто - не запускается
+
+	Timezone         string `yaml:"timezone"`          // Часовой пояс сервера (IANA); пусто - системный
+	NotificationTime string `yaml:"notification_time"` // Время ежедневных напоминаний, ЧЧ:ММ
+
+	AdminIDs []int64 `yaml:"admin_ids"` // Telegram ID администраторов бота
+
+	AccessMode string  `yaml:"access_mode"` // open, allowlist или invite
+	AllowedIDs []int64 `yaml:"allowed_ids"` // Telegram ID, которым доступ открыт в режимах allowlist и invite
+
+	LogLevel  string            `yaml:"log_level"`  // debug, info, warn или error; в debug журнал не скрывает персональные данные
+	LogLevels map[string]string `yaml:"log_levels"` // Уровни отдельных пакетов: {db: warn, bot: debug}
+	LogFormat string            `yaml:"log_format"` // text или json
+
+	// Ключи AES-256 (base64) для шифрования сумм, названий банков и текстов в базе. Первый ключ -
+	// текущий, остальные - прежние, нужны до окончания rotate-keys. Пусто - шифрование выключено.
+	EncryptionKey     string `yaml:"encryption_key"`      // Ключи через запятую
+	EncryptionKeyFile string `yaml:"encryption_key_file"` // Файл с ключами, по одному в строке
+
+	BackupDir      string `yaml:"backup_dir"`      // Папка резервных копий базы
+	BackupInterval string `yaml:"backup_interval"` // Период копирования по расписанию, например 24h; пусто - только вручную
+	BackupKeep     int    `yaml:"backup_keep"`     // Сколько последних копий хранить; 0 - все
+	BackupGzip     bool   `yaml:"backup_gzip"`     // Сжимать копии gzip
+	BackupEncrypt  bool   `yaml:"backup_encrypt"`  // Шифровать копии текущим ключом encryption_key
+}
+
+// field - настройка, которую можно задать переменной окружения и флагом
+type field struct {
+	name  string   // Имя в YAML и в сообщениях об ошибках
+	env   []string // Переменные окружения; более поздние важнее
+	flag  string
+	usage string
+	set   func(c *Config, value string) error
+}
+
+var fields = []field{
+	{"bot_token", []string{"BOT_TOKEN"}, "token", "токен Telegram-бота", setString(func(c *Config) *string { return &c.BotToken })},
+	{"db_driver", []string{"DB_DRIVER"}, "db-driver", "драйвер базы данных", setString(func(c *Config) *string { return &c.DBDriver })},
+	// DB_NAME - прежнее имя настройки, оставлено для существующих .env
+	{"db_dsn", []string{"DB_NAME", "DB_DSN"}, "db-dsn", "строка подключения к базе (для SQLite - путь к файлу)", setString(func(c *Config) *string { return &c.DBDSN })},
+	{"mode", []string{"BOT_MODE"}, "mode", "получение обновлений: polling или webhook", setString(func(c *Config) *string { return &c.Mode })},
+	{"webhook_url", []string{"WEBHOOK_URL"}, "webhook-url", "внешний HTTPS-адрес вебхука", setString(func(c *Config) *string { return &c.WebhookURL })},
+	{"webhook_secret", []string{"WEBHOOK_SECRET"}, "webhook-secret", "секрет вебхука: 1-256 символов A-Z, a-z, 0-9, _ и -", setString(func(c *Config) *string { return &c.WebhookSecret })},
+	{"http_addr", []string{"HTTP_ADDR"}, "http-addr", "адрес HTTP-сервера, например :8080", setString(func(c *Config) *string { return &c.HTTPAddr })},
+	{"public_url", []string{"PUBLIC_URL"}, "public-url", "внешний адрес HTTP-сервера для ссылок", setString(func(c *Config) *string { return &c.PublicURL })},
+	{"metrics_addr", []string{"METRICS_ADDR"}, "metrics-addr", "адрес внутреннего сервера метрик, например 127.0.0.1:9090", setString(func(c *Config) *string { return &c.MetricsAddr })},
+	{"timezone", []string{"TIMEZONE"}, "timezone", "часовой пояс сервера, например Europe/Moscow", setString(func(c *Config) *string { return &c.Timezone })},
+	{"notification_time", []string{"NOTIFICATION_TIME"}, "notification-time", "время ежедневных напоминаний, ЧЧ:ММ", setString(func(c *Config) *string { return &c.NotificationTime })},
+	{"admin_ids", []string{"ADMIN_IDS"}, "admin-ids", "Telegram ID администраторов через запятую", setIDs(func(c *Config) *[]int64 { return &c.AdminIDs })},
+	{"access_mode", []string{"ACCESS_MODE"}, "access-mode", "доступ к боту: open, allowlist или invite", setString(func(c *Config) *string { return &c.AccessMode })},
+	{"allowed_ids", []string{"ALLOWED_IDS"}, "allowed-ids", "Telegram ID с доступом к боту через запятую", setIDs(func(c *Config) *[]int64 { return &c.AllowedIDs })},
+	{"log_level", []string{"LOG_LEVEL"}, "log-level", "уровень журнала: debug, info, warn, error", setString(func(c *Config) *string { return &c.LogLevel })},
+	{"log_levels", []string{"LOG_LEVELS"}, "log-levels", "уровни пакетов, например db=warn,bot=debug", setLogLevels},
+	{"log_format", []string{"LOG_FORMAT"}, "log-format", "формат журнала: text или json", setString(func(c *Config) *string { return &c.LogFormat })},
+	{"encryption_key", []string{"ENCRYPTION_KEY"}, "encryption-key", "ключи шифрования данных (base64) через запятую, первый - текущий", setString(func(c *Config) *string { return &c.EncryptionKey })},
+	{"encryption_key_file", []string{"ENCRYPTION_KEY_FILE"}, "encryption-key-file", "файл с ключами шифрования данных, первый - текущий", setString(func(c *Config) *string { return &c.EncryptionKeyFile })},
+	{"backup_dir", []string{"BACKUP_DIR"}, "backup-dir", "папка резервных копий базы", setString(func(c *Config) *string { return &c.BackupDir })},
+	{"backup_interval", []string{"BACKUP_INTERVAL"}, "backup-interval", "период резервного копирования, например 24h; пусто - только вручную", setString(func(c *Config) *string { return &c.BackupInterval })},
+	{"backup_keep", []string{"BACKUP_KEEP"}, "backup-keep", "сколько последних копий хранить (0 - все)", setInt(func(c *Config) *int { return &c.BackupKeep })},
+	{"backup_gzip", []string{"BACKUP_GZIP"}, "backup-gzip", "сжимать копии: true или false", setBool(func(c *Config) *bool { return &c.BackupGzip })},
+	{"backup_encrypt", []string{"BACKUP_ENCRYPT"}, "backup-encrypt", "шифровать копии ключом encryption_key: true или false", setBool(func(c *Config) *bool { return &c.BackupEncrypt })},
+}
+
+// Default возвращает настройки по умолчанию
+func Default() *Config {
+	return &Config{
+		DBDriver:         "sqlite3",
+		DBDSN:            "debtbot.db",
+		Mode:             ModePolling,
+		NotificationTime: "09:00",
+		AccessMode:       AccessOpen,
+		BackupDir:        "backups",
+		BackupKeep:       7,
+		BackupGzip:       true,
+		LogLevel:         "info",
+		LogFormat:        logging.FormatText,
+	}
+}
+
+// Load собирает настройки из всех источников. args - аргументы командной строки без имени
+// программы; возвращаются аргументы, оставшиеся после флагов (подкоманда и ее флаги).
+// Ошибки всех некорректных настроек возвращаются вместе.
+func Load(args []string) (*Config, []string, error) {
+	flags := flag.NewFlagSet("DebtBot", flag.ContinueOnError)
+	configFile := flags.String("config", "", "YAML-файл настроек (по умолчанию "+defaultConfigFile+", если есть)")
+	envFile := flags.String("env-file", ".env", "файл с переменными окружения")
+	values := make(map[string]*string, len(fields))
+	for _, f := range fields {
+		values[f.flag] = flags.String(f.flag, "", f.usage)
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	cfg := Default()
+	var errs []error
+
+	// YAML-файл
+	path, required := *configFile, true
+	if path == "" {
+		path, required = os.Getenv("CONFIG_FILE"), true
+	}
+	if path == "" {
+		path, required = defaultConfigFile, false
+	}
+	if err := cfg.loadYAML(path, required); err != nil {
+		errs = append(errs, err)
+	}
+
+	// .env не меняет окружение процесса: переменные окружения важнее файла
+	dotenv, err := godotenv.Read(*envFile)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		errs = append(errs, fmt.Errorf("%s: %w", *envFile, err))
+	}
+	errs = append(errs, cfg.apply(func(name string) (string, bool) {
+		value, ok := dotenv[name]
+		return value, ok
+	})...)
+
+	// Переменные окружения
+	errs = append(errs, cfg.apply(os.LookupEnv)...)
+
+	// Флаги: только явно указанные
+	flags.Visit(func(fl *flag.Flag) {
+		for _, f := range fields {
+			if f.flag == fl.Name {
+				if err := f.set(cfg, *values[f.flag]); err != nil {
+					errs = append(errs, fmt.Errorf("%s (-%s): %w", f.name, f.flag, err))
+				}
+			}
+		}
+	})
+
+	errs = append(errs, cfg.validate()...)
+	if err := errors.Join(errs...); err != nil {
+		return nil, nil, err
+	}
+	return cfg, flags.Args(), nil
+}
+
+func (c *Config) loadYAML(path string, required bool) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !required {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true) // Опечатка в имени настройки - ошибка, а не молча пропущенное значение
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// apply переносит в настройки значения переменных, которые вернул lookup
+func (c *Config) apply(lookup func(name string) (string, bool)) []error {
+	var errs []error
+	for _, f := range fields {
+		for _, name := range f.env {
+			value, ok := lookup(name)
+			if !ok {
+				continue
+			}
+			if err := f.set(c, value); err != nil {
+				errs = append(errs, fmt.Errorf("%s (%s): %w", f.name, name, err))
+			}
+		}
+	}
+	return errs
+}
+
+// validate проверяет все настройки сразу, чтобы сообщить обо всех ошибках одним запуском
+func (c *Config) validate() []error {
+	var errs []error
+	invalid := func(name, format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%s: %s", name, fmt.Sprintf(format, args...)))
+	}
+
+	if c.BotToken == "" {
+		invalid("bot_token", "не задан (BOT_TOKEN)")
+	}
+	if c.DBDriver != "sqlite3" {
+		invalid("db_driver", "%q не поддерживается, доступен только sqlite3", c.DBDriver)
+	}
+	if c.DBDSN == "" {
+		invalid("db_dsn", "не задан (DB_DSN)")
+	}
+
+	switch c.Mode {
+	case ModePolling:
+	case ModeWebhook:
+		if c.WebhookURL == "" {
+			invalid("webhook_url", "обязателен в режиме webhook")
+		} else if u, err := url.Parse(c.WebhookURL); err != nil || u.Scheme != "https" || u.Host == "" {
+			invalid("webhook_url", "нужен HTTPS-адрес, например https://bot.example.com/telegram")
+		} else if strings.Trim(u.Path, "/") == "" {
+			// Иначе вебхук занял бы корень HTTP-сервера вместе со страницами календаря
+			invalid("webhook_url", "нужен путь вебхука, например https://bot.example.com/telegram")
+		}
+		if c.WebhookSecret == "" {
+			invalid("webhook_secret", "обязателен в режиме webhook (WEBHOOK_SECRET)")
+		} else if !webhookSecretPattern.MatchString(c.WebhookSecret) {
+			invalid("webhook_secret", "допустимы 1-256 символов A-Z, a-z, 0-9, _ и -")
+		}
+		if c.HTTPAddr == "" {
+			invalid("http_addr", "обязателен в режиме webhook: на нем принимаются обновления")
+		}
+	default:
+		invalid("mode", "%q - допустимы %s и %s", c.Mode, ModePolling, ModeWebhook)
+	}
+
+	if c.PublicURL != "" {
+		if u, err := url.Parse(c.PublicURL); err != nil || u.Scheme == "" || u.Host == "" {
+			invalid("public_url", "%q не похож на адрес, например https://bot.example.com", c.PublicURL)
+		}
+	}
+	if c.MetricsAddr != "" && c.MetricsAddr == c.HTTPAddr {
+		invalid("metrics_addr", "должен отличаться от http_addr: метрики не открываются наружу")
+	}
+	if c.Timezone != "" {
+		if _, err := time.LoadLocation(c.Timezone); err != nil {
+			invalid("timezone", "неизвестный часовой пояс %q", c.Timezone)
+		}
+	}
+	if _, _, err := parseClock(c.NotificationTime); err != nil {
+		invalid("notification_time", "%v", err)
+	}
+	for _, id := range c.AdminIDs {
+		if id <= 0 {
+			invalid("admin_ids", "некорректный ID %d", id)
+		}
+	}
+	switch c.AccessMode {
+	case AccessOpen, AccessAllowlist, AccessInvite:
+	default:
+		invalid("access_mode", "%q - допустимы %s, %s и %s", c.AccessMode, AccessOpen, AccessAllowlist, AccessInvite)
+	}
+	for _, id := range c.AllowedIDs {
+		if id <= 0 {
+			invalid("allowed_ids", "некорректный ID %d", id)
+		}
+	}
+	if _, err := logging.ParseLevel(c.LogLevel); err != nil {
+		invalid("log_level", "%v", err)
+	}
+	for pkg, level := range c.LogLevels {
+		if _, err := logging.ParseLevel(level); err != nil {
+			invalid("log_levels", "%s: %v", pkg, err)
+		}
+	}
+	if c.LogFormat != logging.FormatText && c.LogFormat != logging.FormatJSON {
+		invalid("log_format", "%q - допустимы %s и %s", c.LogFormat, logging.FormatText, logging.FormatJSON)
+	}
+	if c.EncryptionKey != "" && c.EncryptionKeyFile != "" {
+		invalid("encryption_key", "задайте ключи либо в encryption_key, либо в encryption_key_file")
+	} else if keys, err := c.EncryptionKeys(); err != nil {
+		invalid("encryption_key", "%v", err)
+	} else if c.BackupEncrypt && len(keys) == 0 {
+		invalid("backup_encrypt", "для шифрования копий нужен encryption_key или encryption_key_file")
+	}
+	if c.BackupDir == "" {
+		invalid("backup_dir", "не задан (BACKUP_DIR)")
+	}
+	if c.BackupInterval != "" {
+		if interval, err := time.ParseDuration(c.BackupInterval); err != nil || interval < time.Minute {
+			invalid("backup_interval", "%q - нужен период не меньше минуты, например 24h или 6h", c.BackupInterval)
+		}
+	}
+	if c.BackupKeep < 0 {
+		invalid("backup_keep", "не может быть отрицательным")
+	}
+	return errs
+}
+
+// Location - часовой пояс сервера
+func (c *Config) Location() *time.Location {
+	if c.Timezone != "" {
+		if location, err := time.LoadLocation(c.Timezone); err == nil {
+			return location
+		}
+	}
+	return time.Local
+}
+
+// NotificationClock - час и минута ежедневных напоминаний
+func (c *Config) NotificationClock() (hour, minute int) {
+	hour, minute, _ = parseClock(c.NotificationTime)
+	return hour, minute
+}
+
+// BackupEvery - период резервного копирования по расписанию; 0 - копирование только вручную
+func (c *Config) BackupEvery() time.Duration {
+	interval, _ := time.ParseDuration(c.BackupInterval)
+	return interval
+}
+
+// WebhookPath - путь вебхука, который слушает HTTP-сервер бота
+func (c *Config) WebhookPath() string {
+	u, err := url.Parse(c.WebhookURL)
+	if err != nil || u.Path == "" {
+		return "/"
+	}
+	return u.Path
+}
+
+// IsAdmin проверяет, что пользователь - администратор бота
+func (c *Config) IsAdmin(userID int64) bool {
+	return slices.Contains(c.AdminIDs, userID)
+}
+
+// IsAllowed проверяет, что пользователь в списке allowed_ids или администратор
+func (c *Config) IsAllowed(userID int64) bool {
+	return slices.Contains(c.AllowedIDs, userID) || c.IsAdmin(userID)
+}
+
+func parseClock(s string) (int, int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, 0, fmt.Errorf("%q - нужно время ЧЧ:ММ, например 09:00", s)
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+func setString(target func(c *Config) *string) func(c *Config, value string) error {
+	return func(c *Config, value string) error {
+		*target(c) = strings.TrimSpace(value)
+		return nil
+	}
+}
+
+func setInt(target func(c *Config) *int) func(c *Config, value string) error {
+	return func(c *Config, value string) error {
+		number, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("%q - нужно целое число", value)
+		}
+		*target(c) = number
+		return nil
+	}
+}
+
+func setBool(target func(c *Config) *bool) func(c *Config, value string) error {
+	return func(c *Config, value string) error {
+		flag, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("%q - нужно true или false", value)
+		}
+		*target(c) = flag
+		return nil
+	}
+}
+
+func setIDs(target func(c *Config) *[]int64) func(c *Config, value string) error {
+	return func(c *Config, value string) error {
+		ids := target(c)
+		*ids = nil
+		for _, part := range strings.Split(value, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			id, err := strconv.ParseInt(part, 10, 64)
+			if err != nil {
+				return fmt.Errorf("некорректный ID %q", part)
+			}
+			*ids = append(*ids, id)
+		}
+		return nil
+	}
+}
+
+// setLogLevels разбирает уровни пакетов вида "db=warn,bot=debug"
+func setLogLevels(c *Config, value string) error {
+	c.LogLevels = make(map[string]string)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		pkg, level, ok := strings.Cut(part, "=")
+		if !ok || strings.TrimSpace(pkg) == "" {
+			return fmt.Errorf("нужно пакет=уровень, получено %q", part)
+		}
+		c.LogLevels[strings.TrimSpace(pkg)] = strings.TrimSpace(level)
+	}
+	return nil
+}
+
+// EncryptionKeys - ключи шифрования данных из encryption_key или encryption_key_file;
+// первый - текущий. Пустые строки и строки с # в файле пропускаются.
+func (c *Config) EncryptionKeys() ([][]byte, error) {
+	var encoded []string
+	switch {
+	case c.EncryptionKeyFile != "":
+		data, err := os.ReadFile(c.EncryptionKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		encoded = strings.Split(string(data), "\n")
+	case c.EncryptionKey != "":
+		encoded = strings.Split(c.EncryptionKey, ",")
+	}
+
+	var keys [][]byte
+	for _, line := range encoded {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, err := base64.StdEncoding.DecodeString(line)
+		if err != nil {
+			return nil, fmt.Errorf("ключ %d: не base64", len(keys)+1)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("ключ %d: нужно 32 байта, получено %d (создать: openssl rand -base64 32)", len(keys)+1, len(key))
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// Logging - настройки журнала для logging.Setup
+func (c *Config) Logging() logging.Options {
+	return logging.Options{Format: c.LogFormat, Level: c.LogLevel, Levels: c.LogLevels}
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateWebhook(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		secret  string
+		invalid string // Настройка с ошибкой; пусто - настройки корректны
+	}{
+		{"корректные настройки", "https://bot.example.com/telegram", "s3cret_token-1", ""},
+		{"нет адреса", "", "s3cret", "webhook_url"},
+		{"не HTTPS", "http://bot.example.com/telegram", "s3cret", "webhook_url"},
+		{"нет пути", "https://bot.example.com", "s3cret", "webhook_url"},
+		{"корень", "https://bot.example.com/", "s3cret", "webhook_url"},
+		{"только слеши", "https://bot.example.com//", "s3cret", "webhook_url"},
+		{"нет секрета", "https://bot.example.com/telegram", "", "webhook_secret"},
+		{"недопустимые символы", "https://bot.example.com/telegram", "секрет", "webhook_secret"},
+		{"пробел", "https://bot.example.com/telegram", "s3cret token", "webhook_secret"},
+		{"длиннее 256 символов", "https://bot.example.com/telegram", strings.Repeat("a", 257), "webhook_secret"},
+	}
+	for _, tt := range tests {
+		c := Default()
+		c.BotToken = "token"
+		c.Mode = ModeWebhook
+		c.HTTPAddr = ":8080"
+		c.WebhookURL = tt.url
+		c.WebhookSecret = tt.secret
+
+		var got []string
+		for _, err := range c.validate() {
+			got = append(got, err.Error())
+		}
+		switch {
+		case tt.invalid == "" && len(got) > 0:
+			t.Errorf("%s: unexpected errors %q", tt.name, got)
+		case tt.invalid != "" && (len(got) != 1 || !strings.HasPrefix(got[0], tt.invalid+":")):
+			t.Errorf("%s: errors %q, want one for %s", tt.name, got, tt.invalid)
+		}
+	}
+}
+
+func TestWebhookPath(t *testing.T) {
+	c := &Config{WebhookURL: "https://bot.example.com/telegram/hook"}
+	if got := c.WebhookPath(); got != "/telegram/hook" {
+		t.Errorf("WebhookPath = %q, want /telegram/hook", got)
+	}
+}
@@ -0,0 +1,85 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"DebtBot/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// auditRow - запись журнала изменений: снимки содержат суммы и названия банков и шифруются
+type auditRow struct {
+	models.AuditEntry
+	Before string `db:"before_data"`
+	After  string `db:"after_data"`
+}
+
+// auditCredit записывает изменение кредита в audit_log внутри транзакции tx.
+// before - кредит до изменения (nil при создании), after - после (nil при окончательном удалении).
+func (d *DB) auditCredit(tx *sqlx.Tx, action string, before, after *models.Credit) error {
+	entry := &models.AuditEntry{Entity: models.AuditCredit, Action: action}
+	for _, credit := range []*models.Credit{before, after} {
+		if credit != nil {
+			entry.UserID, entry.EntityID = credit.UserID, credit.ID
+		}
+	}
+	var err error
+	if entry.Before, err = snapshot(before); err != nil {
+		return err
+	}
+	if entry.After, err = snapshot(after); err != nil {
+		return err
+	}
+
+	row, err := d.sealAuditEntry(entry)
+	if err != nil {
+		return err
+	}
+	_, err = tx.NamedExec(`
+		INSERT INTO audit_log (user_id, entity, entity_id, action, before_data, after_data)
+		VALUES (:user_id, :entity, :entity_id, :action, :before_data, :after_data)
+	`, row)
+	return err
+}
+
+// snapshot сериализует кредит в JSON; nil - пустая строка
+func snapshot(credit *models.Credit) (string, error) {
+	if credit == nil {
+		return "", nil
+	}
+	data, err := json.Marshal(credit)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (d *DB) sealAuditEntry(entry *models.AuditEntry) (*auditRow, error) {
+	row := &auditRow{AuditEntry: *entry}
+	var err error
+	if row.Before, err = d.cipher.Seal("audit_log.before_data", entry.Before); err != nil {
+		return nil, err
+	}
+	if row.After, err = d.cipher.Seal("audit_log.after_data", entry.After); err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
+func (d *DB) openAuditEntry(row *auditRow) (*models.AuditEntry, error) {
+	entry := row.AuditEntry
+	var err error
+	if entry.Before, err = d.cipher.Open("audit_log.before_data", row.Before); err != nil {
+		return nil, fmt.Errorf("audit entry %d: %w", entry.ID, err)
+	}
+	if entry.After, err = d.cipher.Open("audit_log.after_data", row.After); err != nil {
+		return nil, fmt.Errorf("audit entry %d: %w", entry.ID, err)
+	}
+	return &entry, nil
+}
+
+// Получение журнала изменений данных пользователя
+func (d *DB) GetAuditLogByUser(userID int64) ([]*models.AuditEntry, error) {
+	return selectRows(d, d.openAuditEntry, "SELECT * FROM audit_log WHERE user_id = ? ORDER BY id", userID)
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// Страниц, копируемых за один шаг резервного копирования. Между шагами база открыта
+// для записи, поэтому бот продолжает работать, пока копия создается.
+const backupPagesPerStep = 256
+
+// BackupTo копирует базу в файл path через online backup API SQLite
+func (d *DB) BackupTo(path string) error {
+	ctx := context.Background()
+	dest, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return err
+	}
+	defer dest.Close()
+	destConn, err := dest.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer destConn.Close()
+	srcConn, err := d.DB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer srcConn.Close()
+
+	return destConn.Raw(func(destDriver any) error {
+		return srcConn.Raw(func(srcDriver any) error {
+			destSQLite, destOK := destDriver.(*sqlite3.SQLiteConn)
+			srcSQLite, srcOK := srcDriver.(*sqlite3.SQLiteConn)
+			if !destOK || !srcOK {
+				return fmt.Errorf("online backup is supported only for sqlite3")
+			}
+			backup, err := destSQLite.Backup("main", srcSQLite, "main")
+			if err != nil {
+				return err
+			}
+			for {
+				done, err := backup.Step(backupPagesPerStep)
+				if err != nil {
+					backup.Finish()
+					return err
+				}
+				if done {
+					break
+				}
+				time.Sleep(10 * time.Millisecond) // Пропускаем записи, ждущие блокировки
+			}
+			return backup.Finish()
+		})
+	})
+}
+
+// CheckIntegrity проверяет файл базы SQLite (PRAGMA integrity_check)
+func CheckIntegrity(path string) error {
+	database, err := sql.Open("sqlite3", "file:"+path+"?mode=ro")
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	rows, err := database.Query("PRAGMA integrity_check")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	var problems []string
+	for rows.Next() {
+		var result string
+		if err := rows.Scan(&result); err != nil {
+			return err
+		}
+		if result != "ok" {
+			problems = append(problems, result)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("integrity check failed: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// FilePath возвращает путь к файлу базы из строки подключения SQLite
+func FilePath(dsn string) string {
+	path, _, _ := strings.Cut(strings.TrimPrefix(dsn, "file:"), "?")
+	return path
+}
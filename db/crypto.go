@@ -0,0 +1,177 @@
+package db
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Зашифрованное значение хранится строкой "enc:<ID ключа>:<base64(nonce + шифротекст)>".
+// Значения без префикса - записанные до включения шифрования, они читаются как есть.
+const sealedPrefix = "enc:"
+
+// ErrNoKey - в базе есть зашифрованные данные, а ключ для них не задан
+var ErrNoKey = errors.New("encryption key is not configured")
+
+// Cipher шифрует чувствительные колонки AES-256-GCM. Новые значения шифруются текущим
+// (первым) ключом, прежние ключи нужны только для чтения, пока rotate-keys не перешифрует строки.
+// nil-Cipher - шифрование выключено: значения пишутся открытым текстом.
+type Cipher struct {
+	currentID string
+	keys      map[string]cipher.AEAD
+}
+
+// NewCipher создает шифр из 32-байтных ключей; первый ключ - текущий. Без ключей возвращает nil.
+func NewCipher(keys [][]byte) (*Cipher, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	c := &Cipher{keys: make(map[string]cipher.AEAD, len(keys))}
+	for i, key := range keys {
+		if len(key) != 32 {
+			return nil, fmt.Errorf("key %d: need 32 bytes for AES-256, got %d", i+1, len(key))
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		id := KeyID(key)
+		if i == 0 {
+			c.currentID = id
+		}
+		c.keys[id] = aead
+	}
+	return c, nil
+}
+
+// KeyID - короткий отпечаток ключа, по которому при чтении выбирается ключ
+func KeyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:4])
+}
+
+// CurrentKeyID - ID ключа, которым шифруются новые значения
+func (c *Cipher) CurrentKeyID() string {
+	if c == nil {
+		return ""
+	}
+	return c.currentID
+}
+
+// Seal шифрует значение колонки. Имя колонки ("credits.bank_name") входит в проверяемые данные
+// GCM, поэтому шифротекст нельзя незаметно перенести в другую колонку.
+func (c *Cipher) Seal(column, plaintext string) (string, error) {
+	if c == nil {
+		return plaintext, nil
+	}
+	sealed, err := c.seal([]byte(plaintext), column)
+	if err != nil {
+		return "", err
+	}
+	return sealedPrefix + c.currentID + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// seal шифрует данные текущим ключом; результат - nonce и шифротекст
+func (c *Cipher) seal(data []byte, additional string) ([]byte, error) {
+	aead := c.keys[c.currentID]
+	nonce := make([]byte, aead.NonceSize()) // Свой случайный nonce для каждого значения
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, data, []byte(additional)), nil
+}
+
+// open расшифровывает nonce и шифротекст ключом id
+func (c *Cipher) open(id string, sealed []byte, additional string) ([]byte, error) {
+	aead, ok := c.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown key %s", id)
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("malformed encrypted value")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	data, err := aead.Open(nil, nonce, ciphertext, []byte(additional))
+	if err != nil {
+		return nil, fmt.Errorf("decrypting with key %s: %w", id, err)
+	}
+	return data, nil
+}
+
+// SealBytes шифрует двоичные данные (например, резервную копию) текущим ключом.
+// Результат начинается с ID ключа, поэтому расшифровать его можно и после смены ключа,
+// пока прежний ключ остается в настройках.
+func (c *Cipher) SealBytes(purpose string, data []byte) ([]byte, error) {
+	if c == nil {
+		return nil, ErrNoKey
+	}
+	sealed, err := c.seal(data, purpose)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(c.currentID), sealed...), nil
+}
+
+// OpenBytes расшифровывает данные, зашифрованные SealBytes
+func (c *Cipher) OpenBytes(purpose string, data []byte) ([]byte, error) {
+	if c == nil {
+		return nil, ErrNoKey
+	}
+	if len(data) < len(c.currentID) {
+		return nil, errors.New("malformed encrypted data")
+	}
+	id := string(data[:len(c.currentID)]) // ID ключа всегда одной длины
+	return c.open(id, data[len(c.currentID):], purpose)
+}
+
+// Open расшифровывает значение колонки; значения без префикса возвращаются как есть
+func (c *Cipher) Open(column, value string) (string, error) {
+	if !strings.HasPrefix(value, sealedPrefix) {
+		return value, nil
+	}
+	if c == nil {
+		return "", fmt.Errorf("%s: %w", column, ErrNoKey)
+	}
+	id, encoded, ok := strings.Cut(strings.TrimPrefix(value, sealedPrefix), ":")
+	if !ok {
+		return "", fmt.Errorf("%s: malformed encrypted value", column)
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("%s: malformed encrypted value", column)
+	}
+	plaintext, err := c.open(id, sealed, column)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", column, err)
+	}
+	return string(plaintext), nil
+}
+
+// SealFloat шифрует числовое значение колонки
+func (c *Cipher) SealFloat(column string, value float64) (string, error) {
+	return c.Seal(column, strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+// OpenFloat расшифровывает числовое значение колонки
+func (c *Cipher) OpenFloat(column, value string) (float64, error) {
+	plaintext, err := c.Open(column, value)
+	if err != nil {
+		return 0, err
+	}
+	number, err := strconv.ParseFloat(plaintext, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", column, err)
+	}
+	return number, nil
+}
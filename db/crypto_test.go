@@ -0,0 +1,242 @@
+package db
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"DebtBot/models"
+)
+
+func newTestCipher(t *testing.T, keys ...[]byte) *Cipher {
+	t.Helper()
+	c, err := NewCipher(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestCipherRoundTrip(t *testing.T) {
+	c := newTestCipher(t, testKey(1))
+	for _, plaintext := range []string{"", "Сбербанк", "долг за ужин; 1 500 ₽", strings.Repeat("x", 4096)} {
+		sealed, err := c.Seal("credits.bank_name", plaintext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(sealed, sealedPrefix+c.CurrentKeyID()+":") {
+			t.Errorf("Seal(%q) = %q, want prefix with the current key", plaintext, sealed)
+		}
+		if plaintext != "" && strings.Contains(sealed, plaintext) {
+			t.Errorf("Seal(%q) leaks the plaintext", plaintext)
+		}
+		got, err := c.Open("credits.bank_name", sealed)
+		if err != nil || got != plaintext {
+			t.Errorf("Open(Seal(%q)) = %q, %v", plaintext, got, err)
+		}
+	}
+
+	for _, value := range []float64{0, 0.01, 150000, 1234567.89, -42.5} {
+		sealed, err := c.SealFloat("payments.amount", value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, err := c.OpenFloat("payments.amount", sealed); err != nil || got != value {
+			t.Errorf("OpenFloat(SealFloat(%v)) = %v, %v", value, got, err)
+		}
+	}
+
+	data := []byte("SQLite format 3\x00 backup")
+	sealed, err := c.SealBytes("backup", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := c.OpenBytes("backup", sealed); err != nil || string(got) != string(data) {
+		t.Errorf("OpenBytes(SealBytes()) = %q, %v", got, err)
+	}
+
+	// Одно и то же значение шифруется каждый раз по-разному (свой nonce)
+	a, _ := c.Seal("credits.bank_name", "Сбербанк")
+	b, _ := c.Seal("credits.bank_name", "Сбербанк")
+	if a == b {
+		t.Error("Seal returned the same ciphertext twice")
+	}
+}
+
+func TestCipherPlaintextAndDisabled(t *testing.T) {
+	c := newTestCipher(t, testKey(1))
+	// Значения, записанные до включения шифрования, читаются как есть
+	if got, err := c.Open("credits.bank_name", "Сбербанк"); err != nil || got != "Сбербанк" {
+		t.Errorf("Open(plaintext) = %q, %v", got, err)
+	}
+	if got, err := c.OpenFloat("users.monthly_income", "0"); err != nil || got != 0 {
+		t.Errorf("OpenFloat(plaintext) = %v, %v", got, err)
+	}
+
+	// nil-Cipher пишет открытый текст, а зашифрованное без ключа прочитать нельзя
+	var disabled *Cipher
+	if got, err := disabled.Seal("credits.bank_name", "Сбербанк"); err != nil || got != "Сбербанк" {
+		t.Errorf("nil Seal = %q, %v", got, err)
+	}
+	sealed, _ := c.Seal("credits.bank_name", "Сбербанк")
+	if _, err := disabled.Open("credits.bank_name", sealed); !errors.Is(err, ErrNoKey) {
+		t.Errorf("nil Open(sealed) error = %v, want ErrNoKey", err)
+	}
+	if _, err := disabled.SealBytes("backup", []byte("x")); !errors.Is(err, ErrNoKey) {
+		t.Errorf("nil SealBytes error = %v, want ErrNoKey", err)
+	}
+}
+
+func TestCipherTamperDetection(t *testing.T) {
+	c := newTestCipher(t, testKey(1))
+	sealed, err := c.Seal("credits.bank_name", "Сбербанк")
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefix := sealedPrefix + c.CurrentKeyID() + ":"
+	raw, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(sealed, prefix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	flip := func(i int) string {
+		tampered := append([]byte(nil), raw...)
+		tampered[i] ^= 1
+		return prefix + base64.RawStdEncoding.EncodeToString(tampered)
+	}
+
+	tests := []struct {
+		name   string
+		column string
+		value  string
+	}{
+		{"измененный nonce", "credits.bank_name", flip(0)},
+		{"измененный шифротекст", "credits.bank_name", flip(len(raw) / 2)},
+		{"измененный тег", "credits.bank_name", flip(len(raw) - 1)},
+		{"обрезанное значение", "credits.bank_name", prefix + base64.RawStdEncoding.EncodeToString(raw[:len(raw)-1])},
+		{"слишком короткое значение", "credits.bank_name", prefix + base64.RawStdEncoding.EncodeToString(raw[:4])},
+		{"не base64", "credits.bank_name", prefix + "!!!"},
+		{"нет ID ключа", "credits.bank_name", sealedPrefix + "abc"},
+		{"неизвестный ключ", "credits.bank_name", sealedPrefix + KeyID(testKey(9)) + ":" + strings.TrimPrefix(sealed, prefix)},
+		{"перенос в другую колонку", "debts.description", sealed},
+	}
+	for _, tt := range tests {
+		if got, err := c.Open(tt.column, tt.value); err == nil {
+			t.Errorf("%s: Open = %q, want error", tt.name, got)
+		}
+	}
+
+	backup, _ := c.SealBytes("backup", []byte("data"))
+	backup[len(backup)-1] ^= 1
+	if _, err := c.OpenBytes("backup", backup); err == nil {
+		t.Error("OpenBytes accepted tampered data")
+	}
+}
+
+func TestCipherOldKey(t *testing.T) {
+	old := newTestCipher(t, testKey(1))
+	sealed, err := old.Seal("credits.bank_name", "Сбербанк")
+	if err != nil {
+		t.Fatal(err)
+	}
+	backup, err := old.SealBytes("backup", []byte("data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Новый ключ первым, прежний - вторым: старые значения читаются, новые шифруются новым ключом
+	rotated := newTestCipher(t, testKey(2), testKey(1))
+	if got, err := rotated.Open("credits.bank_name", sealed); err != nil || got != "Сбербанк" {
+		t.Errorf("Open with the old key = %q, %v", got, err)
+	}
+	if got, err := rotated.OpenBytes("backup", backup); err != nil || string(got) != "data" {
+		t.Errorf("OpenBytes with the old key = %q, %v", got, err)
+	}
+	resealed, _ := rotated.Seal("credits.bank_name", "Сбербанк")
+	if !strings.HasPrefix(resealed, sealedPrefix+KeyID(testKey(2))+":") {
+		t.Errorf("Seal after rotation = %q, want the new key", resealed)
+	}
+
+	// Без прежнего ключа старые значения не читаются
+	if _, err := newTestCipher(t, testKey(2)).Open("credits.bank_name", sealed); err == nil {
+		t.Error("Open without the old key succeeded")
+	}
+}
+
+func TestNewCipherKeyLength(t *testing.T) {
+	if _, err := NewCipher([][]byte{make([]byte, 16)}); err == nil {
+		t.Error("NewCipher accepted a 16-byte key")
+	}
+	if c, err := NewCipher(nil); c != nil || err != nil {
+		t.Errorf("NewCipher(nil) = %v, %v, want nil, nil", c, err)
+	}
+}
+
+// Ротация порциями перешифровывает и старые значения, и записанные открытым текстом
+func TestRotateKeys(t *testing.T) {
+	d := newTestDB(t) // Сначала без шифрования: значения пишутся открытым текстом
+	if _, err := d.CreateUserIfNotExist(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetMonthlyIncome(1, 85000); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.AddCredit(&models.Credit{UserID: 1, BankName: "Открытый", LoanAmount: 1000, DueDate: date(2026, 1, 1)}); err != nil {
+		t.Fatal(err)
+	}
+
+	d.cipher = newTestCipher(t, testKey(1))
+	for i := 0; i < 4; i++ {
+		if err := d.AddCredit(&models.Credit{UserID: 1, BankName: "Старый ключ", LoanAmount: 2000, DueDate: date(2026, 1, 1)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := d.CreateUserIfNotExist(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetMonthlyIncome(2, 120000); err != nil {
+		t.Fatal(err)
+	}
+
+	d.cipher = newTestCipher(t, testKey(2), testKey(1))
+	rotated, err := d.RotateKeys(2) // Порции меньше числа строк - проверяется повтор запроса
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rotated != 12 { // 2 пользователя, 5 кредитов и 5 записей журнала изменений о них
+		t.Errorf("RotateKeys = %d rows, want 12", rotated)
+	}
+	if again, err := d.RotateKeys(2); err != nil || again != 0 {
+		t.Errorf("second RotateKeys = %d, %v, want 0", again, err)
+	}
+
+	// Все значения - под новым ключом: читаются без прежнего
+	current := sealedPrefix + KeyID(testKey(2)) + ":"
+	for _, sealed := range sealedColumns {
+		for _, column := range sealed.columns {
+			var values []string
+			if err := d.Select(&values, "SELECT "+column+" FROM "+sealed.table); err != nil {
+				t.Fatal(err)
+			}
+			for _, value := range values {
+				if !strings.HasPrefix(value, current) {
+					t.Errorf("%s.%s = %q, want the new key", sealed.table, column, value)
+				}
+			}
+		}
+	}
+	d.cipher = newTestCipher(t, testKey(2))
+	credits, err := d.GetCreditsByUser(1)
+	if err != nil || len(credits) != 5 {
+		t.Fatalf("GetCreditsByUser = %d credits, %v", len(credits), err)
+	}
+	for _, user := range []struct {
+		id     int64
+		income float64
+	}{{1, 85000}, {2, 120000}} {
+		got, err := d.GetUser(user.id)
+		if err != nil || got.MonthlyIncome != user.income {
+			t.Errorf("user %d income = %v, %v, want %v", user.id, got, err, user.income)
+		}
+	}
+}
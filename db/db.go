@@ -1,32 +1,63 @@
 package db
 
 import (
-	"log"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
 	"time"
 
+	"DebtBot/banks"
 	"DebtBot/config"
+	"DebtBot/logging"
 	"DebtBot/models"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/mattn/go-sqlite3" // Импорт драйвера SQLite
 )
 
+var logger = logging.For("db")
+
+// Условие для кредитов не в корзине: нулевое deleted_at
+const notDeleted = "datetime(deleted_at) = '0001-01-01 00:00:00'"
+
 type DB struct {
 	*sqlx.DB
+	cipher *Cipher // Шифрование чувствительных колонок; nil - выключено
 }
 
 func NewDB(cfg *config.Config) *DB {
-	// SQLite connection string is just the database file path
-	connStr := cfg.DBName // Используем DBName из конфига как путь к файлу SQLite
-	if connStr == "" {
-		connStr = "debtbot.db" // Default SQLite file name if not provided in config
+	// Для SQLite строка подключения - это путь к файлу базы. Внешние ключи SQLite включаются
+	// для каждого соединения отдельно, поэтому - параметром строки подключения.
+	dsn := cfg.DBDSN
+	if !strings.Contains(dsn, "_foreign_keys=") && !strings.Contains(dsn, "_fk=") {
+		separator := "?"
+		if strings.Contains(dsn, "?") {
+			separator = "&"
+		}
+		dsn += separator + "_foreign_keys=on"
+	}
+	keys, err := cfg.EncryptionKeys()
+	if err != nil {
+		logger.Error("Could not read encryption keys", "err", err)
+		os.Exit(1)
+	}
+	dataCipher, err := NewCipher(keys)
+	if err != nil {
+		logger.Error("Invalid encryption key", "err", err)
+		os.Exit(1)
 	}
 
-	database, err := sqlx.Connect("sqlite3", connStr)
+	database, err := sqlx.Connect(cfg.DBDriver, dsn)
 	if err != nil {
-		log.Fatalf("Could not connect to database: %v", err)
+		logger.Error("Could not connect to database", "err", err)
+		os.Exit(1)
 	}
-	log.Println("Successfully connected to database!")
-	return &DB{database}
+	logger.Info("Successfully connected to database", "encryption_key", dataCipher.CurrentKeyID())
+	return &DB{DB: database, cipher: dataCipher}
 }
 
 // Инициализация таблиц (если их нет)
@@ -39,24 +70,341 @@ func (d *DB) InitSchema() error {
 
 		CREATE TABLE IF NOT EXISTS credits (
 			id INTEGER PRIMARY KEY AUTOINCREMENT, -- SERIAL PRIMARY KEY becomes INTEGER PRIMARY KEY AUTOINCREMENT
-			user_id INTEGER REFERENCES users(id), -- BIGINT becomes INTEGER for SQLite
+			user_id INTEGER REFERENCES users(id) ON DELETE CASCADE, -- BIGINT becomes INTEGER for SQLite
 			bank_name TEXT NOT NULL,
 			loan_amount DECIMAL NOT NULL, -- DECIMAL should work in SQLite, or you can use REAL/NUMERIC
 			due_date DATE NOT NULL,
 			created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
 		);
+
+		CREATE TABLE IF NOT EXISTS payments (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+			credit_id INTEGER REFERENCES credits(id) ON DELETE CASCADE,
+			amount DECIMAL NOT NULL,
+			paid_at DATE NOT NULL,
+			created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+		);
+
+		-- Отложенные напоминания: отправляются задачей планировщика, когда наступит remind_at
+		CREATE TABLE IF NOT EXISTS snoozes (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+			credit_id INTEGER NOT NULL REFERENCES credits(id) ON DELETE CASCADE,
+			remind_at DATETIME NOT NULL,
+			created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+		);
+
+		-- Групповые чаты: общий учет трат отдельно от личных кредитов
+		CREATE TABLE IF NOT EXISTS group_chats (
+			id INTEGER PRIMARY KEY, -- Telegram Chat ID
+			title TEXT NOT NULL DEFAULT '',
+			created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+		);
+
+		CREATE TABLE IF NOT EXISTS group_members (
+			group_id INTEGER NOT NULL REFERENCES group_chats(id) ON DELETE CASCADE,
+			user_id INTEGER NOT NULL, -- Telegram User ID, в users не заносится
+			username TEXT NOT NULL DEFAULT '',
+			first_name TEXT NOT NULL DEFAULT '',
+			joined_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+			PRIMARY KEY (group_id, user_id)
+		);
+
+		CREATE TABLE IF NOT EXISTS group_expenses (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			group_id INTEGER NOT NULL REFERENCES group_chats(id) ON DELETE CASCADE,
+			payer_id INTEGER NOT NULL,
+			amount DECIMAL NOT NULL,
+			description TEXT NOT NULL DEFAULT '',
+			created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+		);
+
+		CREATE TABLE IF NOT EXISTS group_expense_shares (
+			expense_id INTEGER NOT NULL REFERENCES group_expenses(id) ON DELETE CASCADE,
+			user_id INTEGER NOT NULL,
+			amount DECIMAL NOT NULL,
+			PRIMARY KEY (expense_id, user_id)
+		);
+
+		-- Долги между двумя пользователями бота, подтверждаемые обеими сторонами
+		CREATE TABLE IF NOT EXISTS debts (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			token TEXT NOT NULL UNIQUE,
+			creator_id INTEGER NOT NULL, -- На users не ссылается: долг остается у второй стороны после удаления аккаунта
+			lender_id INTEGER NOT NULL DEFAULT 0,
+			borrower_id INTEGER NOT NULL DEFAULT 0,
+			amount DECIMAL NOT NULL,
+			description TEXT NOT NULL DEFAULT '',
+			due_date DATE NOT NULL,
+			status TEXT NOT NULL,
+			settle_requested_by INTEGER NOT NULL DEFAULT 0,
+			created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+		);
+
+		CREATE TABLE IF NOT EXISTS debt_status_history (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			debt_id INTEGER NOT NULL REFERENCES debts(id) ON DELETE CASCADE,
+			status TEXT NOT NULL,
+			changed_by INTEGER NOT NULL,
+			changed_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+		);
+
+		-- Очередь уведомлений: отправляются фоновым отправителем с повторами при сбоях
+		CREATE TABLE IF NOT EXISTS outbox (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			chat_id INTEGER NOT NULL,
+			kind TEXT NOT NULL,
+			credit_id INTEGER NOT NULL DEFAULT 0,
+			text TEXT NOT NULL,
+			parse_mode TEXT NOT NULL DEFAULT '',
+			reply_markup TEXT NOT NULL DEFAULT '',
+			status TEXT NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			next_attempt_at DATETIME NOT NULL,
+			last_error TEXT NOT NULL DEFAULT '',
+			message_id INTEGER NOT NULL DEFAULT 0,
+			created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+			sent_at DATETIME NOT NULL DEFAULT '0001-01-01 00:00:00'
+		);
+		CREATE INDEX IF NOT EXISTS outbox_due ON outbox (status, next_attempt_at);
+
+		-- Коды приглашений (режим доступа invite)
+		CREATE TABLE IF NOT EXISTS invites (
+			code TEXT PRIMARY KEY,
+			created_by INTEGER NOT NULL,
+			max_uses INTEGER NOT NULL,
+			uses INTEGER NOT NULL DEFAULT 0,
+			expires_at DATETIME NOT NULL DEFAULT '0001-01-01 00:00:00',
+			created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+		);
+
+		-- Журнал действий администраторов
+		CREATE TABLE IF NOT EXISTS admin_log (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			admin_id INTEGER NOT NULL,
+			action TEXT NOT NULL,
+			target_id INTEGER NOT NULL DEFAULT 0,
+			details TEXT NOT NULL DEFAULT '',
+			created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+		);
+
+		-- Журнал изменений данных пользователей со снимками до и после (JSON)
+		CREATE TABLE IF NOT EXISTS audit_log (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			entity TEXT NOT NULL,
+			entity_id INTEGER NOT NULL,
+			action TEXT NOT NULL,
+			before_data TEXT NOT NULL DEFAULT '',
+			after_data TEXT NOT NULL DEFAULT '',
+			created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+		);
+		CREATE INDEX IF NOT EXISTS audit_log_user ON audit_log (user_id, entity, entity_id);
 	`)
+	if err != nil {
+		return err
+	}
+
+	// Колонки, добавленные после создания первых баз
+	columns := []struct{ table, column, definition string }{
+		{"users", "calendar_token", "TEXT NOT NULL DEFAULT ''"},
+		{"users", "monthly_income", "DECIMAL NOT NULL DEFAULT 0"},
+		{"users", "timezone", "TEXT NOT NULL DEFAULT ''"},
+		{"users", "weekly_digest", "BOOLEAN NOT NULL DEFAULT 0"},
+		{"users", "monthly_digest", "BOOLEAN NOT NULL DEFAULT 0"},
+		{"users", "last_weekly_digest", "TEXT NOT NULL DEFAULT ''"},
+		{"users", "last_monthly_digest", "TEXT NOT NULL DEFAULT ''"},
+		{"credits", "muted", "BOOLEAN NOT NULL DEFAULT 0"},
+		{"users", "language", "TEXT NOT NULL DEFAULT ''"},
+		{"users", "telegram_language", "TEXT NOT NULL DEFAULT ''"},
+		{"credits", "interest_rate", "DECIMAL NOT NULL DEFAULT 0"},
+		{"credits", "term_months", "INTEGER NOT NULL DEFAULT 0"},
+		{"credits", "bank_id", "TEXT NOT NULL DEFAULT ''"},
+		{"users", "blocked", "BOOLEAN NOT NULL DEFAULT 0"},
+		{"users", "banned", "BOOLEAN NOT NULL DEFAULT 0"},
+		{"users", "last_seen_at", "DATETIME NOT NULL DEFAULT '0001-01-01 00:00:00'"},
+		{"credits", "deleted_at", "DATETIME NOT NULL DEFAULT '0001-01-01 00:00:00'"},
+	}
+	for _, c := range columns {
+		if err := d.addColumnIfNotExists(c.table, c.column, c.definition); err != nil {
+			return err
+		}
+	}
+	if err := d.cascadeUserReferences(); err != nil {
+		return fmt.Errorf("error adding cascade to user references: %w", err)
+	}
+	return d.backfillBankIDs()
+}
+
+// Таблицы со ссылками на пользователя в актуальном виде. Базы, созданные раньше, приводятся
+// к этим описаниям пересозданием таблицы: SQLite не умеет менять ограничения существующей таблицы.
+// Описания включают колонки, добавленные через columns, - миграция выполняется после них.
+var userTables = []struct {
+	name    string
+	cascade bool   // true - ссылка на users с ON DELETE CASCADE, false - ссылки на users нет
+	columns string // Колонки и ограничения для CREATE TABLE
+}{
+	{"credits", true, `
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+		bank_name TEXT NOT NULL,
+		loan_amount DECIMAL NOT NULL,
+		due_date DATE NOT NULL,
+		created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+		muted BOOLEAN NOT NULL DEFAULT 0,
+		interest_rate DECIMAL NOT NULL DEFAULT 0,
+		term_months INTEGER NOT NULL DEFAULT 0,
+		bank_id TEXT NOT NULL DEFAULT '',
+		deleted_at DATETIME NOT NULL DEFAULT '0001-01-01 00:00:00'`},
+	{"payments", true, `
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+		credit_id INTEGER REFERENCES credits(id) ON DELETE CASCADE,
+		amount DECIMAL NOT NULL,
+		paid_at DATE NOT NULL,
+		created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))`},
+	{"snoozes", true, `
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+		credit_id INTEGER NOT NULL REFERENCES credits(id) ON DELETE CASCADE,
+		remind_at DATETIME NOT NULL,
+		created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))`},
+	{"debts", false, `
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		token TEXT NOT NULL UNIQUE,
+		creator_id INTEGER NOT NULL,
+		lender_id INTEGER NOT NULL DEFAULT 0,
+		borrower_id INTEGER NOT NULL DEFAULT 0,
+		amount DECIMAL NOT NULL,
+		description TEXT NOT NULL DEFAULT '',
+		due_date DATE NOT NULL,
+		status TEXT NOT NULL,
+		settle_requested_by INTEGER NOT NULL DEFAULT 0,
+		created_at DATETIME DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))`},
+}
+
+// Приведение ссылок на users к описаниям userTables в базах, созданных до них: таблица
+// создается заново под временным именем, данные копируются по именам колонок, старая удаляется.
+func (d *DB) cascadeUserReferences() error {
+	// PRAGMA foreign_keys действует на одно соединение и не меняется внутри транзакции,
+	// поэтому и PRAGMA, и пересоздание выполняются на одном закрепленном соединении
+	ctx := context.Background()
+	conn, err := d.DB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// Пока таблицы пересоздаются, ссылки на них не должны проверяться
+	if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+		return err
+	}
+	defer conn.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, table := range userTables {
+		var schema string
+		err := tx.QueryRowContext(ctx, "SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?", table.name).Scan(&schema)
+		if err != nil {
+			return err
+		}
+		references := strings.Contains(schema, "REFERENCES users(id)")
+		cascades := strings.Contains(schema, "REFERENCES users(id) ON DELETE CASCADE")
+		if table.cascade == cascades && (table.cascade || !references) {
+			continue
+		}
+
+		migrated := table.name + "_migrated"
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("CREATE TABLE %s (%s\n)", migrated, table.columns)); err != nil {
+			return fmt.Errorf("%s: %w", table.name, err)
+		}
+		var columns []string
+		rows, err := tx.QueryContext(ctx, "SELECT name FROM pragma_table_info(?)", migrated)
+		if err != nil {
+			return fmt.Errorf("%s: %w", table.name, err)
+		}
+		for rows.Next() {
+			var column string
+			if err := rows.Scan(&column); err != nil {
+				rows.Close()
+				return fmt.Errorf("%s: %w", table.name, err)
+			}
+			columns = append(columns, column)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("%s: %w", table.name, err)
+		}
+
+		list := strings.Join(columns, ", ")
+		statements := []string{
+			fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s", migrated, list, list, table.name),
+			fmt.Sprintf("DROP TABLE %s", table.name),
+			fmt.Sprintf("ALTER TABLE %s RENAME TO %s", migrated, table.name),
+		}
+		for _, statement := range statements {
+			if _, err := tx.ExecContext(ctx, statement); err != nil {
+				return fmt.Errorf("%s: %w", table.name, err)
+			}
+		}
+		logger.Info("Ссылки на пользователя приведены к актуальной схеме", "table", table.name, "cascade", table.cascade)
+	}
+	return tx.Commit()
+}
+
+// Привязка к справочнику банков кредитов, добавленных до его появления.
+// Справочник может пополняться, поэтому проверяются все кредиты без bank_id.
+// bank_id зашифрован, поэтому кредиты без банка отбираются после расшифровки.
+func (d *DB) backfillBankIDs() error {
+	credits, err := selectRows(d, d.openCredit, "SELECT * FROM credits")
+	if err != nil {
+		return err
+	}
+	for _, credit := range credits {
+		if credit.BankID != "" {
+			continue
+		}
+		if bank, ok := banks.Match(credit.BankName); ok {
+			bankID, err := d.cipher.Seal("credits.bank_id", bank.ID)
+			if err != nil {
+				return err
+			}
+			if _, err := d.Exec("UPDATE credits SET bank_id = ? WHERE id = ?", bankID, credit.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Добавление колонки в существующую таблицу, если ее там еще нет
+func (d *DB) addColumnIfNotExists(table, column, definition string) error {
+	var count int
+	err := d.Get(&count, "SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", table, column)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	_, err = d.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
 	return err
 }
 
 // Получение пользователя по ID
 func (d *DB) GetUser(userID int64) (*models.User, error) {
-	user := &models.User{}
-	err := d.Get(user, "SELECT * FROM users WHERE id = ?", userID) // Используем ? для параметров в SQLite
-	if err != nil {
-		return nil, err
-	}
-	return user, nil
+	return getRow(d, d.openUser, "SELECT * FROM users WHERE id = ?", userID) // Используем ? для параметров в SQLite
+}
+
+// Получение всех пользователей
+func (d *DB) GetUsers() ([]*models.User, error) {
+	return selectRows(d, d.openUser, "SELECT * FROM users ORDER BY id")
 }
 
 // Создание пользователя, если его нет
@@ -73,41 +421,754 @@ func (d *DB) CreateUserIfNotExist(userID int64) (*models.User, error) {
 	return d.GetUser(userID) // Получаем созданного пользователя
 }
 
+// Сохранение секрета ссылки на календарь пользователя
+func (d *DB) SetCalendarToken(userID int64, token string) error {
+	_, err := d.Exec("UPDATE users SET calendar_token = ? WHERE id = ?", token, userID)
+	return err
+}
+
+// Сохранение среднемесячного дохода пользователя (0 - удалить)
+func (d *DB) SetMonthlyIncome(userID int64, income float64) error {
+	sealed, err := d.cipher.SealFloat("users.monthly_income", income)
+	if err != nil {
+		return err
+	}
+	_, err = d.Exec("UPDATE users SET monthly_income = ? WHERE id = ?", sealed, userID)
+	return err
+}
+
+// Сохранение часового пояса пользователя
+func (d *DB) SetTimezone(userID int64, timezone string) error {
+	_, err := d.Exec("UPDATE users SET timezone = ? WHERE id = ?", timezone, userID)
+	return err
+}
+
+// Сохранение языка, выбранного пользователем (пусто - язык Telegram)
+func (d *DB) SetLanguage(userID int64, language string) error {
+	_, err := d.Exec("UPDATE users SET language = ? WHERE id = ?", language, userID)
+	return err
+}
+
+// Сохранение языка из настроек Telegram, если он изменился
+func (d *DB) SetTelegramLanguage(userID int64, code string) error {
+	_, err := d.Exec("UPDATE users SET telegram_language = ? WHERE id = ? AND telegram_language <> ?", code, userID, code)
+	return err
+}
+
+// Отметка, что пользователь заблокировал бота
+func (d *DB) SetUserBlocked(userID int64, blocked bool) error {
+	_, err := d.Exec("UPDATE users SET blocked = ? WHERE id = ? AND blocked <> ?", blocked, userID, blocked)
+	return err
+}
+
+// Отметка активности пользователя: написавший боту снова получает уведомления
+func (d *DB) MarkUserSeen(userID int64, now time.Time) error {
+	_, err := d.Exec("UPDATE users SET last_seen_at = ?, blocked = 0 WHERE id = ?", now.UTC(), userID)
+	return err
+}
+
+// Закрытие и открытие доступа к боту (/ban, /unban)
+func (d *DB) SetUserBanned(userID int64, banned bool) error {
+	_, err := d.Exec("UPDATE users SET banned = ? WHERE id = ?", banned, userID)
+	return err
+}
+
+// Получение получателей рассылки: пользователи, которые не заблокировали бота и не забанены
+func (d *DB) GetBroadcastRecipients() ([]int64, error) {
+	ids := []int64{}
+	err := d.Select(&ids, "SELECT id FROM users WHERE NOT blocked AND NOT banned ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// Включение и выключение недельной и месячной сводок
+func (d *DB) SetDigestSettings(userID int64, weekly, monthly bool) error {
+	_, err := d.Exec("UPDATE users SET weekly_digest = ?, monthly_digest = ? WHERE id = ?", weekly, monthly, userID)
+	return err
+}
+
+// Получение пользователей, подписанных хотя бы на одну сводку
+func (d *DB) GetDigestSubscribers() ([]*models.User, error) {
+	return selectRows(d, d.openUser, "SELECT * FROM users WHERE weekly_digest OR monthly_digest ORDER BY id")
+}
+
+// Отметка об отправленной сводке, чтобы не отправить ее дважды за день
+func (d *DB) MarkWeeklyDigestSent(userID int64, localDate string) error {
+	_, err := d.Exec("UPDATE users SET last_weekly_digest = ? WHERE id = ?", localDate, userID)
+	return err
+}
+
+func (d *DB) MarkMonthlyDigestSent(userID int64, localDate string) error {
+	_, err := d.Exec("UPDATE users SET last_monthly_digest = ? WHERE id = ?", localDate, userID)
+	return err
+}
+
+// Поиск пользователя по секрету ссылки на календарь
+func (d *DB) GetUserByCalendarToken(token string) (*models.User, error) {
+	return getRow(d, d.openUser, "SELECT * FROM users WHERE calendar_token = ? AND calendar_token != ''", token)
+}
+
 // Добавление кредита
 func (d *DB) AddCredit(credit *models.Credit) error {
-	_, err := d.NamedExec(`
-		INSERT INTO credits (user_id, bank_name, loan_amount, due_date)
-		VALUES (:user_id, :bank_name, :loan_amount, :due_date)
-	`, credit)
-	return err
+	return d.AddCredits([]*models.Credit{credit})
+}
+
+// Добавление нескольких кредитов одной транзакцией: либо все, либо ни одного
+func (d *DB) AddCredits(credits []*models.Credit) error {
+	tx, err := d.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, credit := range credits {
+		row, err := d.sealCredit(credit)
+		if err != nil {
+			return err
+		}
+		res, err := tx.NamedExec(`
+			INSERT INTO credits (user_id, bank_name, loan_amount, due_date, interest_rate, term_months, bank_id)
+			VALUES (:user_id, :bank_name, :loan_amount, :due_date, :interest_rate, :term_months, :bank_id)
+		`, row)
+		if err != nil {
+			return err
+		}
+		creditID, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		credit.ID = int(creditID)
+		if err := d.auditCredit(tx, models.AuditCreate, nil, credit); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
 }
 
 // Получение кредитов пользователя
 func (d *DB) GetCreditsByUser(userID int64) ([]*models.Credit, error) {
-	credits := []*models.Credit{}
-	log.Printf("DB.GetCreditsByUser: Запрос кредитов для userID: %d", userID) // <--- Добавили лог
-	err := d.Select(&credits, "SELECT * FROM credits WHERE user_id = ? ORDER BY due_date ASC", userID)
+	credits, err := selectRows(d, d.openCredit, "SELECT * FROM credits WHERE user_id = ? AND "+notDeleted+" ORDER BY due_date ASC", userID)
 	if err != nil {
-		log.Printf("DB.GetCreditsByUser: Ошибка при выполнении запроса: %v", err) // <--- Добавили лог ошибки
+		logger.Error("Ошибка при получении кредитов", "user_id", userID, "err", err)
 		return nil, err
 	}
-	log.Printf("DB.GetCreditsByUser: Найдено кредитов: %d", len(credits)) // <--- Добавили лог количества найденных кредитов
+	logger.Debug("Найдены кредиты", "user_id", userID, "count", len(credits))
 	return credits, nil
 }
 
-// Получение кредитов с датой платежа завтра
+// Получение кредита по ID; кредиты в корзине не возвращаются (sql.ErrNoRows)
+func (d *DB) GetCredit(creditID int) (*models.Credit, error) {
+	return getRow(d, d.openCredit, "SELECT * FROM credits WHERE id = ? AND "+notDeleted, creditID)
+}
+
+// Включение или отключение напоминаний по кредиту
+func (d *DB) SetCreditMuted(creditID int, muted bool) error {
+	return d.updateCredit(creditID, models.AuditUpdate, "UPDATE credits SET muted = ? WHERE id = ?", muted, creditID)
+}
+
+// Получение кредитов с платежом по графику завтра (кроме отключенных)
 func (d *DB) GetCreditsDueTomorrow() ([]*models.Credit, error) {
-	credits := []*models.Credit{}
-	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
-	err := d.Select(&credits, "SELECT * FROM credits WHERE due_date = ?", tomorrow) // Используем ? для параметров в SQLite
+	return d.getCreditsDueOn(time.Now().AddDate(0, 0, 1))
+}
+
+// Платежи по графику (см. schedule.ForCredit) считаются прямо в SQL, чтобы не расшифровывать
+// все кредиты: без срока платеж один - в due_date; со сроком - term_months платежей раз в месяц
+// в тот же день, что и due_date, а если такого дня в месяце нет - в последний день месяца.
+const creditDueOn = `date(due_date) <= ? AND (
+	(term_months <= 0 AND date(due_date) = ?) OR
+	(term_months > 0
+		AND ? - (CAST(strftime('%Y', due_date) AS INTEGER) * 12 + CAST(strftime('%m', due_date) AS INTEGER)) < term_months
+		AND (CAST(strftime('%d', due_date) AS INTEGER) = ? OR (? AND CAST(strftime('%d', due_date) AS INTEGER) > ?)))
+)`
+
+func (d *DB) getCreditsDueOn(day time.Time) ([]*models.Credit, error) {
+	date := day.Format("2006-01-02")
+	monthIndex := day.Year()*12 + int(day.Month())
+	lastDay := day.AddDate(0, 0, 1).Day() == 1
+	return selectRows(d, d.openCredit, "SELECT * FROM credits WHERE "+creditDueOn+" AND muted = 0 AND "+notDeleted,
+		date, date, monthIndex, day.Day(), lastDay, day.Day())
+}
+
+// Получение истории платежей пользователя по всем кредитам
+func (d *DB) GetPaymentsByUser(userID int64) ([]*models.Payment, error) {
+	return selectRows(d, d.openPayment, "SELECT * FROM payments WHERE user_id = ? ORDER BY paid_at ASC", userID)
+}
+
+// Добавление платежа по кредиту
+func (d *DB) AddPayment(payment *models.Payment) error {
+	row, err := d.sealPayment(payment)
+	if err != nil {
+		return err
+	}
+	_, err = d.NamedExec(`
+		INSERT INTO payments (user_id, credit_id, amount, paid_at)
+		VALUES (:user_id, :credit_id, :amount, :paid_at)
+	`, row)
+	return err
+}
+
+// Добавление платежа, если с момента since по кредиту еще не было платежей. Проверка и вставка -
+// один запрос, поэтому повторное нажатие кнопки "Оплачено" не запишет платеж дважды.
+// Возвращает false, если платеж уже был.
+func (d *DB) AddPaymentOnce(payment *models.Payment, since time.Time) (bool, error) {
+	row, err := d.sealPayment(payment)
+	if err != nil {
+		return false, err
+	}
+	res, err := d.Exec(`
+		INSERT INTO payments (user_id, credit_id, amount, paid_at)
+		SELECT ?, ?, ?, ?
+		WHERE NOT EXISTS (
+			SELECT 1 FROM payments WHERE credit_id = ? AND datetime(paid_at) >= datetime(?)
+		)
+	`, row.UserID, row.CreditID, row.Amount, row.PaidAt, row.CreditID, since.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return false, err
+	}
+	added, err := res.RowsAffected()
+	return added > 0, err
+}
+
+// Добавление отложенного напоминания
+func (d *DB) AddSnooze(snooze *models.Snooze) error {
+	_, err := d.NamedExec(`
+		INSERT INTO snoozes (user_id, credit_id, remind_at)
+		VALUES (:user_id, :credit_id, :remind_at)
+	`, snooze)
+	return err
+}
+
+// Получение отложенных напоминаний, время которых наступило
+func (d *DB) GetDueSnoozes(now time.Time) ([]*models.Snooze, error) {
+	snoozes := []*models.Snooze{}
+	err := d.Select(&snoozes, "SELECT * FROM snoozes WHERE datetime(remind_at) <= datetime(?) ORDER BY remind_at ASC", now.UTC().Format("2006-01-02 15:04:05"))
 	if err != nil {
 		return nil, err
 	}
-	return credits, nil
+	return snoozes, nil
 }
 
-// Удаление кредита по ID
+// Удаление отложенного напоминания (после отправки)
+func (d *DB) DeleteSnooze(snoozeID int) error {
+	_, err := d.Exec("DELETE FROM snoozes WHERE id = ?", snoozeID)
+	return err
+}
+
+// Удаление кредита в корзину: платежи и история сохраняются, кредит можно восстановить
 func (d *DB) DeleteCredit(creditID int) error {
-	_, err := d.Exec("DELETE FROM credits WHERE id = ?", creditID) // Используем ? для параметров в SQLite
+	return d.updateCredit(creditID, models.AuditDelete, "UPDATE credits SET deleted_at = ? WHERE id = ? AND "+notDeleted, time.Now().UTC(), creditID)
+}
+
+// Восстановление кредита пользователя из корзины. Возвращает false, если кредита в корзине нет.
+func (d *DB) RestoreCredit(userID int64, creditID int) (bool, error) {
+	credit, err := getRow(d, d.openCredit, "SELECT * FROM credits WHERE id = ? AND user_id = ? AND NOT "+notDeleted, creditID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	err = d.updateCredit(credit.ID, models.AuditRestore, "UPDATE credits SET deleted_at = ? WHERE id = ?", time.Time{}, credit.ID)
+	return err == nil, err
+}
+
+// Получение кредитов пользователя в корзине, последние удаленные - первыми
+func (d *DB) GetDeletedCredits(userID int64) ([]*models.Credit, error) {
+	return selectRows(d, d.openCredit, "SELECT * FROM credits WHERE user_id = ? AND NOT "+notDeleted+" ORDER BY deleted_at DESC", userID)
+}
+
+// Окончательное удаление кредитов, пролежавших в корзине дольше deletedBefore.
+// Платежи и отложенные напоминания по ним удаляются каскадом. Возвращает число удаленных кредитов.
+func (d *DB) PurgeDeletedCredits(deletedBefore time.Time) (int, error) {
+	credits, err := selectRows(d, d.openCredit, "SELECT * FROM credits WHERE NOT "+notDeleted+" AND datetime(deleted_at) < datetime(?)",
+		deletedBefore.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := d.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	purged := 0
+	for _, credit := range credits {
+		// Кредит могли восстановить, пока шла выборка
+		res, err := tx.Exec("DELETE FROM credits WHERE id = ? AND NOT "+notDeleted, credit.ID)
+		if err != nil {
+			return 0, err
+		}
+		if rows, err := res.RowsAffected(); err != nil || rows == 0 {
+			continue
+		}
+		if err := d.auditCredit(tx, models.AuditPurge, credit, nil); err != nil {
+			return 0, err
+		}
+		purged++
+	}
+	return purged, tx.Commit()
+}
+
+// updateCredit изменяет кредит запросом query и записывает изменение в audit_log в той же транзакции
+func (d *DB) updateCredit(creditID int, action, query string, args ...interface{}) error {
+	tx, err := d.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := d.getCreditTx(tx, creditID)
+	if err != nil {
+		return err
+	}
+	res, err := tx.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	if rows, err := res.RowsAffected(); err != nil || rows == 0 {
+		return err // Изменять нечего (например, кредит уже в корзине) - в журнал не пишем
+	}
+	after, err := d.getCreditTx(tx, creditID)
+	if err != nil {
+		return err
+	}
+	if err := d.auditCredit(tx, action, before, after); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// getCreditTx читает кредит (в том числе из корзины) внутри транзакции
+func (d *DB) getCreditTx(tx *sqlx.Tx, creditID int) (*models.Credit, error) {
+	row := &creditRow{}
+	if err := tx.Get(row, "SELECT * FROM credits WHERE id = ?", creditID); err != nil {
+		return nil, err
+	}
+	return d.openCredit(row)
+}
+
+// Создание или обновление группы (название чата могло измениться)
+func (d *DB) UpsertGroup(group *models.Group) error {
+	_, err := d.NamedExec(`
+		INSERT INTO group_chats (id, title) VALUES (:id, :title)
+		ON CONFLICT(id) DO UPDATE SET title = excluded.title
+	`, group)
+	return err
+}
+
+// Получение всех групп
+func (d *DB) GetGroups() ([]*models.Group, error) {
+	groups := []*models.Group{}
+	err := d.Select(&groups, "SELECT * FROM group_chats ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// Создание или обновление участника группы (username и имя могли измениться)
+func (d *DB) UpsertGroupMember(member *models.GroupMember) error {
+	_, err := d.NamedExec(`
+		INSERT INTO group_members (group_id, user_id, username, first_name)
+		VALUES (:group_id, :user_id, :username, :first_name)
+		ON CONFLICT(group_id, user_id) DO UPDATE SET username = excluded.username, first_name = excluded.first_name
+	`, member)
+	return err
+}
+
+// Получение участников группы
+func (d *DB) GetGroupMembers(groupID int64) ([]*models.GroupMember, error) {
+	members := []*models.GroupMember{}
+	err := d.Select(&members, "SELECT * FROM group_members WHERE group_id = ? ORDER BY joined_at ASC", groupID)
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// Поиск участника группы по username (без @, без учета регистра)
+func (d *DB) GetGroupMemberByUsername(groupID int64, username string) (*models.GroupMember, error) {
+	member := &models.GroupMember{}
+	err := d.Get(member, "SELECT * FROM group_members WHERE group_id = ? AND username = ? COLLATE NOCASE", groupID, username)
+	if err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// Добавление общей траты вместе с долями участников в одной транзакции
+func (d *DB) AddGroupExpense(expense *models.GroupExpense, shares []*models.GroupExpenseShare) error {
+	tx, err := d.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	row, err := d.sealGroupExpense(expense)
+	if err != nil {
+		return err
+	}
+	res, err := tx.NamedExec(`
+		INSERT INTO group_expenses (group_id, payer_id, amount, description)
+		VALUES (:group_id, :payer_id, :amount, :description)
+	`, row)
+	if err != nil {
+		return err
+	}
+	expenseID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	expense.ID = int(expenseID)
+
+	for _, share := range shares {
+		share.ExpenseID = expense.ID
+		row, err := d.sealGroupExpenseShare(share)
+		if err != nil {
+			return err
+		}
+		_, err = tx.NamedExec(`
+			INSERT INTO group_expense_shares (expense_id, user_id, amount)
+			VALUES (:expense_id, :user_id, :amount)
+		`, row)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Получение всех трат группы
+func (d *DB) GetGroupExpenses(groupID int64) ([]*models.GroupExpense, error) {
+	return selectRows(d, d.openGroupExpense, "SELECT * FROM group_expenses WHERE group_id = ? ORDER BY created_at ASC", groupID)
+}
+
+// Получение долей всех участников по тратам группы
+func (d *DB) GetGroupExpenseShares(groupID int64) ([]*models.GroupExpenseShare, error) {
+	return selectRows(d, d.openGroupExpenseShare, `
+		SELECT s.* FROM group_expense_shares s
+		JOIN group_expenses e ON e.id = s.expense_id
+		WHERE e.group_id = ?
+	`, groupID)
+}
+
+// Добавление долга вместе с первой записью в истории статусов
+func (d *DB) AddDebt(debt *models.Debt) error {
+	tx, err := d.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	row, err := d.sealDebt(debt)
+	if err != nil {
+		return err
+	}
+	res, err := tx.NamedExec(`
+		INSERT INTO debts (token, creator_id, lender_id, borrower_id, amount, description, due_date, status)
+		VALUES (:token, :creator_id, :lender_id, :borrower_id, :amount, :description, :due_date, :status)
+	`, row)
+	if err != nil {
+		return err
+	}
+	debtID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	debt.ID = int(debtID)
+
+	_, err = tx.Exec("INSERT INTO debt_status_history (debt_id, status, changed_by) VALUES (?, ?, ?)", debt.ID, debt.Status, debt.CreatorID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Сохранение изменений долга (стороны, статус) с записью в истории статусов
+func (d *DB) UpdateDebt(debt *models.Debt, changedBy int64) error {
+	tx, err := d.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.NamedExec(`
+		UPDATE debts SET lender_id = :lender_id, borrower_id = :borrower_id,
+			status = :status, settle_requested_by = :settle_requested_by
+		WHERE id = :id
+	`, debt)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec("INSERT INTO debt_status_history (debt_id, status, changed_by) VALUES (?, ?, ?)", debt.ID, debt.Status, changedBy)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Получение долга по ID
+func (d *DB) GetDebt(debtID int) (*models.Debt, error) {
+	return getRow(d, d.openDebt, "SELECT * FROM debts WHERE id = ?", debtID)
+}
+
+// Получение долга по токену приглашения
+func (d *DB) GetDebtByToken(token string) (*models.Debt, error) {
+	return getRow(d, d.openDebt, "SELECT * FROM debts WHERE token = ?", token)
+}
+
+// Получение непогашенных долгов, в которых участвует пользователь
+func (d *DB) GetOpenDebtsByUser(userID int64) ([]*models.Debt, error) {
+	return selectRows(d, d.openDebt, `
+		SELECT * FROM debts
+		WHERE (lender_id = ? OR borrower_id = ?) AND status IN (?, ?, ?)
+		ORDER BY due_date ASC
+	`, userID, userID, models.DebtPending, models.DebtActive, models.DebtSettleRequested)
+}
+
+// Получение подтвержденных долгов с датой возврата завтра
+func (d *DB) GetDebtsDueTomorrow() ([]*models.Debt, error) {
+	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+	return selectRows(d, d.openDebt, "SELECT * FROM debts WHERE date(due_date) = ? AND status IN (?, ?)", tomorrow, models.DebtActive, models.DebtSettleRequested)
+}
+
+// Получение истории статусов долга
+func (d *DB) GetDebtHistory(debtID int) ([]*models.DebtStatusChange, error) {
+	history := []*models.DebtStatusChange{}
+	err := d.Select(&history, "SELECT * FROM debt_status_history WHERE debt_id = ? ORDER BY id ASC", debtID)
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// Добавление уведомления в очередь отправки
+func (d *DB) AddOutboxMessage(msg *models.OutboxMessage) error {
+	row, err := d.sealOutboxMessage(msg)
+	if err != nil {
+		return err
+	}
+	res, err := d.NamedExec(`
+		INSERT INTO outbox (chat_id, kind, credit_id, text, parse_mode, reply_markup, status, next_attempt_at)
+		VALUES (:chat_id, :kind, :credit_id, :text, :parse_mode, :reply_markup, :status, :next_attempt_at)
+	`, row)
+	if err != nil {
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	msg.ID = int(id)
+	return nil
+}
+
+// Получение уведомлений, которые пора отправить, в порядке очереди. Чаты skipChats
+// пропускаются: в них пока нельзя писать, и их сообщения не должны занимать всю порцию.
+func (d *DB) GetDueOutboxMessages(now time.Time, limit int, skipChats []int64) ([]*models.OutboxMessage, error) {
+	query := "SELECT * FROM outbox WHERE status = ? AND datetime(next_attempt_at) <= datetime(?)"
+	args := []interface{}{models.OutboxPending, now.UTC().Format("2006-01-02 15:04:05")}
+	if len(skipChats) > 0 {
+		query += " AND chat_id NOT IN (?" + strings.Repeat(", ?", len(skipChats)-1) + ")"
+		for _, chatID := range skipChats {
+			args = append(args, chatID)
+		}
+	}
+	query += " ORDER BY next_attempt_at, id LIMIT ?"
+	return selectRows(d, d.openOutboxMessage, query, append(args, limit)...)
+}
+
+// Сохранение результата попытки отправки: статус, число попыток, время повтора, ошибка и ID сообщения
+func (d *DB) UpdateOutboxMessage(msg *models.OutboxMessage) error {
+	_, err := d.NamedExec(`
+		UPDATE outbox
+		SET status = :status, attempts = :attempts, next_attempt_at = :next_attempt_at,
+			last_error = :last_error, message_id = :message_id, sent_at = :sent_at
+		WHERE id = :id
+	`, msg)
+	return err
+}
+
+// Получение последних напоминаний о платежах пользователя с их статусом доставки
+func (d *DB) GetReminderDeliveries(userID int64, limit int) ([]*models.OutboxMessage, error) {
+	return selectRows(d, d.openOutboxMessage, `
+		SELECT * FROM outbox
+		WHERE chat_id = ? AND kind = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?
+	`, userID, models.OutboxReminder, limit)
+}
+
+// Запись действия администратора в журнал
+func (d *DB) AddAdminLog(entry *models.AdminLogEntry) error {
+	_, err := d.NamedExec(`
+		INSERT INTO admin_log (admin_id, action, target_id, details)
+		VALUES (:admin_id, :action, :target_id, :details)
+	`, entry)
+	return err
+}
+
+// Получение сводки по боту. activeSince - с какого момента пользователь считается активным,
+// dayStart - начало текущих суток для подсчета отправленных сегодня напоминаний.
+func (d *DB) GetStats(activeSince, dayStart time.Time) (*models.Stats, error) {
+	stats := &models.Stats{}
+	err := d.Get(stats, `
+		SELECT
+			(SELECT COUNT(*) FROM users) AS users,
+			(SELECT COUNT(*) FROM users WHERE NOT blocked AND datetime(last_seen_at) >= datetime(?)) AS active_users,
+			(SELECT COUNT(*) FROM users WHERE blocked) AS blocked_users,
+			(SELECT COUNT(*) FROM users WHERE banned) AS banned_users,
+			(SELECT COUNT(*) FROM credits WHERE `+notDeleted+`) AS credits,
+			(SELECT COUNT(*) FROM outbox WHERE kind = ? AND status = ? AND datetime(sent_at) >= datetime(?)) AS reminders_sent_today,
+			(SELECT COUNT(*) FROM outbox WHERE status = ?) AS outbox_pending
+	`, activeSince.UTC().Format("2006-01-02 15:04:05"),
+		models.OutboxReminder, models.OutboxSent, dayStart.UTC().Format("2006-01-02 15:04:05"),
+		models.OutboxPending)
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// Получение числа платежей пользователя
+func (d *DB) CountPaymentsByUser(userID int64) (int, error) {
+	var count int
+	err := d.Get(&count, "SELECT COUNT(*) FROM payments WHERE user_id = ?", userID)
+	return count, err
+}
+
+// Проверка, что пользователь зарегистрирован, без создания записи
+func (d *DB) UserExists(userID int64) (bool, error) {
+	var exists bool
+	err := d.Get(&exists, "SELECT EXISTS (SELECT 1 FROM users WHERE id = ?)", userID)
+	return exists, err
+}
+
+// Добавление кода приглашения
+func (d *DB) AddInvite(invite *models.Invite) error {
+	_, err := d.NamedExec(`
+		INSERT INTO invites (code, created_by, max_uses, expires_at)
+		VALUES (:code, :created_by, :max_uses, :expires_at)
+	`, invite)
 	return err
 }
+
+// Использование кода приглашения и регистрация пользователя в одной транзакции: использование
+// не пропадает, если пользователя не удалось создать. false - кода нет, он истек или исчерпан.
+func (d *DB) RedeemInvite(code string, userID int64, now time.Time) (bool, error) {
+	tx, err := d.Beginx()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`
+		UPDATE invites SET uses = uses + 1
+		WHERE code = ? AND uses < max_uses
+			AND (datetime(expires_at) = '0001-01-01 00:00:00' OR datetime(expires_at) > datetime(?))
+	`, code, now.UTC().Format("2006-01-02 15:04:05"))
+	if err != nil {
+		return false, err
+	}
+	if rows, err := res.RowsAffected(); err != nil || rows != 1 {
+		return false, err
+	}
+	if _, err := tx.Exec("INSERT OR IGNORE INTO users (id) VALUES (?)", userID); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+// Получение отложенных напоминаний пользователя
+func (d *DB) GetSnoozesByUser(userID int64) ([]*models.Snooze, error) {
+	snoozes := []*models.Snooze{}
+	err := d.Select(&snoozes, "SELECT * FROM snoozes WHERE user_id = ? ORDER BY remind_at", userID)
+	if err != nil {
+		return nil, err
+	}
+	return snoozes, nil
+}
+
+// Получение всех долгов пользователя, включая погашенные и отклоненные
+func (d *DB) GetAllDebtsByUser(userID int64) ([]*models.Debt, error) {
+	return selectRows(d, d.openDebt, `
+		SELECT * FROM debts
+		WHERE creator_id = ? OR lender_id = ? OR borrower_id = ?
+		ORDER BY created_at
+	`, userID, userID, userID)
+}
+
+// Получение участия пользователя в групповых чатах
+func (d *DB) GetGroupMembershipsByUser(userID int64) ([]*models.GroupMember, error) {
+	members := []*models.GroupMember{}
+	err := d.Select(&members, "SELECT * FROM group_members WHERE user_id = ? ORDER BY joined_at", userID)
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// Получение всех уведомлений пользователю с их статусом доставки
+func (d *DB) GetOutboxByChat(chatID int64) ([]*models.OutboxMessage, error) {
+	return selectRows(d, d.openOutboxMessage, "SELECT * FROM outbox WHERE chat_id = ? ORDER BY created_at, id", chatID)
+}
+
+// Безвозвратное удаление пользователя и всех его данных одной транзакцией.
+// Кредиты, платежи, отложенные напоминания и журнал изменений удаляются каскадом от users;
+// участие в группах, очередь уведомлений и долги без второй стороны - явно.
+//
+// Данные, общие с другими людьми, намеренно не удаляются, а обезличиваются: это записи
+// второй стороны долга и остальных участников группы, и без них у них разойдутся долги
+// и балансы. Во всех оставшихся ссылках ID пользователя заменяется: в долгах, их истории,
+// журнале администраторов и инвайтах - на 0, в тратах групп - на случайный отрицательный
+// ID (он не совпадет ни с одним пользователем Telegram, а балансы группы сойдутся).
+func (d *DB) DeleteUserData(userID int64) error {
+	anonymousID, err := anonymousMemberID()
+	if err != nil {
+		return err
+	}
+	tx, err := d.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	statements := []struct {
+		query string
+		args  []any
+	}{
+		{"DELETE FROM debts WHERE (lender_id = ? AND borrower_id = 0) OR (borrower_id = ? AND lender_id = 0)", []any{userID, userID}},
+		{"UPDATE debts SET creator_id = 0 WHERE creator_id = ?", []any{userID}},
+		{"UPDATE debts SET lender_id = 0 WHERE lender_id = ?", []any{userID}},
+		{"UPDATE debts SET borrower_id = 0 WHERE borrower_id = ?", []any{userID}},
+		{"UPDATE debts SET settle_requested_by = 0 WHERE settle_requested_by = ?", []any{userID}},
+		{"UPDATE debt_status_history SET changed_by = 0 WHERE changed_by = ?", []any{userID}},
+		{"UPDATE group_expenses SET payer_id = ? WHERE payer_id = ?", []any{anonymousID, userID}},
+		{"UPDATE group_expense_shares SET user_id = ? WHERE user_id = ?", []any{anonymousID, userID}},
+		{"DELETE FROM group_members WHERE user_id = ?", []any{userID}},
+		{"DELETE FROM outbox WHERE chat_id = ?", []any{userID}},
+		{"UPDATE admin_log SET admin_id = 0 WHERE admin_id = ?", []any{userID}},
+		{"UPDATE admin_log SET target_id = 0 WHERE target_id = ?", []any{userID}},
+		{"UPDATE invites SET created_by = 0 WHERE created_by = ?", []any{userID}},
+		{"DELETE FROM users WHERE id = ?", []any{userID}},
+	}
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement.query, statement.args...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// anonymousMemberID - случайный отрицательный ID вместо удаленного участника группы.
+// У каждого удаленного свой ID, чтобы доли двух удаленных в одной трате не слились.
+func anonymousMemberID() (int64, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1<<52))
+	if err != nil {
+		return 0, err
+	}
+	return -1 - n.Int64(), nil
+}
@@ -0,0 +1,223 @@
+package db
+
+import (
+	"bytes"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"DebtBot/models"
+	"DebtBot/schedule"
+	"github.com/jmoiron/sqlx"
+)
+
+// newTestDB открывает пустую базу во временном каталоге; с ключами - с шифрованием колонок
+func newTestDB(t *testing.T, keys ...[]byte) *DB {
+	t.Helper()
+	database, err := sqlx.Connect("sqlite3", filepath.Join(t.TempDir(), "test.db")+"?_foreign_keys=on")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.Close() })
+	dataCipher, err := NewCipher(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &DB{DB: database, cipher: dataCipher}
+	if err := d.InitSchema(); err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+// testKey - 32-байтный ключ шифрования, разный для разных n
+func testKey(n byte) []byte {
+	return bytes.Repeat([]byte{n}, 32)
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// Отбор кредитов с платежом в заданный день в SQL должен совпадать с графиком из schedule
+func TestCreditsDueOnMatchesSchedule(t *testing.T) {
+	d := newTestDB(t, testKey(1))
+	if _, err := d.CreateUserIfNotExist(1); err != nil {
+		t.Fatal(err)
+	}
+	credits := []*models.Credit{
+		{UserID: 1, BankName: "Без срока", LoanAmount: 100000, DueDate: date(2026, time.March, 15)},
+		{UserID: 1, BankName: "31 число", LoanAmount: 90000, TermMonths: 14, DueDate: date(2026, time.January, 31)},
+		{UserID: 1, BankName: "30 число", LoanAmount: 90000, InterestRate: 12, TermMonths: 24, DueDate: date(2027, time.November, 30)},
+		{UserID: 1, BankName: "Три года", LoanAmount: 500000, InterestRate: 12, TermMonths: 36, DueDate: date(2026, time.February, 1)},
+		{UserID: 1, BankName: "29 февраля", LoanAmount: 10000, TermMonths: 13, DueDate: date(2028, time.February, 29)},
+	}
+	muted := &models.Credit{UserID: 1, BankName: "Отключен", LoanAmount: 1000, TermMonths: 12, DueDate: date(2026, time.January, 10)}
+	deleted := &models.Credit{UserID: 1, BankName: "В корзине", LoanAmount: 1000, TermMonths: 12, DueDate: date(2026, time.January, 10)}
+	if err := d.AddCredits(append(slices.Clone(credits), muted, deleted)); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := d.GetCreditsByUser(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, credit := range stored {
+		switch credit.BankName {
+		case muted.BankName:
+			err = d.SetCreditMuted(credit.ID, true)
+		case deleted.BankName:
+			err = d.DeleteCredit(credit.ID)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for day := date(2025, time.December, 1); day.Before(date(2030, time.January, 1)); day = day.AddDate(0, 0, 1) {
+		var want []string
+		for _, credit := range credits {
+			if installment, ok := schedule.Next(credit, day); ok && installment.Date.Equal(day) {
+				want = append(want, credit.BankName)
+			}
+		}
+		due, err := d.getCreditsDueOn(day)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, credit := range due {
+			got = append(got, credit.BankName)
+		}
+		slices.Sort(want)
+		slices.Sort(got)
+		if !slices.Equal(got, want) {
+			t.Errorf("%s: due %q, want %q", day.Format(time.DateOnly), got, want)
+		}
+	}
+}
+
+// После удаления аккаунта ID пользователя не остается ни в одной колонке, а общие данные
+// второй стороны (долг, траты группы) сохраняются
+func TestDeleteUserDataLeavesNoReferences(t *testing.T) {
+	const userID, otherID = 1001, 2002
+	d := newTestDB(t, testKey(1))
+	for _, id := range []int64{userID, otherID} {
+		if _, err := d.CreateUserIfNotExist(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	check := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	check(d.SetMonthlyIncome(userID, 85000))
+	check(d.AddCredit(&models.Credit{UserID: userID, BankName: "Сбер", LoanAmount: 1000, DueDate: date(2026, time.January, 1)}))
+	credits, err := d.GetCreditsByUser(userID)
+	check(err)
+	check(d.AddPayment(&models.Payment{UserID: userID, CreditID: credits[0].ID, Amount: 100, PaidAt: date(2026, time.January, 1)}))
+	check(d.AddSnooze(&models.Snooze{UserID: userID, CreditID: credits[0].ID, RemindAt: date(2026, time.January, 1)}))
+
+	shared := &models.Debt{Token: "shared", CreatorID: userID, LenderID: userID, BorrowerID: otherID, Amount: 500, Status: models.DebtActive}
+	check(d.AddDebt(shared))
+	shared.SettleRequestedBy = userID
+	check(d.UpdateDebt(shared, userID))
+	check(d.AddDebt(&models.Debt{Token: "own", CreatorID: userID, LenderID: userID, Amount: 300, Status: models.DebtPending}))
+
+	check(d.UpsertGroup(&models.Group{ID: -100, Title: "Группа"}))
+	for _, id := range []int64{userID, otherID} {
+		check(d.UpsertGroupMember(&models.GroupMember{GroupID: -100, UserID: id}))
+	}
+	check(d.AddGroupExpense(&models.GroupExpense{GroupID: -100, PayerID: userID, Amount: 1000}, []*models.GroupExpenseShare{
+		{UserID: userID, Amount: 500}, {UserID: otherID, Amount: 500},
+	}))
+	check(d.AddOutboxMessage(&models.OutboxMessage{ChatID: userID, Kind: "reminder", Text: "напоминание"}))
+	check(d.AddAdminLog(&models.AdminLogEntry{AdminID: userID, Action: "stats"}))
+	check(d.AddAdminLog(&models.AdminLogEntry{AdminID: otherID, Action: "ban", TargetID: userID}))
+	check(d.AddInvite(&models.Invite{Code: "abc", CreatedBy: userID, MaxUses: 1}))
+
+	check(d.DeleteUserData(userID))
+
+	// Поиск ID во всех колонках всех таблиц
+	var tables []string
+	check(d.Select(&tables, "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'"))
+	for _, table := range tables {
+		var columns []string
+		check(d.Select(&columns, "SELECT name FROM pragma_table_info(?)", table))
+		for _, column := range columns {
+			var count int
+			check(d.Get(&count, "SELECT COUNT(*) FROM "+table+" WHERE "+column+" = ?", userID))
+			if count > 0 {
+				t.Errorf("%s.%s still references the deleted user in %d rows", table, column, count)
+			}
+		}
+	}
+
+	// Долг и траты второй стороны остались, балансы группы сходятся
+	debt, err := d.GetDebt(shared.ID)
+	check(err)
+	if debt.BorrowerID != otherID || debt.LenderID != 0 || debt.Amount != 500 {
+		t.Errorf("shared debt after deletion = %+v", debt)
+	}
+	var total float64
+	var shares []string
+	check(d.Select(&shares, "SELECT amount FROM group_expense_shares"))
+	for _, share := range shares {
+		amount, err := d.cipher.OpenFloat("group_expense_shares.amount", share)
+		check(err)
+		total += amount
+	}
+	if len(shares) != 2 || total != 1000 {
+		t.Errorf("group shares after deletion = %d rows, %v total", len(shares), total)
+	}
+}
+
+// Повторная отметка "Оплачено" по тому же напоминанию не добавляет второй платеж
+func TestAddPaymentOnce(t *testing.T) {
+	d := newTestDB(t, testKey(1))
+	if _, err := d.CreateUserIfNotExist(1); err != nil {
+		t.Fatal(err)
+	}
+	for _, bank := range []string{"Сбер", "ВТБ"} {
+		if err := d.AddCredit(&models.Credit{UserID: 1, BankName: bank, LoanAmount: 1000, DueDate: date(2026, time.November, 1)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	credits, err := d.GetCreditsByUser(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, second := credits[0].ID, credits[1].ID
+	// Платеж до отправки напоминания не мешает отметить новый
+	if err := d.AddPayment(&models.Payment{UserID: 1, CreditID: first, Amount: 100, PaidAt: date(2026, time.October, 1)}); err != nil {
+		t.Fatal(err)
+	}
+
+	sentAt := time.Date(2026, time.October, 30, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		creditID int
+		paidAt   time.Time
+		want     bool
+	}{
+		{"первое нажатие", first, sentAt.Add(time.Minute), true},
+		{"повторное нажатие", first, sentAt.Add(time.Minute + time.Second), false},
+		{"другой кредит", second, sentAt.Add(time.Minute), true},
+	}
+	for _, tt := range tests {
+		added, err := d.AddPaymentOnce(&models.Payment{UserID: 1, CreditID: tt.creditID, Amount: 500, PaidAt: tt.paidAt}, sentAt)
+		if err != nil || added != tt.want {
+			t.Errorf("%s: AddPaymentOnce = %v, %v, want %v", tt.name, added, err, tt.want)
+		}
+	}
+
+	payments, err := d.GetPaymentsByUser(1)
+	if err != nil || len(payments) != 3 {
+		t.Fatalf("GetPaymentsByUser = %d payments, %v, want 3", len(payments), err)
+	}
+	if payments[1].Amount != 500 { // Сумма зашифрована и читается обратно
+		t.Errorf("payment amount = %v, want 500", payments[1].Amount)
+	}
+}
@@ -0,0 +1,349 @@
+package db
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"DebtBot/models"
+)
+
+// Строки таблиц с зашифрованными колонками. Поля строки перекрывают одноименные поля модели
+// (sqlx берет поле с наименьшей вложенностью), поэтому запросы и именованные параметры
+// остаются прежними, а шифротекст не попадает в модели. Суммы и итоги по зашифрованным
+// колонкам SQL посчитать не может - они считаются после расшифровки, в Go.
+
+type userRow struct {
+	models.User
+	MonthlyIncome string `db:"monthly_income"`
+}
+
+type creditRow struct {
+	models.Credit
+	BankName   string `db:"bank_name"`
+	LoanAmount string `db:"loan_amount"`
+	BankID     string `db:"bank_id"`
+}
+
+type paymentRow struct {
+	models.Payment
+	Amount string `db:"amount"`
+}
+
+type groupExpenseRow struct {
+	models.GroupExpense
+	Amount      string `db:"amount"`
+	Description string `db:"description"`
+}
+
+type groupExpenseShareRow struct {
+	models.GroupExpenseShare
+	Amount string `db:"amount"`
+}
+
+type debtRow struct {
+	models.Debt
+	Amount      string `db:"amount"`
+	Description string `db:"description"`
+}
+
+type outboxRow struct {
+	models.OutboxMessage
+	Text string `db:"text"`
+}
+
+// Зашифрованные колонки по таблицам - для перешифрования новым ключом
+var sealedColumns = []struct {
+	table   string
+	columns []string
+}{
+	{"users", []string{"monthly_income"}},
+	{"credits", []string{"bank_name", "loan_amount", "bank_id"}},
+	{"payments", []string{"amount"}},
+	{"group_expenses", []string{"amount", "description"}},
+	{"group_expense_shares", []string{"amount"}},
+	{"debts", []string{"amount", "description"}},
+	{"outbox", []string{"text"}},
+	{"audit_log", []string{"before_data", "after_data"}},
+}
+
+func (d *DB) openUser(row *userRow) (*models.User, error) {
+	user := row.User
+	var err error
+	if user.MonthlyIncome, err = d.cipher.OpenFloat("users.monthly_income", row.MonthlyIncome); err != nil {
+		return nil, fmt.Errorf("user %d: %w", user.ID, err)
+	}
+	return &user, nil
+}
+
+func (d *DB) sealCredit(credit *models.Credit) (*creditRow, error) {
+	row := &creditRow{Credit: *credit}
+	var err error
+	if row.BankName, err = d.cipher.Seal("credits.bank_name", credit.BankName); err != nil {
+		return nil, err
+	}
+	if row.LoanAmount, err = d.cipher.SealFloat("credits.loan_amount", credit.LoanAmount); err != nil {
+		return nil, err
+	}
+	if row.BankID, err = d.cipher.Seal("credits.bank_id", credit.BankID); err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
+func (d *DB) openCredit(row *creditRow) (*models.Credit, error) {
+	credit := row.Credit
+	var err error
+	if credit.BankName, err = d.cipher.Open("credits.bank_name", row.BankName); err != nil {
+		return nil, fmt.Errorf("credit %d: %w", credit.ID, err)
+	}
+	if credit.LoanAmount, err = d.cipher.OpenFloat("credits.loan_amount", row.LoanAmount); err != nil {
+		return nil, fmt.Errorf("credit %d: %w", credit.ID, err)
+	}
+	if credit.BankID, err = d.cipher.Open("credits.bank_id", row.BankID); err != nil {
+		return nil, fmt.Errorf("credit %d: %w", credit.ID, err)
+	}
+	return &credit, nil
+}
+
+func (d *DB) sealPayment(payment *models.Payment) (*paymentRow, error) {
+	amount, err := d.cipher.SealFloat("payments.amount", payment.Amount)
+	if err != nil {
+		return nil, err
+	}
+	return &paymentRow{Payment: *payment, Amount: amount}, nil
+}
+
+func (d *DB) openPayment(row *paymentRow) (*models.Payment, error) {
+	payment := row.Payment
+	var err error
+	if payment.Amount, err = d.cipher.OpenFloat("payments.amount", row.Amount); err != nil {
+		return nil, fmt.Errorf("payment %d: %w", payment.ID, err)
+	}
+	return &payment, nil
+}
+
+func (d *DB) sealGroupExpense(expense *models.GroupExpense) (*groupExpenseRow, error) {
+	row := &groupExpenseRow{GroupExpense: *expense}
+	var err error
+	if row.Amount, err = d.cipher.SealFloat("group_expenses.amount", expense.Amount); err != nil {
+		return nil, err
+	}
+	if row.Description, err = d.cipher.Seal("group_expenses.description", expense.Description); err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
+func (d *DB) openGroupExpense(row *groupExpenseRow) (*models.GroupExpense, error) {
+	expense := row.GroupExpense
+	var err error
+	if expense.Amount, err = d.cipher.OpenFloat("group_expenses.amount", row.Amount); err != nil {
+		return nil, fmt.Errorf("group expense %d: %w", expense.ID, err)
+	}
+	if expense.Description, err = d.cipher.Open("group_expenses.description", row.Description); err != nil {
+		return nil, fmt.Errorf("group expense %d: %w", expense.ID, err)
+	}
+	return &expense, nil
+}
+
+func (d *DB) sealGroupExpenseShare(share *models.GroupExpenseShare) (*groupExpenseShareRow, error) {
+	amount, err := d.cipher.SealFloat("group_expense_shares.amount", share.Amount)
+	if err != nil {
+		return nil, err
+	}
+	return &groupExpenseShareRow{GroupExpenseShare: *share, Amount: amount}, nil
+}
+
+func (d *DB) openGroupExpenseShare(row *groupExpenseShareRow) (*models.GroupExpenseShare, error) {
+	share := row.GroupExpenseShare
+	var err error
+	if share.Amount, err = d.cipher.OpenFloat("group_expense_shares.amount", row.Amount); err != nil {
+		return nil, fmt.Errorf("share of expense %d: %w", share.ExpenseID, err)
+	}
+	return &share, nil
+}
+
+func (d *DB) sealDebt(debt *models.Debt) (*debtRow, error) {
+	row := &debtRow{Debt: *debt}
+	var err error
+	if row.Amount, err = d.cipher.SealFloat("debts.amount", debt.Amount); err != nil {
+		return nil, err
+	}
+	if row.Description, err = d.cipher.Seal("debts.description", debt.Description); err != nil {
+		return nil, err
+	}
+	return row, nil
+}
+
+func (d *DB) openDebt(row *debtRow) (*models.Debt, error) {
+	debt := row.Debt
+	var err error
+	if debt.Amount, err = d.cipher.OpenFloat("debts.amount", row.Amount); err != nil {
+		return nil, fmt.Errorf("debt %d: %w", debt.ID, err)
+	}
+	if debt.Description, err = d.cipher.Open("debts.description", row.Description); err != nil {
+		return nil, fmt.Errorf("debt %d: %w", debt.ID, err)
+	}
+	return &debt, nil
+}
+
+func (d *DB) sealOutboxMessage(msg *models.OutboxMessage) (*outboxRow, error) {
+	text, err := d.cipher.Seal("outbox.text", msg.Text)
+	if err != nil {
+		return nil, err
+	}
+	return &outboxRow{OutboxMessage: *msg, Text: text}, nil
+}
+
+func (d *DB) openOutboxMessage(row *outboxRow) (*models.OutboxMessage, error) {
+	msg := row.OutboxMessage
+	var err error
+	if msg.Text, err = d.cipher.Open("outbox.text", row.Text); err != nil {
+		return nil, fmt.Errorf("outbox message %d: %w", msg.ID, err)
+	}
+	return &msg, nil
+}
+
+// selectRows выполняет запрос к таблице с зашифрованными колонками и расшифровывает строки
+func selectRows[R, M any](d *DB, open func(*R) (*M, error), query string, args ...interface{}) ([]*M, error) {
+	rows := []*R{}
+	if err := d.Select(&rows, query, args...); err != nil {
+		return nil, err
+	}
+	result := make([]*M, 0, len(rows))
+	for _, row := range rows {
+		item, err := open(row)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// getRow выполняет запрос одной строки таблицы с зашифрованными колонками и расшифровывает ее
+func getRow[R, M any](d *DB, open func(*R) (*M, error), query string, args ...interface{}) (*M, error) {
+	row := new(R)
+	if err := d.Get(row, query, args...); err != nil {
+		return nil, err
+	}
+	return open(row)
+}
+
+// RotateKeys перешифровывает текущим ключом все значения, зашифрованные прежними ключами
+// или записанные открытым текстом. Строки обрабатываются порциями по batchSize, каждая
+// порция - отдельной транзакцией, поэтому прерванную ротацию можно просто запустить заново.
+// Возвращает число перешифрованных строк.
+func (d *DB) RotateKeys(batchSize int) (int, error) {
+	if d.cipher == nil {
+		return 0, ErrNoKey
+	}
+	current := sealedPrefix + d.cipher.CurrentKeyID() + ":%"
+	total := 0
+	for _, sealed := range sealedColumns {
+		conditions := make([]string, len(sealed.columns))
+		for i, column := range sealed.columns {
+			conditions[i] = column + " NOT LIKE ?"
+		}
+		query := fmt.Sprintf("SELECT rowid, %s FROM %s WHERE %s LIMIT ?",
+			strings.Join(sealed.columns, ", "), sealed.table, strings.Join(conditions, " OR "))
+		assignments := make([]string, len(sealed.columns))
+		for i, column := range sealed.columns {
+			assignments[i] = column + " = ?"
+		}
+		update := fmt.Sprintf("UPDATE %s SET %s WHERE rowid = ?", sealed.table, strings.Join(assignments, ", "))
+
+		args := make([]interface{}, 0, len(sealed.columns)+1)
+		for range sealed.columns {
+			args = append(args, current)
+		}
+		args = append(args, batchSize)
+
+		for {
+			batch, err := d.loadBatch(query, args)
+			if err != nil {
+				return total, fmt.Errorf("%s: %w", sealed.table, err)
+			}
+			if len(batch) == 0 {
+				break
+			}
+			if err := d.resealBatch(sealed.table, sealed.columns, update, batch); err != nil {
+				return total, fmt.Errorf("%s: %w", sealed.table, err)
+			}
+			total += len(batch)
+			logger.Info("Перешифрована порция строк", "table", sealed.table, "rows", len(batch), "total", total)
+		}
+	}
+	return total, nil
+}
+
+// loadBatch читает порцию строк: rowid и значения колонок строками.
+// Строки читаются целиком до записи, чтобы не держать чтение открытым во время транзакции.
+func (d *DB) loadBatch(query string, args []interface{}) ([][]string, error) {
+	rows, err := d.Queryx(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var batch [][]string
+	for rows.Next() {
+		values, err := rows.SliceScan()
+		if err != nil {
+			return nil, err
+		}
+		row := make([]string, len(values))
+		for i, value := range values {
+			row[i] = columnString(value)
+		}
+		batch = append(batch, row)
+	}
+	return batch, rows.Err()
+}
+
+// resealBatch расшифровывает значения порции прежними ключами и записывает их зашифрованными текущим
+func (d *DB) resealBatch(table string, columns []string, update string, batch [][]string) error {
+	tx, err := d.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, row := range batch {
+		args := make([]interface{}, 0, len(row))
+		for i, column := range columns {
+			name := table + "." + column
+			plaintext, err := d.cipher.Open(name, row[i+1])
+			if err != nil {
+				return fmt.Errorf("row %s: %w", row[0], err)
+			}
+			value, err := d.cipher.Seal(name, plaintext)
+			if err != nil {
+				return err
+			}
+			args = append(args, value)
+		}
+		args = append(args, row[0])
+		if _, err := tx.Exec(update, args...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// columnString приводит значение колонки SQLite к строке так же, как при чтении в строковое поле
+func columnString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,36 @@
+package db
+
+import (
+	"database/sql"
+	"time"
+
+	"DebtBot/metrics"
+)
+
+// Запросы через DB учитываются в метриках по виду операции. Методы перекрывают одноименные
+// методы sqlx.DB, поэтому существующие запросы менять не нужно; запросы внутри транзакций
+// (tx.NamedExec и т.п.) не учитываются.
+
+// Get выполняет запрос, возвращающий одну строку
+func (d *DB) Get(dest interface{}, query string, args ...interface{}) error {
+	defer metrics.ObserveQuery("get", time.Now())
+	return d.DB.Get(dest, query, args...)
+}
+
+// Select выполняет запрос, возвращающий несколько строк
+func (d *DB) Select(dest interface{}, query string, args ...interface{}) error {
+	defer metrics.ObserveQuery("select", time.Now())
+	return d.DB.Select(dest, query, args...)
+}
+
+// Exec выполняет запрос без результата
+func (d *DB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	defer metrics.ObserveQuery("exec", time.Now())
+	return d.DB.Exec(query, args...)
+}
+
+// NamedExec выполняет запрос с именованными параметрами
+func (d *DB) NamedExec(query string, arg interface{}) (sql.Result, error) {
+	defer metrics.ObserveQuery("exec", time.Now())
+	return d.DB.NamedExec(query, arg)
+}
@@ -0,0 +1,75 @@
+// Package digest собирает еженедельные и ежемесячные сводки платежей пользователя
+package digest
+
+import (
+	"fmt"
+	"time"
+
+	"DebtBot/i18n"
+	"DebtBot/models"
+	"DebtBot/schedule"
+	"DebtBot/summary"
+	"DebtBot/utils"
+)
+
+// Weekly - платежи на неделю, начинающуюся в день now (обычно понедельник).
+// Возвращает пустую строку, если на неделе нет платежей.
+func Weekly(tr *i18n.Localizer, credits []*models.Credit, now time.Time) string {
+	from := dateOf(now)
+	to := from.AddDate(0, 0, 7)
+
+	text := ""
+	total := 0.0
+	for _, installment := range schedule.ForCredits(credits) {
+		if installment.Date.Before(from) || !installment.Date.Before(to) {
+			continue
+		}
+		text += fmt.Sprintf("%s %s - %s - %s\n", tr.Weekday(installment.Date.Weekday()), installment.Date.Format("02.01"),
+			utils.EscapeMarkdown(installment.BankName), utils.FormatMoney(installment.Amount))
+		total += installment.Amount
+	}
+	if text == "" {
+		return ""
+	}
+
+	return tr.T("digest.weekly", from.Format("02.01"), to.AddDate(0, 0, -1).Format("02.01"), text, utils.FormatMoney(total))
+}
+
+// Monthly - итоги прошлого месяца и план на месяц, в который попадает now.
+// Возвращает пустую строку, если у пользователя нет кредитов.
+func Monthly(tr *i18n.Localizer, credits []*models.Credit, payments []*models.Payment, income float64, now time.Time) string {
+	if len(credits) == 0 {
+		return ""
+	}
+
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	prevStart := monthStart.AddDate(0, -1, 0)
+
+	paidLastMonth := 0.0
+	for _, payment := range payments {
+		if !payment.PaidAt.Before(prevStart) && payment.PaidAt.Before(monthStart) {
+			paidLastMonth += payment.Amount
+		}
+	}
+
+	count := 0
+	for _, installment := range schedule.ForCredits(credits) {
+		if !installment.Date.Before(monthStart) && installment.Date.Before(monthStart.AddDate(0, 1, 0)) {
+			count++
+		}
+	}
+
+	s := summary.Calculate(credits, payments, income, now)
+	text := tr.T("digest.monthly_title", tr.Month(now.Month()), now.Year())
+	text += tr.N("digest.monthly_payments", count, utils.FormatMoney(s.ThisMonth))
+	text += tr.T("digest.monthly_paid", tr.Month(prevStart.Month()), utils.FormatMoney(paidLastMonth))
+	text += tr.T("digest.monthly_outstanding", utils.FormatMoney(s.Outstanding))
+	if s.Level != summary.LevelUnknown {
+		text += tr.T("digest.monthly_load", s.Load*100)
+	}
+	return text
+}
+
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
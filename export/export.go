@@ -0,0 +1,174 @@
+// Package export формирует выгрузки кредитов, графика платежей и истории платежей
+// в CSV и XLSX. Пакет не зависит от Telegram и используется как ботом, так и CLI.
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"time"
+
+	"DebtBot/db"
+	"DebtBot/models"
+	"DebtBot/schedule"
+	"github.com/xuri/excelize/v2"
+)
+
+// Data - все, что выгружается для одного пользователя
+type Data struct {
+	Credits      []*models.Credit
+	Installments []schedule.Installment
+	Payments     []*models.Payment
+}
+
+// Load собирает из базы данные для выгрузки пользователя
+func Load(database *db.DB, userID int64) (Data, error) {
+	credits, err := database.GetCreditsByUser(userID)
+	if err != nil {
+		return Data{}, err
+	}
+	payments, err := database.GetPaymentsByUser(userID)
+	if err != nil {
+		return Data{}, err
+	}
+	return Data{
+		Credits:      credits,
+		Installments: schedule.ForCredits(credits),
+		Payments:     payments,
+	}, nil
+}
+
+// Table - одна таблица выгрузки (лист XLSX или отдельный CSV-файл)
+type Table struct {
+	Name   string // Имя листа и основа имени файла
+	Header []string
+	Rows   [][]any // float64 и time.Time сохраняются в XLSX как числа и даты
+}
+
+// Tables раскладывает данные пользователя по таблицам выгрузки
+func Tables(data Data) []Table {
+	credits := Table{
+		Name:   "credits",
+		Header: []string{"ID", "Банк", "Сумма", "Ставка, %", "Срок, мес.", "Дата платежа", "Добавлен"},
+	}
+	for _, credit := range data.Credits {
+		credits.Rows = append(credits.Rows, []any{credit.ID, credit.BankName, credit.LoanAmount, credit.InterestRate, credit.TermMonths, credit.DueDate, credit.CreatedAt})
+	}
+
+	installments := Table{
+		Name:   "schedule",
+		Header: []string{"ID кредита", "Банк", "Номер платежа", "Дата", "Сумма"},
+	}
+	for _, installment := range data.Installments {
+		installments.Rows = append(installments.Rows, []any{installment.CreditID, installment.BankName, installment.Number, installment.Date, installment.Amount})
+	}
+
+	payments := Table{
+		Name:   "payments",
+		Header: []string{"ID", "ID кредита", "Сумма", "Дата оплаты"},
+	}
+	for _, payment := range data.Payments {
+		payments.Rows = append(payments.Rows, []any{payment.ID, payment.CreditID, payment.Amount, payment.PaidAt})
+	}
+
+	return []Table{credits, installments, payments}
+}
+
+// CSV записывает таблицу в CSV, который без настроек открывается в русском Excel:
+// UTF-8 с BOM и ";" в качестве разделителя.
+func CSV(table Table) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("\ufeff")
+
+	writer := csv.NewWriter(&buf)
+	writer.Comma = ';'
+	if err := writer.Write(table.Header); err != nil {
+		return nil, err
+	}
+	for _, row := range table.Rows {
+		record := make([]string, len(row))
+		for i, value := range row {
+			record[i] = formatValue(value)
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// XLSX записывает все таблицы в одну книгу, по листу на таблицу
+func XLSX(tables []Table) ([]byte, error) {
+	file := excelize.NewFile()
+	defer file.Close()
+
+	dateStyle, err := file.NewStyle(&excelize.Style{NumFmt: 14}) // 14 - встроенный формат даты
+	if err != nil {
+		return nil, err
+	}
+	moneyStyle, err := file.NewStyle(&excelize.Style{NumFmt: 4}) // 4 - "#,##0.00"
+	if err != nil {
+		return nil, err
+	}
+	headerStyle, err := file.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, table := range tables {
+		if i == 0 {
+			if err := file.SetSheetName("Sheet1", table.Name); err != nil {
+				return nil, err
+			}
+		} else if _, err := file.NewSheet(table.Name); err != nil {
+			return nil, err
+		}
+
+		if err := file.SetSheetRow(table.Name, "A1", &table.Header); err != nil {
+			return nil, err
+		}
+		lastHeader, _ := excelize.CoordinatesToCellName(len(table.Header), 1)
+		if err := file.SetCellStyle(table.Name, "A1", lastHeader, headerStyle); err != nil {
+			return nil, err
+		}
+
+		for r, row := range table.Rows {
+			for c, value := range row {
+				cell, _ := excelize.CoordinatesToCellName(c+1, r+2)
+				if err := file.SetCellValue(table.Name, cell, value); err != nil {
+					return nil, err
+				}
+				switch value.(type) {
+				case time.Time:
+					err = file.SetCellStyle(table.Name, cell, cell, dateStyle)
+				case float64:
+					err = file.SetCellStyle(table.Name, cell, cell, moneyStyle)
+				}
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	buf, err := file.WriteToBuffer()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case float64:
+		return fmt.Sprintf("%.2f", v)
+	case time.Time:
+		return v.Format("2006-01-02")
+	default:
+		return fmt.Sprint(v)
+	}
+}
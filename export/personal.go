@@ -0,0 +1,116 @@
+package export
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"DebtBot/db"
+)
+
+// PersonalData - все, что бот хранит о пользователе (/mydata).
+// Записи сохраняются с именами колонок базы, чтобы выгрузка совпадала с тем, что лежит в таблицах.
+type PersonalData struct {
+	ExportedAt    time.Time        `json:"exported_at"`
+	Profile       map[string]any   `json:"profile"` // Включает настройки: язык, часовой пояс, сводки
+	Credits       []map[string]any `json:"credits"`
+	Trash         []map[string]any `json:"deleted_credits"` // Кредиты в корзине
+	CreditHistory []map[string]any `json:"credit_history"`  // Журнал изменений кредитов
+	Payments      []map[string]any `json:"payments"`
+	Snoozes       []map[string]any `json:"snoozes"`
+	Debts         []map[string]any `json:"debts"`
+	DebtHistory   []map[string]any `json:"debt_history"`
+	Groups        []map[string]any `json:"group_memberships"`
+	Notifications []map[string]any `json:"notifications"` // Журнал напоминаний и других уведомлений
+}
+
+// LoadPersonal собирает из базы все данные пользователя
+func LoadPersonal(database *db.DB, userID int64) (PersonalData, error) {
+	user, err := database.GetUser(userID)
+	if err != nil {
+		return PersonalData{}, err
+	}
+	credits, err := database.GetCreditsByUser(userID)
+	if err != nil {
+		return PersonalData{}, err
+	}
+	trash, err := database.GetDeletedCredits(userID)
+	if err != nil {
+		return PersonalData{}, err
+	}
+	creditHistory, err := database.GetAuditLogByUser(userID)
+	if err != nil {
+		return PersonalData{}, err
+	}
+	payments, err := database.GetPaymentsByUser(userID)
+	if err != nil {
+		return PersonalData{}, err
+	}
+	snoozes, err := database.GetSnoozesByUser(userID)
+	if err != nil {
+		return PersonalData{}, err
+	}
+	debts, err := database.GetAllDebtsByUser(userID)
+	if err != nil {
+		return PersonalData{}, err
+	}
+	groups, err := database.GetGroupMembershipsByUser(userID)
+	if err != nil {
+		return PersonalData{}, err
+	}
+	notifications, err := database.GetOutboxByChat(userID)
+	if err != nil {
+		return PersonalData{}, err
+	}
+
+	data := PersonalData{
+		ExportedAt:    time.Now(),
+		Profile:       record(user),
+		Credits:       records(credits),
+		Trash:         records(trash),
+		CreditHistory: records(creditHistory),
+		Payments:      records(payments),
+		Snoozes:       records(snoozes),
+		Debts:         records(debts),
+		DebtHistory:   []map[string]any{},
+		Groups:        records(groups),
+		Notifications: records(notifications),
+	}
+	for _, debt := range debts {
+		history, err := database.GetDebtHistory(debt.ID)
+		if err != nil {
+			return PersonalData{}, err
+		}
+		data.DebtHistory = append(data.DebtHistory, records(history)...)
+	}
+	return data, nil
+}
+
+// JSON сериализует данные пользователя в читаемый JSON
+func JSON(data PersonalData) ([]byte, error) {
+	return json.MarshalIndent(data, "", "  ")
+}
+
+// records преобразует срез моделей в записи с ключами из тегов db
+func records[T any](items []T) []map[string]any {
+	result := make([]map[string]any, 0, len(items))
+	for _, item := range items {
+		result = append(result, record(item))
+	}
+	return result
+}
+
+// record преобразует модель (структуру или указатель на нее) в запись с ключами из тегов db
+func record(item any) map[string]any {
+	value := reflect.Indirect(reflect.ValueOf(item))
+	result := make(map[string]any, value.NumField())
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		name := field.Tag.Get("db")
+		if name == "" || name == "-" || !field.IsExported() {
+			continue
+		}
+		result[name] = value.Field(i).Interface()
+	}
+	return result
+}
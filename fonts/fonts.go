@@ -0,0 +1,14 @@
+// Package fonts встраивает в бинарник шрифты с поддержкой кириллицы
+// для документов и изображений, которые генерирует бот.
+//
+// DejaVu Sans распространяется под свободной лицензией DejaVu Fonts
+// (производная от Bitstream Vera), https://dejavu-fonts.github.io/License.html
+package fonts
+
+import _ "embed"
+
+//go:embed DejaVuSans.ttf
+var Regular []byte
+
+//go:embed DejaVuSans-Bold.ttf
+var Bold []byte
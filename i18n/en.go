@@ -0,0 +1,403 @@
+package i18n
+
+var enMessages = map[string]string{
+	"language.name":   "English",
+	"language.choose": "Choose a language:",
+	"language.auto":   "Same as Telegram",
+	"language.saved":  "Interface language: %s",
+
+	"help.text": `
+Hi! I help you keep track of your loans.
+Add a loan in one line: /addcredit Sberbank 150000 15.03.2025 12.9% 36m
+You can also just write: "paid tinkoff 5000", "how much do I owe?"
+
+Add me to a group chat to split shared expenses with friends (/split).
+Debts between friends: /newdebt - record a debt, /debts - list of debts.
+/export - download loans and payments as Excel (XLSX) and CSV.
+/import - add several loans at once from a CSV or XLSX file.
+/calendar - payment calendar for Google/Apple Calendar.
+/reminders - whether payment reminders were delivered.
+/statement - monthly PDF statement (sent automatically on the 1st).
+/charts - charts of the remaining debt and payments.
+/summary - overview and debt-to-income ratio, /income - set your income.
+/settings - weekly and monthly digests, /timezone - your time zone.
+/language - interface language.
+/trash - trash: restore a deleted loan.
+/mydata - all your data in one file, /deleteme - delete your account.
+
+Choose an action:`,
+
+	ButtonAddCredit:    "➕ Add loan",
+	ButtonMyCredits:    "💶 My loans",
+	ButtonDeleteCredit: "➖ Delete loan",
+	ButtonHelp:         "🆘 Help",
+	"button.cancel":    "Cancel",
+
+	"error.generic":    "Something went wrong, please try again.",
+	"error.retry":      "Error, please try again",
+	"error.amount":     "Invalid amount. For example: 10000.50, 10,000, 15k, 1.5m",
+	"error.date":       "Could not read the date. For example: 2024-12-31, 31.12.2024, December 31, tomorrow, in 2 weeks, every 15th",
+	"error.bad_button": "Invalid button",
+
+	"credit.ask_bank":          "Enter the bank name:",
+	"credit.ask_bank_text":     "Enter the bank name as text:",
+	"credit.ask_amount":        "Enter the loan amount:",
+	"credit.ask_due_date":      "Enter the payment date (for example 31.12.2024, December 31 or every 15th):",
+	"credit.save_error":        "Failed to save the loan. Please try again.",
+	"credit.added":             "Loan added!",
+	"credit.delete_title":      "Choose the number of the loan to delete:\n\n",
+	"credit.delete_item":       "%d. 🏦 *Bank:* %s, 💰 *Loan amount:* %.2f ₽, 📅 *Payment date:* %s\n",
+	"credit.delete_empty":      "You have no loans to delete. Use /addcredit to add one.",
+	"credit.delete_ask_number": "Please enter the number of the loan to delete.",
+	"credit.delete_bad_number": "Wrong loan number. Please choose a number from the list.",
+	"credit.delete_error":      "Failed to delete the loan. Please try again.",
+	"credit.use_buttons":       "Press \"Save\" or \"Cancel\" under the loan message.",
+	"credit.button_save":       "✅ Save",
+	"credit.confirm":           "Please check the loan:\n\n🏦 *Bank:* %s\n💰 *Loan amount:* %s\n📅 *Payment date:* %s\n",
+	"credit.confirm_rate":      "📈 *Rate:* %.2f%% per year\n",
+	"credit.confirm_expired":   "The loan has already been saved or cancelled",
+	"credit.cancelled":         "Adding the loan was cancelled.",
+	"credit.quick_unknown":     "Could not understand: %s\n\nExample: /addcredit Sberbank 150000 15.03.2025 12.9%% 36m\nPut bank names with spaces in quotes: \"Tinkoff Bank\".",
+
+	"credits.title":      "*Your loans:*\n\n",
+	"credits.item":       "🏦 *Bank:* %s\n💰 *Loan amount:* %.2f ₽\n📅 *Payment date:* %s\n",
+	"credits.item_end":   "---\n",
+	"credits.empty":      "You have no loans yet. Use /addcredit to add one.",
+	"credits.load_error": "Failed to load your loans.",
+
+	"reminder.text":             "🔔 *Loan payment reminder!*\n\nBank: %s\nAmount: %.2f\nPayment date: %s\n\nDon't forget to pay %s!",
+	"reminder.when_soon":        "soon",
+	"reminder.when_today":       "today",
+	"reminder.when_tomorrow":    "tomorrow",
+	"reminder.when_overdue":     "- it is already overdue",
+	"reminder.button_paid":      "✅ Paid",
+	"reminder.button_later":     "⏰ In 2 hours",
+	"reminder.button_tomorrow":  "📅 Tomorrow",
+	"reminder.button_mute":      "🔕 Stop reminding",
+	"reminder.button_unmute":    "🔔 Turn back on",
+	"reminder.paid":             "✅ Paid %s: %s",
+	"reminder.already_paid":     "Payment already recorded",
+	"reminder.later":            "⏰ I'll remind you at %s",
+	"reminder.tomorrow":         "⏰ I'll remind you tomorrow at %s",
+	"reminder.muted":            "🔕 Reminders for this loan are off",
+	"reminder.unmuted":          "🔔 Reminders for this loan are back on",
+	"reminder.credit_not_found": "Loan not found",
+	"reminder.credit_deleted":   "The loan was deleted.",
+
+	"debt.ask_direction":            "Who owes whom?",
+	"debt.button_lent":              "I'm owed",
+	"debt.button_borrowed":          "I owe",
+	"debt.use_buttons":              "Choose an option with the buttons under the message above.",
+	"debt.ask_amount":               "Enter the debt amount:",
+	"debt.ask_description":          "What is it for? (for example \"cinema tickets\")",
+	"debt.ask_due_date":             "Enter the repayment date (for example 31.12.2024, December 31 or in 2 weeks):",
+	"debt.save_error":               "Failed to save the debt. Please try again.",
+	"debt.created":                  "The debt is recorded and waits for confirmation.\n\n%s\n\nForward this link to the other party: once confirmed, the debt shows up in /debts for both of you.",
+	"debt.dialog_finished":          "This dialog is over, start again: /newdebt",
+	"debt.invite":                   "You are asked to confirm a debt:\n\n%s",
+	"debt.invite_not_found":         "The invitation was not found or has expired.",
+	"debt.invite_own":               "This is your own invitation - forward the link to the other party.",
+	"debt.invite_processed":         "This invitation has already been handled.",
+	"debt.button_accept":            "✅ Confirm",
+	"debt.button_reject":            "❌ Decline",
+	"debt.button_settle":            "💸 Repaid",
+	"debt.button_settle_ok":         "✅ Yes, repaid",
+	"debt.button_settle_no":         "❌ No",
+	"debt.accepted":                 "✅ Debt confirmed:\n\n%s",
+	"debt.accepted_other":           "✅ The other party confirmed the debt:\n\n%s",
+	"debt.rejected":                 "❌ You declined the debt.",
+	"debt.rejected_other":           "❌ The other party declined the debt:\n\n%s",
+	"debt.not_found":                "Debt not found",
+	"debt.settle_requested_already": "Repayment already requested",
+	"debt.nothing_to_confirm":       "Nothing to confirm",
+	"debt.settle_waiting":           "⏳ Waiting for the other party to confirm the repayment:\n\n%s",
+	"debt.settle_confirm":           "The other party marked the debt as repaid. Do you confirm?\n\n%s",
+	"debt.settled":                  "🎉 Debt repaid:\n\n%s",
+	"debt.settled_other":            "🎉 Repayment confirmed:\n\n%s",
+	"debt.settle_declined":          "You did not confirm the repayment, the debt stays open:\n\n%s",
+	"debt.settle_declined_other":    "❌ The other party did not confirm the repayment:\n\n%s",
+	"debt.reminder":                 "🔔 *Reminder: a debt is due tomorrow!*\n\n%s",
+	"debt.you_owe":                  "You owe",
+	"debt.owed_to_you":              "You are owed",
+	"debt.format_amount":            "🤝 *%s:* %.2f ₽\n",
+	"debt.format_description":       "📝 *For:* %s\n",
+	"debt.format_due_date":          "📅 *Due:* %s\n",
+	"debt.format_status":            "📌 *Status:* %s",
+	"debt.status.pending":           "waiting for confirmation",
+	"debt.status.active":            "confirmed",
+	"debt.status.rejected":          "declined",
+	"debt.status.settle_requested":  "waiting for repayment confirmation",
+	"debt.status.settled":           "repaid",
+
+	"debts.title":      "*Your debts:*",
+	"debts.empty":      "You have no open debts. Use /newdebt to record one.",
+	"debts.load_error": "Failed to load your debts.",
+
+	"group.help": `*Shared group expenses*
+
+/split 3000 @a @b dinner - you paid 3000 ₽, split equally between you, @a and @b (without mentions - between all members)
+/paid @a 1000 - you paid @a back 1000 ₽
+/balance - who owes the group how much
+/settle - the shortest list of transfers to settle up
+
+I only know members who have written in this chat since I was added.`,
+	"group.private_only":     "Personal loans are only available in a private chat with me.",
+	"group.split_usage":      "Specify the amount, for example: /split 3000 @a @b dinner",
+	"group.members_error":    "Failed to load the member list.",
+	"group.split_nobody":     "Nobody to split with: mention members or wait until they write in the chat.",
+	"group.split_error":      "Failed to save the expense. Please try again.",
+	"group.paid_usage":       "Specify whom you paid back and how much, for example: /paid @a 1000",
+	"group.repayment":        "Repayment",
+	"group.paid_error":       "Failed to save the repayment. Please try again.",
+	"group.paid_done":        "✅ Noted: %s paid %s back %.2f ₽.",
+	"group.balance_error":    "Failed to calculate balances.",
+	"group.settled":          "Everyone is settled up 🤝",
+	"group.balance_title":    "*Group balances:*\n\n",
+	"group.balance_positive": "🟢 %s: the group owes %.2f ₽\n",
+	"group.balance_negative": "🔴 %s: owes the group %.2f ₽\n",
+	"group.settle_error":     "Failed to calculate transfers.",
+	"group.reminder":         "🔔 *Settle-up reminder*\n\n%s",
+	"group.settle_title":     "*To settle up:*\n\n",
+	"group.settle_item":      "%s → %s: %.2f ₽\n",
+	"group.settle_hint":      "\nAfter a transfer, record it with /paid",
+	"group.unknown_member":   "I don't know @%s yet - ask them to write something in this chat.",
+	"group.member_error":     "Failed to find the member. Please try again.",
+	"group.someone":          "someone",
+
+	"import.help": `*Import loans from a file*
+
+Send me a CSV or XLSX file. The first row holds the column headers:
+- *bank_name* - bank name
+- *loan_amount* - loan amount, for example 10000.50
+- *due_date* - payment date, for example 2024-12-31 or 31.12.2024
+
+The credits.csv file from /export works too. The template is below.`,
+	"import.bad_format":     "I can only import CSV and XLSX files. Template: /import",
+	"import.too_big":        "The file is too large, the limit is 1 MB.",
+	"import.download_error": "Failed to download the file. Please try again.",
+	"import.parse_error":    "Failed to read the file: %s\n\nTemplate: /import",
+	"import.preview":        "*Import preview*\n\nRows in the file: %d\n✅ Valid: %d\n❌ With errors: %d\n",
+	"import.valid_title":    "\n*Will be added:*\n",
+	"import.more":           "... and %d more\n",
+	"import.invalid_title":  "\n*Errors:*\n",
+	"import.invalid_row":    "Row %d: %s\n",
+	"import.valid_row":      "🏦 %s - %.2f ₽ - %s\n",
+	"import.nothing":        "\nNothing to import - fix the file and send it again.",
+	"import.button_confirm": "📥 Import (%d)",
+	"import.finished":       "The import is already finished or cancelled",
+	"import.cancelled":      "Import cancelled.",
+	"import.error_short":    "Import failed",
+	"import.error":          "Import failed, no loans were added. Please try again.",
+
+	// Import file errors: shown after "Failed to read the file:" and in rows with errors
+	"import.read_csv":            "could not read the CSV",
+	"import.open_xlsx":           "could not open the XLSX",
+	"import.no_sheets":           "the file has no sheets",
+	"import.read_sheet":          "could not read sheet %s",
+	"import.empty_file":          "the file is empty",
+	"import.too_many_rows":       "too many rows: %d, the limit is %d",
+	"import.no_credits":          "the file has no loan rows",
+	"import.missing_columns":     "columns not found in the header: %s",
+	"import.row_bank":            "the bank name is missing",
+	"import.row_amount":          "invalid amount",
+	"import.row_amount_positive": "the amount must be greater than zero",
+	"import.row_date":            "invalid date, for example: 2024-12-31, 31.12.2024, December 31",
+
+	"export.error": "Failed to prepare the export.",
+	"export.empty": "Nothing to export yet. Use /addcredit to add a loan.",
+
+	"calendar.subscribe": "To keep the calendar up to date, subscribe to it with the link below (in Google Calendar: \"Add calendar\" → \"From URL\").\n\nDon't share this link. If it got into the wrong hands, issue a new one: /calendar reset",
+
+	"statement.usage":   "Specify the month as YYYY-MM, for example: /statement 2024-12",
+	"statement.error":   "Failed to build the statement.",
+	"statement.monthly": "📄 Your loan statement for %s %d",
+
+	"statement.pdf_title":     "Statement for %s",
+	"statement.pdf_heading":   "Loan statement for %s",
+	"statement.pdf_generated": "Generated %s",
+	"statement.pdf_credits":   "Loans",
+	"statement.pdf_payments":  "Payments this month",
+	"statement.pdf_bank":      "Bank",
+	"statement.pdf_amount":    "Amount",
+	"statement.pdf_paid":      "Paid",
+	"statement.pdf_remaining": "Principal left",
+	"statement.pdf_due_date":  "Payment date",
+	"statement.pdf_date":      "Date",
+	"statement.pdf_total":     "Total",
+	"statement.pdf_no_data":   "No data",
+
+	"charts.balance_title": "Remaining debt by month, ₽",
+	"charts.monthly_title": "Payments by month, ₽",
+	"charts.banks_title":   "Debt share by bank",
+
+	"ical.calendar_name": "Loan payments",
+	"ical.summary":       "Loan payment: %s - %.2f ₽",
+	"ical.description":   "Bank: %s\nAmount: %.2f ₽\nPayment #%d",
+
+	"income.ask":        "Enter your average monthly income after taxes (0 - remove):",
+	"income.bad_amount": "Invalid amount. For example: 85000, 85,000 or 85k",
+	"income.save_error": "Failed to save your income. Please try again.",
+	"income.deleted":    "Income removed.",
+	"income.saved":      "Monthly income of %.2f ₽ saved. See your debt load in /summary",
+
+	"summary.error":            "Failed to build the overview.",
+	"summary.title":            "*Loan overview*\n\n",
+	"summary.outstanding":      "💰 Remaining debt: %s\n",
+	"summary.this_month":       "📅 Payments this month: %s\n",
+	"summary.next_30_days":     "⏳ Payments in the next 30 days: %s\n",
+	"summary.monthly_payments": "📊 Average monthly payment: %s\n",
+	"summary.by_bank":          "\n*By bank:*\n",
+	"summary.bank_item":        "%s %s: %s\n",
+	"summary.no_income":        "\nSet your income with /income to see your debt-to-income ratio.",
+	"summary.load":             "\n*Debt-to-income:* %.0f%% (income %s)\n",
+	"summary.level_critical":   "🔴 Very high load: more than 80% of your income goes to loans. Banks will most likely refuse a new loan - consider refinancing.",
+	"summary.level_warning":    "🟠 High load: more than 50% of your income goes to loans. A bank may not approve a new loan.",
+	"summary.level_normal":     "🟢 Your debt load is within normal limits.",
+
+	"settings.text":             "*Settings*\n\n🗓 Weekly digest (Mon, %d:00): %s\n📆 Monthly digest (1st, %d:00): %s\n🌍 Time zone: %s\n🗣 Language: %s\n\nChange the time zone: /timezone Europe/London, language: /language",
+	"settings.timezone_default": "not set (server time)",
+	"settings.on":               "on",
+	"settings.off":              "off",
+	"settings.button_weekly":    "Week",
+	"settings.button_monthly":   "Month",
+	"settings.saved":            "Saved",
+	"settings.error":            "Failed to load your settings.",
+
+	"timezone.usage":      "Specify a time zone, for example: /timezone Europe/London or /timezone +3",
+	"timezone.unknown":    "Unknown time zone. Examples: Europe/London, America/New_York, +3",
+	"timezone.save_error": "Failed to save the time zone.",
+	"timezone.saved":      "Time zone saved. It is %s for you now.",
+
+	"digest.weekly":              "🗓 *Payments for the week %s - %s*\n\n%s\n*Total:* %s",
+	"digest.monthly_title":       "📆 *Overview for %s %d*\n\n",
+	"digest.monthly_paid":        "Paid in %s: %s\n",
+	"digest.monthly_outstanding": "Remaining debt: %s\n",
+	"digest.monthly_load":        "Debt-to-income: %.0f%%\n",
+
+	"month.1":  "January",
+	"month.2":  "February",
+	"month.3":  "March",
+	"month.4":  "April",
+	"month.5":  "May",
+	"month.6":  "June",
+	"month.7":  "July",
+	"month.8":  "August",
+	"month.9":  "September",
+	"month.10": "October",
+	"month.11": "November",
+	"month.12": "December",
+
+	"month_short.1":  "Jan",
+	"month_short.2":  "Feb",
+	"month_short.3":  "Mar",
+	"month_short.4":  "Apr",
+	"month_short.5":  "May",
+	"month_short.6":  "Jun",
+	"month_short.7":  "Jul",
+	"month_short.8":  "Aug",
+	"month_short.9":  "Sep",
+	"month_short.10": "Oct",
+	"month_short.11": "Nov",
+	"month_short.12": "Dec",
+
+	"weekday.0": "Sun",
+	"weekday.1": "Mon",
+	"weekday.2": "Tue",
+	"weekday.3": "Wed",
+	"weekday.4": "Thu",
+	"weekday.5": "Fri",
+	"weekday.6": "Sat",
+
+	"intent.unknown":           "I did not understand that 🤔 Try for example: \"paid tinkoff 5000\", \"how much do I owe?\", \"my loans\" - or pick an action in the menu. All commands: /help",
+	"intent.payment_ask_bank":  "Which loan is the payment for? Name the bank, for example: \"paid tinkoff 5000\".",
+	"intent.payment_not_found": "No loan found for bank \"%s\". Your loans: /mycredits",
+	"intent.payment_confirm":   "Record a payment of %s for the *%s* loan?",
+	"intent.button_confirm":    "✅ Record",
+	"intent.payment_done":      "✅ Payment of %s recorded.",
+	"intent.cancelled":         "The payment was not recorded.",
+	"intent.expired":           "The payment has already been recorded or cancelled",
+
+	"bank.suggest":     "This bank is not in the catalog. Did you mean:",
+	"bank.button_keep": "Keep \"%s\"",
+	"bank.chosen":      "🏦 Bank: %s",
+	"bank.expired":     "The bank has already been chosen",
+
+	"reminders.empty":          "There have been no payment reminders yet.",
+	"reminders.title":          "*Recent payment reminders:*",
+	"reminders.line":           "%s, %s: %s",
+	"reminders.deleted_credit": "deleted loan",
+	"reminders.status_sent":    "✅ delivered %s",
+	"reminders.status_pending": "⏳ queued",
+	"reminders.status_failed":  "❌ not delivered",
+	"reminders.status_blocked": "🚫 not delivered: the bot was blocked",
+
+	"admin.help":                "*Admin:* /stats - statistics, /broadcast <text> - message everyone, /user <id> - user data, /ban <id> and /unban <id> - revoke and restore access, /invite - invite code, /jobs - scheduled jobs, /backup - database backup.",
+	"admin.banned_notice":       "🚫 Your access to the bot is restricted.",
+	"admin.stats":               "*Statistics*\n👥 Users: %d\n✅ Active in 30 days: %d\n🔕 Blocked the bot: %d\n🚫 Banned: %d\n🏦 Loans: %d\n🔔 Reminders sent today: %d\n📤 Waiting in outbox: %d",
+	"admin.broadcast_usage":     "Specify the broadcast text: /broadcast <text>. Markdown is supported.",
+	"admin.button_send":         "📤 Send",
+	"admin.broadcast_expired":   "The broadcast has expired, send /broadcast again",
+	"admin.broadcast_cancelled": "Broadcast cancelled.",
+	"admin.user_usage":          "Specify a Telegram ID: /user <id>",
+	"admin.user_not_found":      "User %d not found.",
+	"admin.status_active":       "active",
+	"admin.status_blocked":      "blocked the bot",
+	"admin.status_banned":       "banned",
+	"admin.never":               "never",
+	"admin.server_timezone":     "server default",
+	"admin.user_info":           "👤 *User %d*\nRegistered: %s\nLast active: %s\nLanguage: %s, time zone: %s\nStatus: %s\nOpen debts: %d, payments: %d\n",
+	"admin.user_credit":         "• #%d %s, %s ₽, due %s\n",
+	"admin.user_credits":        "Loans: %d\n",
+	"admin.ban_usage":           "Specify a Telegram ID: /ban <id>",
+	"admin.unban_usage":         "Specify a Telegram ID: /unban <id>",
+	"admin.ban_admin":           "An admin can't be banned.",
+	"admin.ban_done":            "🚫 User %d is banned.",
+	"admin.unban_done":          "✅ Access restored for user %d.",
+	"admin.backup_error":        "Failed to create a backup, see the log for details.",
+	"admin.backup_too_large":    "The backup was created but is too large for Telegram: `%s`",
+	"admin.jobs_empty":          "There are no scheduled jobs.",
+	"admin.jobs_title":          "*Scheduled jobs:*",
+	"admin.job":                 "• %s: last run %s, next %s",
+	"admin.job_running":         " (running)",
+
+	"access.private":            "🔒 This is a private bot, only its members can use it.\nIf you're on the team, send your Telegram ID to the admin: `%d`",
+	"access.invite_required":    "🔒 This is a private bot, you need an invitation to use it.\nOpen the invite link or send the code you were given.",
+	"access.invite_invalid":     "The invite code didn't work: it is wrong, expired or already used. Ask the person who invited you for a new one.",
+	"access.welcome":            "🎉 Invitation accepted, welcome!",
+	"access.denied":             "🔒 No access",
+	"access.group_denied":       "🔒 This is a private bot, only its members can use it. To get access, send me a private message.",
+	"admin.invite_usage":        "Usage: /invite [number of uses] [days valid]. For example: /invite 5 30",
+	"admin.invite_no_expiry":    "never expires",
+	"admin.invite_expires":      "valid until %s",
+	"admin.invite_mode_warning": "⚠️ The access mode is currently %s: the code only works with access\\_mode: invite.",
+
+	"account.delete_confirm":   "⚠️ *Delete your account?*\nYour loans, payments, debts without a second party, settings and notification history will be permanently deleted and cannot be restored.\nDebts with other users and shared group expenses stay with the other participants, but without your Telegram ID.\nYou can save a copy with /mydata.",
+	"account.button_delete":    "Delete forever",
+	"account.delete_cancelled": "Deletion cancelled.",
+	"account.deleted":          "Your account and all data have been deleted. Send /start to begin again.",
+
+	"trash.button_undo":    "↩️ Undo",
+	"trash.undo_expired":   "Undo is no longer available. You can restore the loan in /trash.",
+	"trash.empty":          "The trash is empty.",
+	"trash.item":           "%d. *%s* - %s, deleted %s\n",
+	"trash.button_restore": "↩️ %d. %s",
+	"trash.not_found":      "The loan has already been restored or deleted permanently.",
+	"trash.restored":       "✅ Loan *%s* for %s restored.",
+}
+
+// Формы: 1 loan, 5 loans (Few не используется)
+var enPlurals = map[string]Plural{
+	"credit.confirm_term":       {"🗓 *Term:* %d month\n", "", "🗓 *Term:* %d months\n"},
+	"summary.credits":           {"🏦 %d loan\n", "", "🏦 %d loans\n"},
+	"group.split_done":          {"✅ Expense of %.2[2]f ₽ recorded, %.2[3]f ₽ for each of %[1]d member.", "", "✅ Expense of %.2[2]f ₽ recorded, %.2[3]f ₽ for each of %[1]d members."},
+	"import.more_invalid":       {"... and %d more row with errors\n", "", "... and %d more rows with errors\n"},
+	"import.done":               {"✅ Imported %d loan. See: /mycredits", "", "✅ Imported %d loans. See: /mycredits"},
+	"digest.monthly_payments":   {"%d payment this month, %s\n", "", "%d payments this month, %s\n"},
+	"reminders.status_retrying": {"⏳ retrying, %d attempt", "", "⏳ retrying, %d attempts"},
+	"admin.broadcast_confirm":   {"Send this message to %d user?", "", "Send this message to %d users?"},
+	"admin.broadcast_queued":    {"📤 Broadcast queued for %d user.", "", "📤 Broadcast queued for %d users."},
+	"admin.invite_created":      {"🎟 Invite code `%[2]s` for %[1]d use, %[3]s. Link:", "", "🎟 Invite code `%[2]s` for %[1]d uses, %[3]s. Link:"},
+	"credit.deleted":            {"🗑 Loan moved to the trash. It is kept there for %d day, you can restore it in /trash.", "", "🗑 Loan moved to the trash. It is kept there for %d days, you can restore it in /trash."},
+	"statement.pdf_upcoming":    {"Upcoming payments (%d day)", "", "Upcoming payments (%d days)"},
+	"trash.title":               {"🗑 *Trash*\nLoans are deleted permanently %d day after deletion.\n\n", "", "🗑 *Trash*\nLoans are deleted permanently %d days after deletion.\n\n"},
+}
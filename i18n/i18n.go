@@ -0,0 +1,179 @@
+// Package i18n хранит каталоги сообщений бота (ru, en) и правила множественного числа
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"DebtBot/logging"
+)
+
+var logger = logging.For("i18n")
+
+// Lang - код поддерживаемого языка
+type Lang string
+
+const (
+	RU Lang = "ru"
+	EN Lang = "en"
+)
+
+// Default - язык, если Telegram не сообщил язык пользователя
+const Default = RU
+
+// Supported - языки в порядке показа в /language
+var Supported = []Lang{RU, EN}
+
+// Кнопки reply-клавиатуры: бот узнает нажатие по ключу, на каком бы языке ни была подпись
+const (
+	ButtonAddCredit    = "button.add_credit"
+	ButtonMyCredits    = "button.my_credits"
+	ButtonDeleteCredit = "button.delete_credit"
+	ButtonHelp         = "button.help"
+)
+
+var buttons = []string{ButtonAddCredit, ButtonMyCredits, ButtonDeleteCredit, ButtonHelp}
+
+// Form - форма множественного числа
+type Form int
+
+const (
+	One  Form = iota // 1 кредит, 1 credit
+	Few              // 2 кредита
+	Many             // 5 кредитов, 5 credits
+)
+
+// Plural - формы сообщения для разных чисел. В английском Few не используется.
+type Plural [3]string
+
+type catalog struct {
+	messages map[string]string
+	plurals  map[string]Plural
+	form     func(n int) Form
+}
+
+var catalogs = map[Lang]*catalog{
+	RU: {messages: ruMessages, plurals: ruPlurals, form: russianForm},
+	EN: {messages: enMessages, plurals: enPlurals, form: englishForm},
+}
+
+func russianForm(n int) Form {
+	if n < 0 {
+		n = -n
+	}
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return One
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return Few
+	}
+	return Many
+}
+
+func englishForm(n int) Form {
+	if n == 1 || n == -1 {
+		return One
+	}
+	return Many
+}
+
+// Match выбирает язык по коду из Telegram ("ru", "en-US") или из настройки пользователя.
+// Пустой код - язык по умолчанию, остальные неподдерживаемые языки получают английский.
+func Match(code string) Lang {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if code == "" {
+		return Default
+	}
+	base, _, _ := strings.Cut(code, "-")
+	switch base {
+	case "ru", "uk", "be", "kk":
+		return RU
+	}
+	return EN
+}
+
+// Parse проверяет код языка, выбранный пользователем явно
+func Parse(code string) (Lang, bool) {
+	lang := Lang(strings.ToLower(strings.TrimSpace(code)))
+	_, ok := catalogs[lang]
+	return lang, ok
+}
+
+// Localizer переводит сообщения на язык одного пользователя
+type Localizer struct {
+	lang    Lang
+	catalog *catalog
+}
+
+// New создает Localizer для кода языка (см. Match)
+func New(code string) *Localizer {
+	lang := Match(code)
+	return &Localizer{lang: lang, catalog: catalogs[lang]}
+}
+
+// Lang - язык, на который переводит Localizer
+func (l *Localizer) Lang() Lang {
+	return l.lang
+}
+
+// T возвращает сообщение key, подставляя args как в fmt.Sprintf.
+// Если в каталоге языка нет сообщения, берется язык по умолчанию.
+func (l *Localizer) T(key string, args ...interface{}) string {
+	message, ok := l.catalog.messages[key]
+	if !ok {
+		message, ok = catalogs[Default].messages[key]
+	}
+	if !ok {
+		logger.Warn("Нет сообщения в каталоге", "key", key)
+		return key
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// N возвращает форму сообщения key для числа n. Первым аргументом форматирования
+// всегда идет n, за ним - args.
+func (l *Localizer) N(key string, n int, args ...interface{}) string {
+	c := l.catalog
+	forms, ok := c.plurals[key]
+	if !ok {
+		c = catalogs[Default]
+		forms, ok = c.plurals[key]
+	}
+	if !ok {
+		logger.Warn("Нет сообщения в каталоге", "key", key)
+		return key
+	}
+	return fmt.Sprintf(forms[c.form(n)], append([]interface{}{n}, args...)...)
+}
+
+// Month - название месяца: "октябрь", "October"
+func (l *Localizer) Month(month time.Month) string {
+	return l.T(fmt.Sprintf("month.%d", int(month)))
+}
+
+// MonthShort - сокращенное название месяца: "окт", "Oct"
+func (l *Localizer) MonthShort(month time.Month) string {
+	return l.T(fmt.Sprintf("month_short.%d", int(month)))
+}
+
+// Weekday - короткое название дня недели: "Пн", "Mon"
+func (l *Localizer) Weekday(day time.Weekday) string {
+	return l.T(fmt.Sprintf("weekday.%d", int(day)))
+}
+
+// Button ищет кнопку reply-клавиатуры по ее подписи на любом из языков
+func Button(text string) (string, bool) {
+	text = strings.TrimSpace(text)
+	for _, key := range buttons {
+		for _, lang := range Supported {
+			if catalogs[lang].messages[key] == text {
+				return key, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,403 @@
+package i18n
+
+var ruMessages = map[string]string{
+	"language.name":   "Русский",
+	"language.choose": "Выберите язык:",
+	"language.auto":   "Как в Telegram",
+	"language.saved":  "Язык интерфейса: %s",
+
+	"help.text": `
+Привет! Я бот для учета твоих кредитов.
+Кредит можно добавить одной строкой: /addcredit Сбербанк 150000 15.03.2025 12.9% 36м
+Можно писать и своими словами: «заплатил по тинькофф 5000», «сколько я должен?»
+
+Добавьте меня в групповой чат, чтобы делить общие траты с друзьями (/split).
+Долги между друзьями: /newdebt - записать долг, /debts - список долгов.
+/export - выгрузить кредиты и платежи в Excel (XLSX) и CSV.
+/import - загрузить сразу несколько кредитов из CSV или XLSX файла.
+/calendar - календарь платежей для Google/Apple Calendar.
+/reminders - доставлены ли напоминания о платежах.
+/statement - PDF-выписка за месяц (приходит сама 1-го числа).
+/charts - графики остатка долга и платежей.
+/summary - сводка и долговая нагрузка (ПДН), /income - указать доход.
+/settings - сводки на неделю и месяц, /timezone - часовой пояс.
+/language - язык интерфейса.
+/trash - корзина: восстановить удаленный кредит.
+/mydata - все ваши данные одним файлом, /deleteme - удалить аккаунт.
+
+Выберите действие:`,
+
+	ButtonAddCredit:    "➕ Добавить кредит",
+	ButtonMyCredits:    "💶 Мои кредиты",
+	ButtonDeleteCredit: "➖ Удалить кредит",
+	ButtonHelp:         "🆘 Помощь",
+	"button.cancel":    "Отмена",
+
+	"error.generic":    "Произошла ошибка, попробуйте еще раз.",
+	"error.retry":      "Ошибка, попробуйте еще раз",
+	"error.amount":     "Некорректная сумма. Например: 10000.50, 10 000 ₽, 15к, 1,5 млн",
+	"error.date":       "Не удалось разобрать дату. Например: 31.12.2024, 31 декабря, завтра, через 2 недели, каждое 15 число",
+	"error.bad_button": "Некорректная кнопка",
+
+	"credit.ask_bank":          "Введите название банка:",
+	"credit.ask_bank_text":     "Введите название банка текстом:",
+	"credit.ask_amount":        "Введите сумму кредита:",
+	"credit.ask_due_date":      "Введите дату платежа (например, 31.12.2024, 31 декабря или каждое 15 число):",
+	"credit.save_error":        "Ошибка при сохранении кредита. Попробуйте еще раз.",
+	"credit.added":             "Кредит успешно добавлен!",
+	"credit.delete_title":      "Выберите номер кредита для удаления:\n\n",
+	"credit.delete_item":       "%d. 🏦 *Банк:* %s, 💰 *Сумма кредита:* %.2f ₽, 📅 *Дата платежа:* %s\n",
+	"credit.delete_empty":      "У вас нет кредитов для удаления. Используйте /addcredit чтобы добавить.",
+	"credit.delete_ask_number": "Пожалуйста, введите номер кредита для удаления.",
+	"credit.delete_bad_number": "Неверный номер кредита. Пожалуйста, выберите номер из списка.",
+	"credit.delete_error":      "Ошибка при удалении кредита. Попробуйте еще раз.",
+	"credit.use_buttons":       "Нажмите «Сохранить» или «Отмена» под сообщением с кредитом.",
+	"credit.button_save":       "✅ Сохранить",
+	"credit.confirm":           "Проверьте кредит:\n\n🏦 *Банк:* %s\n💰 *Сумма кредита:* %s\n📅 *Дата платежа:* %s\n",
+	"credit.confirm_rate":      "📈 *Ставка:* %.2f%% годовых\n",
+	"credit.confirm_expired":   "Кредит уже сохранен или отменен",
+	"credit.cancelled":         "Добавление кредита отменено.",
+	"credit.quick_unknown":     "Не удалось разобрать: %s\n\nПример: /addcredit Сбербанк 150000 15.03.2025 12.9%% 36м\nБанк с пробелами пишите в кавычках: \"Тинькофф Банк\".",
+
+	"credits.title":      "*Ваши кредиты:*\n\n",
+	"credits.item":       "🏦 *Банк:* %s\n💰 *Сумма кредита:* %.2f ₽\n📅 *Дата платежа:* %s\n",
+	"credits.item_end":   "---\n",
+	"credits.empty":      "У вас пока нет добавленных кредитов. Используйте /addcredit чтобы добавить.",
+	"credits.load_error": "Ошибка при получении списка кредитов.",
+
+	"reminder.text":             "🔔 *Напоминание о платеже по кредиту!*\n\nБанк: %s\nСумма: %.2f\nДата платежа: %s\n\nНе забудьте оплатить кредит %s!",
+	"reminder.when_soon":        "скоро",
+	"reminder.when_today":       "сегодня",
+	"reminder.when_tomorrow":    "завтра",
+	"reminder.when_overdue":     "- срок уже прошел",
+	"reminder.button_paid":      "✅ Оплачено",
+	"reminder.button_later":     "⏰ Через 2 часа",
+	"reminder.button_tomorrow":  "📅 Завтра",
+	"reminder.button_mute":      "🔕 Не напоминать",
+	"reminder.button_unmute":    "🔔 Включить снова",
+	"reminder.paid":             "✅ Оплачено %s: %s",
+	"reminder.already_paid":     "Платеж уже отмечен",
+	"reminder.later":            "⏰ Напомню в %s",
+	"reminder.tomorrow":         "⏰ Напомню завтра в %s",
+	"reminder.muted":            "🔕 Напоминания по этому кредиту отключены",
+	"reminder.unmuted":          "🔔 Напоминания по этому кредиту снова включены",
+	"reminder.credit_not_found": "Кредит не найден",
+	"reminder.credit_deleted":   "Кредит удален.",
+
+	"debt.ask_direction":            "Кто кому должен?",
+	"debt.button_lent":              "Мне должны",
+	"debt.button_borrowed":          "Я должен",
+	"debt.use_buttons":              "Выберите вариант кнопкой под сообщением выше.",
+	"debt.ask_amount":               "Введите сумму долга:",
+	"debt.ask_description":          "За что долг? (например, \"билеты в кино\")",
+	"debt.ask_due_date":             "Введите дату возврата (например, 31.12.2024, 31 декабря или через 2 недели):",
+	"debt.save_error":               "Ошибка при сохранении долга. Попробуйте еще раз.",
+	"debt.created":                  "Долг записан и ждет подтверждения.\n\n%s\n\nПерешлите эту ссылку второй стороне: после подтверждения долг появится у обоих в /debts.",
+	"debt.dialog_finished":          "Диалог уже завершен, начните заново: /newdebt",
+	"debt.invite":                   "Вас просят подтвердить долг:\n\n%s",
+	"debt.invite_not_found":         "Приглашение не найдено или устарело.",
+	"debt.invite_own":               "Это ваше приглашение - перешлите ссылку второй стороне долга.",
+	"debt.invite_processed":         "Это приглашение уже обработано.",
+	"debt.button_accept":            "✅ Подтвердить",
+	"debt.button_reject":            "❌ Отклонить",
+	"debt.button_settle":            "💸 Погашен",
+	"debt.button_settle_ok":         "✅ Да, погашен",
+	"debt.button_settle_no":         "❌ Нет",
+	"debt.accepted":                 "✅ Долг подтвержден:\n\n%s",
+	"debt.accepted_other":           "✅ Вторая сторона подтвердила долг:\n\n%s",
+	"debt.rejected":                 "❌ Вы отклонили долг.",
+	"debt.rejected_other":           "❌ Вторая сторона отклонила долг:\n\n%s",
+	"debt.not_found":                "Долг не найден",
+	"debt.settle_requested_already": "Погашение уже запрошено",
+	"debt.nothing_to_confirm":       "Нечего подтверждать",
+	"debt.settle_waiting":           "⏳ Ждем подтверждения погашения от второй стороны:\n\n%s",
+	"debt.settle_confirm":           "Вторая сторона отметила долг погашенным. Подтверждаете?\n\n%s",
+	"debt.settled":                  "🎉 Долг погашен:\n\n%s",
+	"debt.settled_other":            "🎉 Погашение подтверждено:\n\n%s",
+	"debt.settle_declined":          "Вы не подтвердили погашение, долг остается открытым:\n\n%s",
+	"debt.settle_declined_other":    "❌ Вторая сторона не подтвердила погашение:\n\n%s",
+	"debt.reminder":                 "🔔 *Напоминание: завтра срок возврата долга!*\n\n%s",
+	"debt.you_owe":                  "Вы должны",
+	"debt.owed_to_you":              "Вам должны",
+	"debt.format_amount":            "🤝 *%s:* %.2f ₽\n",
+	"debt.format_description":       "📝 *За что:* %s\n",
+	"debt.format_due_date":          "📅 *Вернуть до:* %s\n",
+	"debt.format_status":            "📌 *Статус:* %s",
+	"debt.status.pending":           "ждет подтверждения",
+	"debt.status.active":            "подтвержден",
+	"debt.status.rejected":          "отклонен",
+	"debt.status.settle_requested":  "ждет подтверждения погашения",
+	"debt.status.settled":           "погашен",
+
+	"debts.title":      "*Ваши долги:*",
+	"debts.empty":      "У вас нет открытых долгов. Используйте /newdebt чтобы записать долг.",
+	"debts.load_error": "Ошибка при получении списка долгов.",
+
+	"group.help": `*Общие траты группы*
+
+/split 3000 @a @b ужин - вы заплатили 3000 ₽, делим поровну между вами, @a и @b (без упоминаний - на всех участников)
+/paid @a 1000 - вы вернули @a 1000 ₽
+/balance - кто сколько должен группе
+/settle - минимальный список переводов, чтобы рассчитаться
+
+Я знаю только тех участников, кто уже писал в этот чат после моего добавления.`,
+	"group.private_only":     "Личные кредиты доступны только в личном чате со мной.",
+	"group.split_usage":      "Укажите сумму, например: /split 3000 @a @b ужин",
+	"group.members_error":    "Ошибка при получении списка участников.",
+	"group.split_nobody":     "Не с кем делить: упомяните участников или дождитесь, пока они напишут в чат.",
+	"group.split_error":      "Ошибка при сохранении траты. Попробуйте еще раз.",
+	"group.paid_usage":       "Укажите, кому и сколько вы вернули, например: /paid @a 1000",
+	"group.repayment":        "Возврат долга",
+	"group.paid_error":       "Ошибка при сохранении возврата. Попробуйте еще раз.",
+	"group.paid_done":        "✅ Записал: %s вернул(а) %s %.2f ₽.",
+	"group.balance_error":    "Ошибка при расчете балансов.",
+	"group.settled":          "Все в расчете 🤝",
+	"group.balance_title":    "*Балансы группы:*\n\n",
+	"group.balance_positive": "🟢 %s: группа должна %.2f ₽\n",
+	"group.balance_negative": "🔴 %s: должен(на) группе %.2f ₽\n",
+	"group.settle_error":     "Ошибка при расчете переводов.",
+	"group.reminder":         "🔔 *Напоминание о взаиморасчетах*\n\n%s",
+	"group.settle_title":     "*Чтобы рассчитаться:*\n\n",
+	"group.settle_item":      "%s → %s: %.2f ₽\n",
+	"group.settle_hint":      "\nПосле перевода отметьте его командой /paid",
+	"group.unknown_member":   "Я пока не знаю @%s - пусть напишет что-нибудь в этот чат.",
+	"group.member_error":     "Ошибка при поиске участника. Попробуйте еще раз.",
+	"group.someone":          "кто-то",
+
+	"import.help": `*Импорт кредитов из файла*
+
+Пришлите мне CSV или XLSX файл. Первая строка - заголовки колонок:
+- *bank_name* - название банка
+- *loan_amount* - сумма кредита, например 10000.50
+- *due_date* - дата платежа, например 2024-12-31 или 31.12.2024
+
+Подойдет и файл credits.csv из /export. Шаблон - ниже.`,
+	"import.bad_format":     "Я умею импортировать только CSV и XLSX файлы. Шаблон: /import",
+	"import.too_big":        "Файл слишком большой, максимум 1 МБ.",
+	"import.download_error": "Не удалось скачать файл. Попробуйте еще раз.",
+	"import.parse_error":    "Не удалось разобрать файл: %s\n\nШаблон: /import",
+	"import.preview":        "*Предпросмотр импорта*\n\nСтрок в файле: %d\n✅ Корректных: %d\n❌ С ошибками: %d\n",
+	"import.valid_title":    "\n*Будут добавлены:*\n",
+	"import.more":           "... и еще %d\n",
+	"import.invalid_title":  "\n*Ошибки:*\n",
+	"import.invalid_row":    "Строка %d: %s\n",
+	"import.valid_row":      "🏦 %s - %.2f ₽ - %s\n",
+	"import.nothing":        "\nИмпортировать нечего - исправьте файл и пришлите снова.",
+	"import.button_confirm": "📥 Импортировать (%d)",
+	"import.finished":       "Импорт уже завершен или отменен",
+	"import.cancelled":      "Импорт отменен.",
+	"import.error_short":    "Ошибка при импорте",
+	"import.error":          "Ошибка при импорте, ни один кредит не добавлен. Попробуйте еще раз.",
+
+	// Ошибки разбора файла импорта: показываются после "Не удалось разобрать файл:" и в строках с ошибками
+	"import.read_csv":            "не удалось прочитать CSV",
+	"import.open_xlsx":           "не удалось открыть XLSX",
+	"import.no_sheets":           "в файле нет листов",
+	"import.read_sheet":          "не удалось прочитать лист %s",
+	"import.empty_file":          "файл пустой",
+	"import.too_many_rows":       "слишком много строк: %d, максимум %d",
+	"import.no_credits":          "в файле нет строк с кредитами",
+	"import.missing_columns":     "в заголовке не найдены колонки: %s",
+	"import.row_bank":            "не указано название банка",
+	"import.row_amount":          "некорректная сумма",
+	"import.row_amount_positive": "сумма должна быть больше нуля",
+	"import.row_date":            "некорректная дата, например: 2024-12-31, 31.12.2024, 31 декабря",
+
+	"export.error": "Ошибка при подготовке выгрузки.",
+	"export.empty": "Выгружать пока нечего. Используйте /addcredit чтобы добавить кредит.",
+
+	"calendar.subscribe": "Чтобы календарь обновлялся сам, подпишитесь на него по ссылке ниже (в Google Calendar: \"Добавить календарь\" → \"По URL\").\n\nНикому не показывайте эту ссылку. Если она попала не в те руки, выпустите новую: /calendar reset",
+
+	"statement.usage":   "Укажите месяц в формате ГГГГ-ММ, например: /statement 2024-12",
+	"statement.error":   "Ошибка при формировании выписки.",
+	"statement.monthly": "📄 Ваша выписка по кредитам за %s %d",
+
+	"statement.pdf_title":     "Выписка за %s",
+	"statement.pdf_heading":   "Выписка по кредитам за %s",
+	"statement.pdf_generated": "Сформирована %s",
+	"statement.pdf_credits":   "Кредиты",
+	"statement.pdf_payments":  "Платежи за месяц",
+	"statement.pdf_bank":      "Банк",
+	"statement.pdf_amount":    "Сумма",
+	"statement.pdf_paid":      "Внесено",
+	"statement.pdf_remaining": "Остаток долга",
+	"statement.pdf_due_date":  "Дата платежа",
+	"statement.pdf_date":      "Дата",
+	"statement.pdf_total":     "Итого",
+	"statement.pdf_no_data":   "Нет данных",
+
+	"charts.balance_title": "Остаток долга по месяцам, ₽",
+	"charts.monthly_title": "Платежи по месяцам, ₽",
+	"charts.banks_title":   "Доля долга по банкам",
+
+	"ical.calendar_name": "Платежи по кредитам",
+	"ical.summary":       "Платеж по кредиту: %s - %.2f ₽",
+	"ical.description":   "Банк: %s\nСумма: %.2f ₽\nПлатеж №%d",
+
+	"income.ask":        "Введите ваш среднемесячный доход после налогов (0 - удалить):",
+	"income.bad_amount": "Некорректная сумма. Например: 85000, 85 000 ₽ или 85к",
+	"income.save_error": "Ошибка при сохранении дохода. Попробуйте еще раз.",
+	"income.deleted":    "Доход удален.",
+	"income.saved":      "Доход %.2f ₽ в месяц сохранен. Долговая нагрузка - в /summary",
+
+	"summary.error":            "Ошибка при расчете сводки.",
+	"summary.title":            "*Сводка по кредитам*\n\n",
+	"summary.outstanding":      "💰 Остаток долга: %s\n",
+	"summary.this_month":       "📅 Платежи в этом месяце: %s\n",
+	"summary.next_30_days":     "⏳ Платежи в ближайшие 30 дней: %s\n",
+	"summary.monthly_payments": "📊 Среднемесячный платеж: %s\n",
+	"summary.by_bank":          "\n*По банкам:*\n",
+	"summary.bank_item":        "%s %s: %s\n",
+	"summary.no_income":        "\nУкажите доход командой /income, чтобы узнать долговую нагрузку (ПДН).",
+	"summary.load":             "\n*ПДН:* %.0f%% (доход %s)\n",
+	"summary.level_critical":   "🔴 Очень высокая нагрузка: больше 80% дохода уходит на кредиты. Банки, скорее всего, откажут в новом кредите - стоит подумать о рефинансировании.",
+	"summary.level_warning":    "🟠 Высокая нагрузка: больше 50% дохода уходит на кредиты. Новый кредит банк может не одобрить.",
+	"summary.level_normal":     "🟢 Нагрузка в пределах нормы.",
+
+	"settings.text":             "*Настройки*\n\n🗓 Сводка на неделю (пн, %d:00): %s\n📆 Сводка на месяц (1-го, %d:00): %s\n🌍 Часовой пояс: %s\n🗣 Язык: %s\n\nСменить часовой пояс: /timezone Europe/Moscow, язык: /language",
+	"settings.timezone_default": "не указан (время сервера)",
+	"settings.on":               "включена",
+	"settings.off":              "выключена",
+	"settings.button_weekly":    "Неделя",
+	"settings.button_monthly":   "Месяц",
+	"settings.saved":            "Сохранено",
+	"settings.error":            "Ошибка при получении настроек.",
+
+	"timezone.usage":      "Укажите часовой пояс, например: /timezone Europe/Moscow или /timezone +3",
+	"timezone.unknown":    "Не знаю такой часовой пояс. Примеры: Europe/Moscow, Asia/Yekaterinburg, +3",
+	"timezone.save_error": "Ошибка при сохранении часового пояса.",
+	"timezone.saved":      "Часовой пояс сохранен. У вас сейчас %s.",
+
+	"digest.weekly":              "🗓 *Платежи на неделю %s - %s*\n\n%s\n*Итого:* %s",
+	"digest.monthly_title":       "📆 *Сводка на %s %d*\n\n",
+	"digest.monthly_paid":        "Внесено за %s: %s\n",
+	"digest.monthly_outstanding": "Остаток долга: %s\n",
+	"digest.monthly_load":        "ПДН: %.0f%%\n",
+
+	"month.1":  "январь",
+	"month.2":  "февраль",
+	"month.3":  "март",
+	"month.4":  "апрель",
+	"month.5":  "май",
+	"month.6":  "июнь",
+	"month.7":  "июль",
+	"month.8":  "август",
+	"month.9":  "сентябрь",
+	"month.10": "октябрь",
+	"month.11": "ноябрь",
+	"month.12": "декабрь",
+
+	"month_short.1":  "янв",
+	"month_short.2":  "фев",
+	"month_short.3":  "мар",
+	"month_short.4":  "апр",
+	"month_short.5":  "май",
+	"month_short.6":  "июн",
+	"month_short.7":  "июл",
+	"month_short.8":  "авг",
+	"month_short.9":  "сен",
+	"month_short.10": "окт",
+	"month_short.11": "ноя",
+	"month_short.12": "дек",
+
+	"weekday.0": "Вс",
+	"weekday.1": "Пн",
+	"weekday.2": "Вт",
+	"weekday.3": "Ср",
+	"weekday.4": "Чт",
+	"weekday.5": "Пт",
+	"weekday.6": "Сб",
+
+	"intent.unknown":           "Не понял сообщение 🤔 Можно написать, например: «заплатил по тинькофф 5000», «сколько я должен?», «мои кредиты» — или выбрать действие в меню. Все команды: /help",
+	"intent.payment_ask_bank":  "По какому кредиту платеж? Напишите банк, например: «заплатил по тинькофф 5000».",
+	"intent.payment_not_found": "Не нашел кредит в банке «%s». Ваши кредиты: /mycredits",
+	"intent.payment_confirm":   "Записать платеж %s по кредиту *%s*?",
+	"intent.button_confirm":    "✅ Записать",
+	"intent.payment_done":      "✅ Платеж %s записан.",
+	"intent.cancelled":         "Платеж не записан.",
+	"intent.expired":           "Платеж уже записан или отменен",
+
+	"bank.suggest":     "Не нашел такой банк в справочнике. Возможно, вы имели в виду:",
+	"bank.button_keep": "Оставить «%s»",
+	"bank.chosen":      "🏦 Банк: %s",
+	"bank.expired":     "Банк уже выбран",
+
+	"reminders.empty":          "Напоминаний о платежах еще не было.",
+	"reminders.title":          "*Последние напоминания о платежах:*",
+	"reminders.line":           "%s, %s: %s",
+	"reminders.deleted_credit": "удаленный кредит",
+	"reminders.status_sent":    "✅ доставлено %s",
+	"reminders.status_pending": "⏳ в очереди",
+	"reminders.status_failed":  "❌ не доставлено",
+	"reminders.status_blocked": "🚫 не доставлено: бот был заблокирован",
+
+	"admin.help":                "*Администратору:* /stats - статистика, /broadcast <текст> - рассылка всем, /user <id> - данные пользователя, /ban <id> и /unban <id> - закрыть и открыть доступ, /invite - код приглашения, /jobs - периодические задачи, /backup - резервная копия базы.",
+	"admin.banned_notice":       "🚫 Доступ к боту ограничен.",
+	"admin.stats":               "*Статистика*\n👥 Пользователей: %d\n✅ Активных за 30 дней: %d\n🔕 Заблокировали бота: %d\n🚫 Забанено: %d\n🏦 Кредитов: %d\n🔔 Напоминаний отправлено сегодня: %d\n📤 В очереди отправки: %d",
+	"admin.broadcast_usage":     "Укажите текст рассылки: /broadcast <текст>. Поддерживается Markdown.",
+	"admin.button_send":         "📤 Отправить",
+	"admin.broadcast_expired":   "Рассылка устарела, отправьте /broadcast еще раз",
+	"admin.broadcast_cancelled": "Рассылка отменена.",
+	"admin.user_usage":          "Укажите Telegram ID: /user <id>",
+	"admin.user_not_found":      "Пользователь %d не найден.",
+	"admin.status_active":       "активен",
+	"admin.status_blocked":      "заблокировал бота",
+	"admin.status_banned":       "забанен",
+	"admin.never":               "никогда",
+	"admin.server_timezone":     "как у сервера",
+	"admin.user_info":           "👤 *Пользователь %d*\nЗарегистрирован: %s\nПоследняя активность: %s\nЯзык: %s, часовой пояс: %s\nСтатус: %s\nОткрытых долгов: %d, платежей: %d\n",
+	"admin.user_credit":         "• #%d %s, %s ₽, платеж %s\n",
+	"admin.user_credits":        "Кредитов: %d\n",
+	"admin.ban_usage":           "Укажите Telegram ID: /ban <id>",
+	"admin.unban_usage":         "Укажите Telegram ID: /unban <id>",
+	"admin.ban_admin":           "Администратора нельзя забанить.",
+	"admin.ban_done":            "🚫 Пользователь %d забанен.",
+	"admin.unban_done":          "✅ Доступ пользователя %d восстановлен.",
+	"admin.backup_error":        "Не удалось создать резервную копию, подробности в журнале.",
+	"admin.backup_too_large":    "Резервная копия создана, но слишком велика для Telegram: `%s`",
+	"admin.jobs_empty":          "Периодических задач нет.",
+	"admin.jobs_title":          "*Периодические задачи:*",
+	"admin.job":                 "• %s: последний запуск %s, следующий %s",
+	"admin.job_running":         " (выполняется)",
+
+	"access.private":            "🔒 Это частный бот, доступ открыт только его участникам.\nЕсли вы из команды, сообщите администратору свой Telegram ID: `%d`",
+	"access.invite_required":    "🔒 Это частный бот, пользоваться им можно по приглашению.\nОткройте ссылку-приглашение или отправьте код, который вам дали.",
+	"access.invite_invalid":     "Код приглашения не подошел: он неверный, истек или уже использован. Попросите новый у того, кто вас пригласил.",
+	"access.welcome":            "🎉 Приглашение принято, добро пожаловать!",
+	"access.denied":             "🔒 Нет доступа",
+	"access.group_denied":       "🔒 Это частный бот, пользоваться им могут только его участники. Чтобы получить доступ, напишите мне в личные сообщения.",
+	"admin.invite_usage":        "Использование: /invite [число использований] [срок в днях]. Например: /invite 5 30",
+	"admin.invite_no_expiry":    "бессрочный",
+	"admin.invite_expires":      "действует до %s",
+	"admin.invite_mode_warning": "⚠️ Сейчас режим доступа %s: код сработает только при access\\_mode: invite.",
+
+	"account.delete_confirm":   "⚠️ *Удалить аккаунт?*\nБудут безвозвратно удалены кредиты, платежи, долги без второй стороны, настройки и история уведомлений. Восстановить их будет нельзя.\nДолги с другими пользователями и общие траты в группах останутся у остальных участников, но без вашего Telegram ID.\nСохранить копию можно командой /mydata.",
+	"account.button_delete":    "Удалить навсегда",
+	"account.delete_cancelled": "Удаление отменено.",
+	"account.deleted":          "Аккаунт и все данные удалены. Чтобы начать заново, отправьте /start.",
+
+	"trash.button_undo":    "↩️ Отменить",
+	"trash.undo_expired":   "Время отмены истекло. Восстановить кредит можно в /trash.",
+	"trash.empty":          "Корзина пуста.",
+	"trash.item":           "%d. *%s* - %s, удален %s\n",
+	"trash.button_restore": "↩️ %d. %s",
+	"trash.not_found":      "Кредит уже восстановлен или удален окончательно.",
+	"trash.restored":       "✅ Кредит *%s* на %s восстановлен.",
+}
+
+// Формы: 1 кредит, 2 кредита, 5 кредитов
+var ruPlurals = map[string]Plural{
+	"credit.confirm_term":       {"🗓 *Срок:* %d месяц\n", "🗓 *Срок:* %d месяца\n", "🗓 *Срок:* %d месяцев\n"},
+	"summary.credits":           {"🏦 %d кредит\n", "🏦 %d кредита\n", "🏦 %d кредитов\n"},
+	"group.split_done":          {"✅ Трата %.2[2]f ₽ записана, по %.2[3]f ₽ на каждого из %[1]d участника.", "✅ Трата %.2[2]f ₽ записана, по %.2[3]f ₽ на каждого из %[1]d участников.", "✅ Трата %.2[2]f ₽ записана, по %.2[3]f ₽ на каждого из %[1]d участников."},
+	"import.more_invalid":       {"... и еще %d строка с ошибками\n", "... и еще %d строки с ошибками\n", "... и еще %d строк с ошибками\n"},
+	"import.done":               {"✅ Импортирован %d кредит. Посмотреть: /mycredits", "✅ Импортировано %d кредита. Посмотреть: /mycredits", "✅ Импортировано %d кредитов. Посмотреть: /mycredits"},
+	"digest.monthly_payments":   {"В этом месяце %d платеж на %s\n", "В этом месяце %d платежа на %s\n", "В этом месяце %d платежей на %s\n"},
+	"reminders.status_retrying": {"⏳ повторная отправка, %d попытка", "⏳ повторная отправка, %d попытки", "⏳ повторная отправка, %d попыток"},
+	"admin.broadcast_confirm":   {"Отправить это сообщение %d пользователю?", "Отправить это сообщение %d пользователям?", "Отправить это сообщение %d пользователям?"},
+	"admin.broadcast_queued":    {"📤 Рассылка поставлена в очередь для %d пользователя.", "📤 Рассылка поставлена в очередь для %d пользователей.", "📤 Рассылка поставлена в очередь для %d пользователей."},
+	"admin.invite_created":      {"🎟 Код приглашения `%[2]s` на %[1]d использование, %[3]s. Ссылка:", "🎟 Код приглашения `%[2]s` на %[1]d использования, %[3]s. Ссылка:", "🎟 Код приглашения `%[2]s` на %[1]d использований, %[3]s. Ссылка:"},
+	"credit.deleted":            {"🗑 Кредит перемещен в корзину. Он хранится там %d день, восстановить можно в /trash.", "🗑 Кредит перемещен в корзину. Он хранится там %d дня, восстановить можно в /trash.", "🗑 Кредит перемещен в корзину. Он хранится там %d дней, восстановить можно в /trash."},
+	"statement.pdf_upcoming":    {"Предстоящие платежи (%d день)", "Предстоящие платежи (%d дня)", "Предстоящие платежи (%d дней)"},
+	"trash.title":               {"🗑 *Корзина*\nКредиты удаляются окончательно через %d день после удаления.\n\n", "🗑 *Корзина*\nКредиты удаляются окончательно через %d дня после удаления.\n\n", "🗑 *Корзина*\nКредиты удаляются окончательно через %d дней после удаления.\n\n"},
+}
@@ -0,0 +1,78 @@
+// Package ical формирует календарь платежей в формате iCalendar (RFC 5545)
+package ical
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"DebtBot/i18n"
+	"DebtBot/schedule"
+)
+
+// Напоминание в календаре: за 15 часов до начала дня платежа, т.е. в 9:00 накануне
+const alarmTrigger = "-PT15H"
+
+// PaymentsCalendar строит календарь с событием и напоминанием на каждый предстоящий платеж
+// на языке tr. Платежи до from (прошедшие) в календарь не попадают.
+func PaymentsCalendar(tr *i18n.Localizer, installments []schedule.Installment, from time.Time) []byte {
+	today := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	stamp := from.UTC().Format("20060102T150405Z")
+
+	var b strings.Builder
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:-//DebtBot//Payments//"+strings.ToUpper(string(tr.Lang())))
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+	writeLine(&b, "X-WR-CALNAME:"+escapeText(tr.T("ical.calendar_name")))
+
+	for _, installment := range installments {
+		date := time.Date(installment.Date.Year(), installment.Date.Month(), installment.Date.Day(), 0, 0, 0, 0, time.UTC)
+		if date.Before(today) {
+			continue
+		}
+		summary := tr.T("ical.summary", installment.BankName, installment.Amount)
+
+		writeLine(&b, "BEGIN:VEVENT")
+		writeLine(&b, fmt.Sprintf("UID:credit-%d-%d@debtbot", installment.CreditID, installment.Number))
+		writeLine(&b, "DTSTAMP:"+stamp)
+		writeLine(&b, "DTSTART;VALUE=DATE:"+date.Format("20060102"))
+		writeLine(&b, "DTEND;VALUE=DATE:"+date.AddDate(0, 0, 1).Format("20060102"))
+		writeLine(&b, "SUMMARY:"+escapeText(summary))
+		writeLine(&b, "DESCRIPTION:"+escapeText(tr.T("ical.description", installment.BankName, installment.Amount, installment.Number)))
+		writeLine(&b, "TRANSP:TRANSPARENT")
+		writeLine(&b, "BEGIN:VALARM")
+		writeLine(&b, "ACTION:DISPLAY")
+		writeLine(&b, "TRIGGER:"+alarmTrigger)
+		writeLine(&b, "DESCRIPTION:"+escapeText(summary))
+		writeLine(&b, "END:VALARM")
+		writeLine(&b, "END:VEVENT")
+	}
+
+	writeLine(&b, "END:VCALENDAR")
+	return []byte(b.String())
+}
+
+// Экранирование текстовых значений (RFC 5545, 3.3.11)
+func escapeText(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return replacer.Replace(s)
+}
+
+// writeLine пишет строку с CRLF, перенося ее каждые 75 октетов (RFC 5545, 3.1).
+// Переносы не разрывают многобайтовые символы UTF-8.
+func writeLine(b *strings.Builder, line string) {
+	const limit = 75
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > limit {
+			b.WriteString("\r\n ")
+			width = 1 // Пробел в начале продолжения тоже считается
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	b.WriteString("\r\n")
+}
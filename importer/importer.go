@@ -0,0 +1,251 @@
+// Package importer разбирает CSV и XLSX файлы с кредитами пользователя.
+//
+// Шаблон файла - первая строка с заголовками, далее по кредиту на строку:
+//
+//	bank_name;loan_amount;due_date
+//	Сбербанк;150000;2025-03-15
+//
+// Вместо английских заголовков можно использовать русские из /export
+// ("Банк", "Сумма", "Дата платежа"), лишние колонки игнорируются.
+// Разделитель CSV - ";", "," или табуляция, определяется по строке заголовков.
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"DebtBot/banks"
+	"DebtBot/i18n"
+	"DebtBot/models"
+	"DebtBot/utils"
+	"github.com/xuri/excelize/v2"
+)
+
+// Максимальное количество строк в одном файле
+const MaxRows = 500
+
+// Template - пример файла для импорта
+const Template = "bank_name;loan_amount;due_date\nСбербанк;150000;2025-03-15\nТинькофф;23000.50;2025-04-01\n"
+
+// Колонки шаблона и их допустимые заголовки
+var columnAliases = map[string][]string{
+	"bank_name":   {"bank_name", "bank", "банк", "название банка"},
+	"loan_amount": {"loan_amount", "amount", "сумма", "сумма кредита"},
+	"due_date":    {"due_date", "date", "дата платежа", "дата"},
+}
+
+// Row - результат разбора одной строки файла
+type Row struct {
+	Line   int            // Номер строки в файле, начиная с 1 (заголовок - строка 1)
+	Credit *models.Credit // nil, если в строке есть ошибки
+	Errors []string
+}
+
+// Result - результат разбора всего файла
+type Result struct {
+	Rows []Row
+}
+
+// Valid возвращает кредиты из строк без ошибок
+func (r *Result) Valid() []*models.Credit {
+	credits := []*models.Credit{}
+	for _, row := range r.Rows {
+		if row.Credit != nil {
+			credits = append(credits, row.Credit)
+		}
+	}
+	return credits
+}
+
+// Invalid возвращает строки с ошибками
+func (r *Result) Invalid() []Row {
+	rows := []Row{}
+	for _, row := range r.Rows {
+		if len(row.Errors) > 0 {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// ParseCSV разбирает CSV файл. Ошибки файла и строк - на языке tr.
+func ParseCSV(tr *i18n.Localizer, content []byte) (*Result, error) {
+	content = bytes.TrimPrefix(content, []byte("\ufeff")) // BOM от Excel и /export
+
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.Comma = detectDelimiter(content)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records := [][]string{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", tr.T("import.read_csv"), err)
+		}
+		records = append(records, record)
+	}
+	return parseRecords(tr, records, nil)
+}
+
+// ParseXLSX разбирает первый лист XLSX файла. Ошибки файла и строк - на языке tr.
+func ParseXLSX(tr *i18n.Localizer, content []byte) (*Result, error) {
+	file, err := excelize.OpenReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", tr.T("import.open_xlsx"), err)
+	}
+	defer file.Close()
+
+	sheets := file.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, errors.New(tr.T("import.no_sheets"))
+	}
+	// Сырые значения: даты приходят числом Excel, а не строкой в локальном формате
+	records, err := file.GetRows(sheets[0], excelize.Options{RawCellValue: true})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", tr.T("import.read_sheet", sheets[0]), err)
+	}
+	return parseRecords(tr, records, excelDate)
+}
+
+// parseRecords проверяет строки по тем же правилам, что и диалог добавления кредита.
+// convertDate при необходимости переводит значение ячейки с датой в ГГГГ-ММ-ДД.
+func parseRecords(tr *i18n.Localizer, records [][]string, convertDate func(string) string) (*Result, error) {
+	if len(records) == 0 {
+		return nil, errors.New(tr.T("import.empty_file"))
+	}
+
+	columns, err := mapColumns(tr, records[0])
+	if err != nil {
+		return nil, err
+	}
+	if len(records)-1 > MaxRows {
+		return nil, errors.New(tr.T("import.too_many_rows", len(records)-1, MaxRows))
+	}
+
+	result := &Result{}
+	for i, record := range records[1:] {
+		if isEmptyRecord(record) {
+			continue
+		}
+
+		row := Row{Line: i + 2}
+		bankName, err := utils.ValidateBankName(cell(record, columns["bank_name"]))
+		if err != nil {
+			row.Errors = append(row.Errors, validationError(tr, err))
+		}
+		amount, err := utils.ParseAmount(cell(record, columns["loan_amount"]))
+		if err != nil {
+			row.Errors = append(row.Errors, validationError(tr, err))
+		}
+		rawDate := cell(record, columns["due_date"])
+		if convertDate != nil {
+			rawDate = convertDate(rawDate)
+		}
+		dueDate, err := utils.ParseDate(rawDate)
+		if err != nil {
+			row.Errors = append(row.Errors, validationError(tr, err))
+		}
+
+		if len(row.Errors) == 0 {
+			row.Credit = &models.Credit{BankName: bankName, LoanAmount: amount, DueDate: dueDate}
+			if bank, ok := banks.Match(bankName); ok {
+				row.Credit.BankName = bank.Name
+				row.Credit.BankID = bank.ID
+			}
+		}
+		result.Rows = append(result.Rows, row)
+	}
+
+	if len(result.Rows) == 0 {
+		return nil, errors.New(tr.T("import.no_credits"))
+	}
+	return result, nil
+}
+
+// validationError - текст ошибки проверки значения из utils на языке tr
+func validationError(tr *i18n.Localizer, err error) string {
+	switch {
+	case errors.Is(err, utils.ErrNoBankName):
+		return tr.T("import.row_bank")
+	case errors.Is(err, utils.ErrNonPositiveAmount):
+		return tr.T("import.row_amount_positive")
+	case errors.Is(err, utils.ErrInvalidDate):
+		return tr.T("import.row_date")
+	}
+	return tr.T("import.row_amount")
+}
+
+// mapColumns находит номера обязательных колонок по заголовкам
+func mapColumns(tr *i18n.Localizer, header []string) (map[string]int, error) {
+	columns := make(map[string]int)
+	for i, title := range header {
+		title = strings.ToLower(strings.TrimSpace(title))
+		for column, aliases := range columnAliases {
+			for _, alias := range aliases {
+				if title == alias {
+					columns[column] = i
+				}
+			}
+		}
+	}
+
+	missing := []string{}
+	for _, column := range []string{"bank_name", "loan_amount", "due_date"} {
+		if _, ok := columns[column]; !ok {
+			missing = append(missing, column)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, errors.New(tr.T("import.missing_columns", strings.Join(missing, ", ")))
+	}
+	return columns, nil
+}
+
+func detectDelimiter(content []byte) rune {
+	header, _, _ := bytes.Cut(content, []byte("\n"))
+	best, bestCount := ';', 0
+	for _, delimiter := range []rune{';', ',', '\t'} {
+		if count := bytes.Count(header, []byte(string(delimiter))); count > bestCount {
+			best, bestCount = delimiter, count
+		}
+	}
+	return best
+}
+
+// Даты в XLSX хранятся как число дней от 1899-12-30
+func excelDate(value string) string {
+	serial, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return value // Дата записана текстом
+	}
+	date, err := excelize.ExcelDateToTime(serial, false)
+	if err != nil {
+		return value
+	}
+	return date.Format(utils.DateLayout)
+}
+
+func cell(record []string, index int) string {
+	if index < len(record) {
+		return strings.TrimSpace(record[index])
+	}
+	return ""
+}
+
+func isEmptyRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
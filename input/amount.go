@@ -0,0 +1,105 @@
+// Package input разбирает суммы и даты, которые пользователь пишет в диалогах "как привык":
+//
+//	Суммы: "10000.50", "10 000,50 ₽", "10,000", "15к", "15k", "2 тыс", "1,5 млн", "1.5m", "3 000 руб."
+//	Даты:  "2024-12-31", "31.12.2024", "31.12.24", "31.12", "31/12/2024",
+//	       "31 декабря", "31 дек 2024", "December 31", "31 dec 2024",
+//	       "сегодня", "завтра", "послезавтра", "today", "tomorrow",
+//	       "через 2 недели", "через месяц", "in 3 days", "in a week",
+//	       "каждое 15 число", "15 числа", "15-го", "every 15th"
+//
+// Дата без года - ближайшая такая дата начиная с сегодняшнего дня.
+package input
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Множители сумм. Длинные варианты идут раньше коротких, чтобы "тыс" не разобрать как "т".
+var amountMultipliers = []struct {
+	suffix     string
+	multiplier float64
+}{
+	{"миллиона", 1e6}, {"миллионов", 1e6}, {"миллион", 1e6}, {"млн.", 1e6}, {"млн", 1e6},
+	{"mln", 1e6}, {"kk", 1e6}, {"кк", 1e6}, {"m", 1e6}, {"м", 1e6},
+	{"тысячи", 1e3}, {"тысяч", 1e3}, {"тысяча", 1e3}, {"тыс.", 1e3}, {"тыс", 1e3},
+	{"k", 1e3}, {"к", 1e3}, {"т", 1e3},
+}
+
+// Обозначения валюты, которые отбрасываются
+var currencySuffixes = []string{"рублей", "рубля", "рубль", "руб.", "руб", "р.", "р", "rub", "rur", "₽"}
+
+// Пробелы, которыми разделяют разряды (обычный, неразрывный, узкие)
+var digitSpaces = strings.NewReplacer(" ", "", "\u00a0", "", "\u2009", "", "\u202f", "", "'", "", "_", "")
+
+// Amount разбирает неотрицательную сумму, округляя ее до копеек.
+// Одна запятая считается десятичной ("1,5 млн"), если за ней не ровно три цифры ("10,000");
+// несколько запятых - разделители разрядов ("1,000,000").
+func Amount(s string) (float64, error) {
+	text := strings.ToLower(strings.TrimSpace(s))
+	text = trimAnySuffix(text, currencySuffixes)
+
+	multiplier := 1.0
+	for _, m := range amountMultipliers {
+		if strings.HasSuffix(text, m.suffix) {
+			text = strings.TrimSpace(strings.TrimSuffix(text, m.suffix))
+			multiplier = m.multiplier
+			break
+		}
+	}
+	text = trimAnySuffix(text, currencySuffixes)
+
+	number := normalizeNumber(digitSpaces.Replace(text))
+	if number == "" {
+		return 0, errors.New("некорректная сумма")
+	}
+	for _, r := range number {
+		if (r < '0' || r > '9') && r != '.' {
+			return 0, errors.New("некорректная сумма")
+		}
+	}
+
+	amount, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, errors.New("некорректная сумма")
+	}
+	amount = math.Round(amount*multiplier*100) / 100
+	if math.IsInf(amount, 0) || math.IsNaN(amount) {
+		return 0, errors.New("некорректная сумма")
+	}
+	return amount, nil
+}
+
+// normalizeNumber приводит число к виду, понятному strconv.ParseFloat: "10.000,50" -> "10000.50"
+func normalizeNumber(s string) string {
+	commas, dots := strings.Count(s, ","), strings.Count(s, ".")
+	switch {
+	case commas > 0 && dots > 0:
+		// Десятичный разделитель - тот, что стоит последним
+		if strings.LastIndex(s, ",") > strings.LastIndex(s, ".") {
+			s = strings.ReplaceAll(s, ".", "")
+			return strings.Replace(s, ",", ".", 1)
+		}
+		return strings.ReplaceAll(s, ",", "")
+	case commas == 1 && len(s)-strings.Index(s, ",") == 4:
+		return strings.Replace(s, ",", "", 1)
+	case commas == 1:
+		return strings.Replace(s, ",", ".", 1)
+	case commas > 1:
+		return strings.ReplaceAll(s, ",", "")
+	case dots > 1:
+		return strings.ReplaceAll(s, ".", "")
+	}
+	return s
+}
+
+func trimAnySuffix(s string, suffixes []string) string {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return strings.TrimSpace(strings.TrimSuffix(s, suffix))
+		}
+	}
+	return s
+}
@@ -0,0 +1,95 @@
+package input
+
+import "testing"
+
+func TestAmount(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+	}{
+		// Десятичные и разделители разрядов
+		{"10000", 10000},
+		{"10000.50", 10000.5},
+		{"10000,50", 10000.5},
+		{"10 000", 10000},
+		{"10 000,50", 10000.5},
+		{"10 000,50 ₽", 10000.5},
+		{"10\u00a0000,50", 10000.5}, // Неразрывный пробел
+		{"10\u202f000", 10000},      // Узкий неразрывный пробел
+		{"10_000", 10000},
+		{"10,000", 10000},
+		{"1,000,000", 1000000},
+		{"1.000.000", 1000000},
+		{"10.000,50", 10000.5},
+		{"10,000.50", 10000.5},
+		{"10,5", 10.5},
+		{"0.99", 0.99},
+		{"99.999", 100},
+		{"0", 0},
+
+		// Валюта
+		{"3 000 руб.", 3000},
+		{"3000 руб", 3000},
+		{"100 рублей", 100},
+		{"1 рубль", 1},
+		{"500р", 500},
+		{"500 р.", 500},
+		{"500 RUB", 500},
+		{"500₽", 500},
+
+		// Множители
+		{"15к", 15000},
+		{"15 к", 15000},
+		{"15k", 15000},
+		{"15K", 15000},
+		{"15т", 15000},
+		{"2 тыс", 2000},
+		{"2 тыс.", 2000},
+		{"2,5 тысячи", 2500},
+		{"10 тысяч", 10000},
+		{"1,5 млн", 1500000},
+		{"1.5 млн.", 1500000},
+		{"1.5m", 1500000},
+		{"2 миллиона", 2000000},
+		{"3кк", 3000000},
+		{"1,5 млн ₽", 1500000},
+		{"15к руб", 15000},
+	}
+	for _, tt := range tests {
+		got, err := Amount(tt.in)
+		if err != nil {
+			t.Errorf("Amount(%q): unexpected error %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Amount(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestAmountInvalid(t *testing.T) {
+	tests := []string{
+		"",
+		"   ",
+		"abc",
+		"₽",
+		"млн",
+		"-5",
+		"−5",
+		"-1 000",
+		"+5",
+		"10 000 abc",
+		"10$",
+		"1e5",
+		"Inf",
+		"NaN",
+		"1.2.3,4,5",
+		"12:30",
+		"5%",
+	}
+	for _, in := range tests {
+		if got, err := Amount(in); err == nil {
+			t.Errorf("Amount(%q) = %v, want error", in, got)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package input
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errDate = errors.New("некорректная дата")
+
+// Месяцы по началу слова: "дек", "декабря", "december", "dec"
+var monthPrefixes = []struct {
+	prefix string
+	month  time.Month
+}{
+	{"янв", time.January}, {"фев", time.February}, {"мар", time.March}, {"апр", time.April},
+	{"мая", time.May}, {"май", time.May}, {"июн", time.June}, {"июл", time.July},
+	{"авг", time.August}, {"сен", time.September}, {"окт", time.October}, {"ноя", time.November},
+	{"дек", time.December},
+	{"jan", time.January}, {"feb", time.February}, {"mar", time.March}, {"apr", time.April},
+	{"may", time.May}, {"jun", time.June}, {"jul", time.July}, {"aug", time.August},
+	{"sep", time.September}, {"oct", time.October}, {"nov", time.November}, {"dec", time.December},
+}
+
+// Числа, которые пишут словами: "через две недели", "in a week"
+var numberWords = map[string]int{
+	"один": 1, "одну": 1, "одна": 1, "два": 2, "две": 2, "три": 3, "четыре": 4, "пять": 5,
+	"шесть": 6, "семь": 7, "восемь": 8, "девять": 9, "десять": 10, "пару": 2,
+	"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5,
+	"six": 6, "seven": 7, "eight": 8, "nine": 9, "ten": 10, "couple": 2,
+}
+
+var (
+	isoDate     = regexp.MustCompile(`^(\d{4})-(\d{1,2})-(\d{1,2})$`)
+	numericDate = regexp.MustCompile(`^(\d{1,2})[./-](\d{1,2})(?:[./-](\d{2}|\d{4}))?$`)
+	dayMonth    = regexp.MustCompile(`^(\d{1,2})(?:-?(?:го|е|st|nd|rd|th))?\s+(?:of\s+)?([\p{L}]+)\.?(?:\s+(\d{4}))?$`)
+	monthDay    = regexp.MustCompile(`^([\p{L}]+)\.?\s+(\d{1,2})(?:st|nd|rd|th)?,?(?:\s+(\d{4}))?$`)
+	relative    = regexp.MustCompile(`^(?:через|in)\s+(?:(\d+|[\p{L}]+)\s+)?([\p{L}]+)$`)
+	yearSuffix  = regexp.MustCompile(`(\d{4}|\.\d{2})\s?(?:года|год|г)\.?$`)
+	monthlyDay  = regexp.MustCompile(`^(?:(?:каждое|каждого|every|on\s+the)\s+)?(\d{1,2})(?:-?(?:го|е|ое|st|nd|rd|th))?(?:\s+(?:число|числа|of\s+(?:every|each|the)\s+month))?(?:\s+каждого\s+месяца)?$`)
+)
+
+// Date разбирает дату платежа относительно текущего момента now.
+// Возвращает полночь даты в UTC - так же, как time.Parse для "2006-01-02".
+func Date(s string, now time.Time) (time.Time, error) {
+	text := normalizeDate(s)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch text {
+	case "сегодня", "today":
+		return today, nil
+	case "завтра", "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "послезавтра", "day after tomorrow":
+		return today.AddDate(0, 0, 2), nil
+	}
+
+	if m := isoDate.FindStringSubmatch(text); m != nil {
+		return makeDate(atoi(m[1]), atoi(m[2]), atoi(m[3]))
+	}
+	if m := numericDate.FindStringSubmatch(text); m != nil {
+		return dateWithOptionalYear(today, atoi(m[1]), time.Month(atoi(m[2])), m[3])
+	}
+	if m := relative.FindStringSubmatch(text); m != nil {
+		return relativeDate(today, m[1], m[2])
+	}
+	if day, ok := MonthlyDay(text); ok {
+		return nextMonthlyDate(today, day), nil
+	}
+	if m := dayMonth.FindStringSubmatch(text); m != nil {
+		if month, ok := parseMonth(m[2]); ok {
+			return dateWithOptionalYear(today, atoi(m[1]), month, m[3])
+		}
+	}
+	if m := monthDay.FindStringSubmatch(text); m != nil {
+		if month, ok := parseMonth(m[1]); ok {
+			return dateWithOptionalYear(today, atoi(m[2]), month, m[3])
+		}
+	}
+	return time.Time{}, errDate
+}
+
+// MonthlyDay распознает ежемесячный день платежа: "каждое 15 число", "15 числа", "15-го", "every 15th"
+func MonthlyDay(s string) (int, bool) {
+	m := monthlyDay.FindStringSubmatch(normalizeDate(s))
+	if m == nil {
+		return 0, false
+	}
+	// Голое число ("15") - это не день месяца, а, скорее всего, опечатка в дате или сумме
+	if normalizeDate(s) == m[1] {
+		return 0, false
+	}
+	day := atoi(m[1])
+	if day < 1 || day > 31 {
+		return 0, false
+	}
+	return day, true
+}
+
+// Ближайшая (начиная с today) дата с днем day; в коротких месяцах - последний день месяца
+func nextMonthlyDate(today time.Time, day int) time.Time {
+	for i := 0; ; i++ {
+		month := time.Date(today.Year(), today.Month()+time.Month(i), 1, 0, 0, 0, 0, time.UTC)
+		candidate := time.Date(month.Year(), month.Month(), min(day, daysIn(month)), 0, 0, 0, 0, time.UTC)
+		if !candidate.Before(today) {
+			return candidate
+		}
+	}
+}
+
+func relativeDate(today time.Time, count, unit string) (time.Time, error) {
+	n := 1
+	if count != "" {
+		if value, err := strconv.Atoi(count); err == nil {
+			n = value
+		} else if value, ok := numberWords[count]; ok {
+			n = value
+		} else {
+			return time.Time{}, errDate
+		}
+	}
+	if n <= 0 || n > 1000 {
+		return time.Time{}, errDate
+	}
+
+	switch {
+	case strings.HasPrefix(unit, "д"), strings.HasPrefix(unit, "day"):
+		return today.AddDate(0, 0, n), nil
+	case strings.HasPrefix(unit, "нед"), strings.HasPrefix(unit, "week"):
+		return today.AddDate(0, 0, 7*n), nil
+	case strings.HasPrefix(unit, "мес"), strings.HasPrefix(unit, "month"):
+		return addMonths(today, n), nil
+	case strings.HasPrefix(unit, "год"), strings.HasPrefix(unit, "лет"), strings.HasPrefix(unit, "year"):
+		return addMonths(today, 12*n), nil
+	}
+	return time.Time{}, errDate
+}
+
+// addMonths прибавляет месяцы, не перескакивая в следующий месяц: 31 января + 1 месяц = 28/29 февраля
+func addMonths(t time.Time, months int) time.Time {
+	month := time.Date(t.Year(), t.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+	return time.Date(month.Year(), month.Month(), min(t.Day(), daysIn(month)), 0, 0, 0, 0, time.UTC)
+}
+
+// Дата без года - ближайшая начиная с today; год из двух цифр - 20xx
+func dateWithOptionalYear(today time.Time, day int, month time.Month, year string) (time.Time, error) {
+	if year != "" {
+		y := atoi(year)
+		if len(year) == 2 {
+			y += 2000
+		}
+		return makeDate(y, int(month), day)
+	}
+
+	date, err := makeDate(today.Year(), int(month), day)
+	if err != nil && !(month == time.February && day == 29) {
+		return time.Time{}, err
+	}
+	if err != nil || date.Before(today) {
+		// 29 февраля без года - ближайший високосный год
+		for y := today.Year() + 1; y <= today.Year()+8; y++ {
+			if date, err = makeDate(y, int(month), day); err == nil {
+				return date, nil
+			}
+		}
+	}
+	return date, err
+}
+
+// makeDate проверяет, что дата существует (31.02 - нет)
+func makeDate(year, month, day int) (time.Time, error) {
+	if month < 1 || month > 12 || day < 1 {
+		return time.Time{}, errDate
+	}
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if date.Day() != day || int(date.Month()) != month {
+		return time.Time{}, errDate
+	}
+	return date, nil
+}
+
+func parseMonth(word string) (time.Month, bool) {
+	for _, m := range monthPrefixes {
+		if strings.HasPrefix(word, m.prefix) {
+			return m.month, true
+		}
+	}
+	return 0, false
+}
+
+func daysIn(month time.Time) int {
+	return time.Date(month.Year(), month.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// normalizeDate приводит ввод к нижнему регистру, схлопывает пробелы и убирает "г."/"года"
+// после года в конце. Только после года: в "через 3 года" это единица срока.
+func normalizeDate(s string) string {
+	text := strings.Join(strings.Fields(strings.ToLower(s)), " ")
+	return yearSuffix.ReplaceAllString(text, "$1")
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -0,0 +1,177 @@
+package input
+
+import (
+	"testing"
+	"time"
+)
+
+// Понедельник, 19 октября 2026: относительные даты и даты без года считаются от него
+var testNow = time.Date(2026, time.October, 19, 15, 30, 0, 0, time.Local)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestDate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		// ISO и числовые форматы
+		{"2024-12-31", date(2024, time.December, 31)},
+		{"2024-1-5", date(2024, time.January, 5)},
+		{"31.12.2024", date(2024, time.December, 31)},
+		{"31.12.24", date(2024, time.December, 31)},
+		{"31/12/2024", date(2024, time.December, 31)},
+		{"31-12-2024", date(2024, time.December, 31)},
+		{"5.1.2025", date(2025, time.January, 5)},
+		{"31.12.2024 г.", date(2024, time.December, 31)},
+		{"31.12.24г", date(2024, time.December, 31)},
+		{"29.02.2028", date(2028, time.February, 29)},
+
+		// Без года - ближайшая дата начиная с сегодняшней
+		{"31.12", date(2026, time.December, 31)},
+		{"19.10", date(2026, time.October, 19)},
+		{"01.10", date(2027, time.October, 1)},
+		{"29.02", date(2028, time.February, 29)},
+
+		// Месяц словом
+		{"31 декабря", date(2026, time.December, 31)},
+		{"31 декабря 2025", date(2025, time.December, 31)},
+		{"31 декабря 2025 года", date(2025, time.December, 31)},
+		{"31 декабря 2025 г.", date(2025, time.December, 31)},
+		{"31 дек 2024", date(2024, time.December, 31)},
+		{"1 мая", date(2027, time.May, 1)},
+		{"15-го ноября", date(2026, time.November, 15)},
+		{"December 31", date(2026, time.December, 31)},
+		{"Dec. 31st, 2024", date(2024, time.December, 31)},
+		{"31 dec 2024", date(2024, time.December, 31)},
+		{"31st of December", date(2026, time.December, 31)},
+
+		// Слова
+		{"сегодня", date(2026, time.October, 19)},
+		{"завтра", date(2026, time.October, 20)},
+		{"  ЗАВТРА ", date(2026, time.October, 20)},
+		{"послезавтра", date(2026, time.October, 21)},
+		{"today", date(2026, time.October, 19)},
+		{"tomorrow", date(2026, time.October, 20)},
+		{"day after tomorrow", date(2026, time.October, 21)},
+
+		// Через сколько-то
+		{"через 2 недели", date(2026, time.November, 2)},
+		{"через две недели", date(2026, time.November, 2)},
+		{"через пару дней", date(2026, time.October, 21)},
+		{"через неделю", date(2026, time.October, 26)},
+		{"через месяц", date(2026, time.November, 19)},
+		{"через 3 месяца", date(2027, time.January, 19)},
+		{"через год", date(2027, time.October, 19)},
+		{"через 3 года", date(2029, time.October, 19)},
+		{"через 5 лет", date(2031, time.October, 19)},
+		{"in 3 days", date(2026, time.October, 22)},
+		{"in a week", date(2026, time.October, 26)},
+		{"in two months", date(2026, time.December, 19)},
+
+		// Ежемесячный день - ближайший такой день
+		{"каждое 15 число", date(2026, time.November, 15)},
+		{"каждое 19 число", date(2026, time.October, 19)},
+		{"каждое 25 число", date(2026, time.October, 25)},
+		{"15 числа", date(2026, time.November, 15)},
+		{"15-го", date(2026, time.November, 15)},
+		{"15-го каждого месяца", date(2026, time.November, 15)},
+		{"every 15th", date(2026, time.November, 15)},
+		{"on the 15th", date(2026, time.November, 15)},
+		{"каждое 31 число", date(2026, time.October, 31)},
+	}
+	for _, tt := range tests {
+		got, err := Date(tt.in, testNow)
+		if err != nil {
+			t.Errorf("Date(%q): unexpected error %v", tt.in, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("Date(%q) = %s, want %s", tt.in, got.Format(time.DateOnly), tt.want.Format(time.DateOnly))
+		}
+	}
+}
+
+func TestDateInvalid(t *testing.T) {
+	tests := []string{
+		"",
+		"   ",
+		"абв",
+		"вчера",
+		"30.02",
+		"30.02.2026",
+		"29.02.2027",
+		"31.04.2026",
+		"32.01.2026",
+		"00.01.2026",
+		"15.13.2026",
+		"15.00.2026",
+		"2026-02-30",
+		"2026-13-01",
+		"31.12.202",
+		"31 февраля 2026",
+		"31 абвгд",
+		"через 0 дней",
+		"через 1001 день",
+		"через несколько дней",
+		"через 2 попугая",
+		"каждое 32 число",
+		"каждое 0 число",
+		"15",
+		"150000",
+	}
+	for _, in := range tests {
+		if got, err := Date(in, testNow); err == nil {
+			t.Errorf("Date(%q) = %s, want error", in, got.Format(time.DateOnly))
+		}
+	}
+}
+
+func TestMonthlyDay(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   int
+		wantOK bool
+	}{
+		{"каждое 15 число", 15, true},
+		{"каждого 1 числа", 1, true},
+		{"15 числа", 15, true},
+		{"15-го", 15, true},
+		{"15-е", 15, true},
+		{"every 15th", 15, true},
+		{"15th of every month", 15, true},
+		{"каждое 31 число", 31, true},
+		{"15", 0, false}, // Голое число - не день месяца
+		{"каждое 32 число", 0, false},
+		{"каждое 0 число", 0, false},
+		{"31.12", 0, false},
+		{"завтра", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := MonthlyDay(tt.in)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("MonthlyDay(%q) = %d, %v, want %d, %v", tt.in, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestNextMonthlyDateShortMonths(t *testing.T) {
+	tests := []struct {
+		today time.Time
+		day   int
+		want  time.Time
+	}{
+		{date(2026, time.February, 10), 31, date(2026, time.February, 28)},
+		{date(2028, time.February, 10), 30, date(2028, time.February, 29)},
+		{date(2026, time.April, 1), 31, date(2026, time.April, 30)},
+		{date(2026, time.December, 20), 15, date(2027, time.January, 15)},
+	}
+	for _, tt := range tests {
+		if got := nextMonthlyDate(tt.today, tt.day); !got.Equal(tt.want) {
+			t.Errorf("nextMonthlyDate(%s, %d) = %s, want %s", tt.today.Format(time.DateOnly), tt.day,
+				got.Format(time.DateOnly), tt.want.Format(time.DateOnly))
+		}
+	}
+}
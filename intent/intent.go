@@ -0,0 +1,144 @@
+// Package intent понимает сообщения, написанные своими словами вне диалогов:
+// "заплатил по тинькофф 5000", "сколько я должен?", "покажи кредиты".
+//
+// Parser - точка расширения: сейчас используется набор правил (Rules), но на его место
+// можно подставить LLM или локальную модель, не меняя бота.
+package intent
+
+import (
+	"regexp"
+	"strings"
+
+	"DebtBot/input"
+)
+
+// Kind - действие, которое хочет выполнить пользователь
+type Kind string
+
+const (
+	Unknown     Kind = ""
+	Help        Kind = "help"
+	ListCredits Kind = "list_credits"
+	Summary     Kind = "summary" // Сколько я должен, долговая нагрузка
+	Debts       Kind = "debts"   // Долги между друзьями
+	AddCredit   Kind = "add_credit"
+	Payment     Kind = "payment"
+)
+
+// Intent - распознанное намерение с извлеченными параметрами
+type Intent struct {
+	Kind     Kind
+	BankName string  // Банк, как его написал пользователь ("тинькофф", "сбер")
+	Amount   float64 // Сумма платежа; 0 - не указана
+	Args     string  // Для AddCredit - описание кредита в синтаксисе /addcredit
+}
+
+// Parser распознает намерение в тексте сообщения
+type Parser interface {
+	Parse(text string) (Intent, error)
+}
+
+// Rules - распознавание по ключевым словам на русском и английском
+type Rules struct{}
+
+// NewRules создает парсер на правилах
+func NewRules() *Rules {
+	return &Rules{}
+}
+
+var (
+	addCreditPattern = regexp.MustCompile(`^(?:я\s+)?(?:взял|взяла|оформил|оформила|добавь|добавить|новый|add|new|took)\s+(?:a\s+)?(?:кредит|займ|ипотеку|loan|credit)\s*(?:в\s+|на\s+|in\s+|at\s+|from\s+)?(.*)$`)
+	paymentPattern   = regexp.MustCompile(`^(?:я\s+)?(?:заплатил|заплатила|оплатил|оплатила|внес|внесла|погасил|погасила|перевел|перевела|paid|pay)(?:\s+(.*))?$`)
+	punctuation      = strings.NewReplacer("?", " ", "!", " ", ";", " ", "…", " ")
+)
+
+// Предлоги и служебные слова, которые не входят в название банка
+var paymentStopWords = map[string]bool{
+	"по": true, "в": true, "за": true, "на": true, "кредит": true, "кредиту": true, "платеж": true,
+	"руб": true, "рублей": true, "р": true, "₽": true,
+	"to": true, "for": true, "the": true, "my": true, "loan": true, "credit": true, "at": true,
+}
+
+// Фразы без параметров: первая совпавшая побеждает, поэтому более точные идут раньше
+var phrases = []struct {
+	kind    Kind
+	phrases []string
+}{
+	{Debts, []string{"кто мне должен", "кому я должен", "долги друзей", "мои долги", "who owes me", "debts"}},
+	{Summary, []string{"сколько я должен", "сколько должен", "сколько осталось", "общий долг", "нагрузка", "how much do i owe", "how much i owe", "summary"}},
+	{ListCredits, []string{"мои кредиты", "покажи кредиты", "список кредитов", "какие кредиты", "my loans", "show loans", "list loans"}},
+	{Help, []string{"помощь", "помоги", "что ты умеешь", "help", "what can you do"}},
+}
+
+// Parse реализует Parser
+func (r *Rules) Parse(text string) (Intent, error) {
+	normalized := strings.Join(strings.Fields(strings.ToLower(punctuation.Replace(text))), " ")
+	normalized = strings.TrimRight(normalized, ".")
+	if normalized == "" {
+		return Intent{}, nil
+	}
+
+	// Для /addcredit важен исходный регистр названия банка, поэтому аргументы берем из текста
+	if m := addCreditPattern.FindStringSubmatchIndex(normalized); m != nil {
+		return Intent{Kind: AddCredit, Args: originalTail(text, normalized, m[2])}, nil
+	}
+	if m := paymentPattern.FindStringSubmatch(normalized); m != nil {
+		return parsePayment(m[1]), nil
+	}
+
+	for _, p := range phrases {
+		for _, phrase := range p.phrases {
+			if strings.Contains(normalized, phrase) {
+				return Intent{Kind: p.kind}, nil
+			}
+		}
+	}
+	return Intent{}, nil
+}
+
+// parsePayment выделяет из "по тинькофф 5000" банк и сумму
+func parsePayment(rest string) Intent {
+	intent := Intent{Kind: Payment}
+	var bank []string
+	words := strings.Fields(rest)
+	for i := 0; i < len(words); i++ {
+		// Сумма может занимать несколько слов: "1 000", "5 тыс"
+		if startsWithDigit(words[i]) && intent.Amount == 0 {
+			j := i + 1
+			for j < len(words) && startsWithDigit(words[j]) {
+				j++
+			}
+			if j < len(words) {
+				if _, err := input.Amount(strings.Join(words[i:j+1], " ")); err == nil {
+					j++
+				}
+			}
+			if amount, err := input.Amount(strings.Join(words[i:j], " ")); err == nil && amount > 0 {
+				intent.Amount = amount
+				i = j - 1
+				continue
+			}
+		}
+		if paymentStopWords[words[i]] {
+			continue
+		}
+		bank = append(bank, words[i])
+	}
+	intent.BankName = strings.Join(bank, " ")
+	return intent
+}
+
+func startsWithDigit(word string) bool {
+	return word != "" && word[0] >= '0' && word[0] <= '9'
+}
+
+// originalTail возвращает окончание исходного текста, соответствующее окончанию нормализованного,
+// начиная с байта start. Если нормализация изменила длину, возвращается нормализованный вариант.
+func originalTail(original, normalized string, start int) string {
+	tail := normalized[start:]
+	trimmed := strings.TrimSpace(original)
+	if len(trimmed) >= len(tail) && strings.EqualFold(trimmed[len(trimmed)-len(tail):], tail) {
+		return trimmed[len(trimmed)-len(tail):]
+	}
+	return tail
+}
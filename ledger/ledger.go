@@ -0,0 +1,120 @@
+package ledger
+
+import (
+	"math"
+	"sort"
+
+	"DebtBot/models"
+)
+
+// Transfer - один перевод, который нужно сделать для взаиморасчета в группе
+type Transfer struct {
+	From   int64   // Кто платит
+	To     int64   // Кому платит
+	Amount float64 // Сколько
+}
+
+// Все расчеты ведутся в копейках, чтобы не накапливать ошибки округления float64
+func toKopecks(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+func fromKopecks(kopecks int64) float64 {
+	return float64(kopecks) / 100
+}
+
+// SplitEqually делит сумму на n равных долей с точностью до копейки.
+// Остаток от деления раздается по одной копейке первым участникам,
+// так что сумма долей всегда в точности равна исходной сумме.
+func SplitEqually(amount float64, n int) []float64 {
+	if n <= 0 {
+		return nil
+	}
+	total := toKopecks(amount)
+	base := total / int64(n)
+	rest := total % int64(n)
+
+	shares := make([]float64, n)
+	for i := range shares {
+		share := base
+		if int64(i) < rest {
+			share++
+		}
+		shares[i] = fromKopecks(share)
+	}
+	return shares
+}
+
+// Balances считает баланс каждого участника группы:
+// положительный - группа должна участнику, отрицательный - участник должен группе.
+func Balances(expenses []*models.GroupExpense, shares []*models.GroupExpenseShare) map[int64]float64 {
+	kopecks := make(map[int64]int64)
+	for _, expense := range expenses {
+		kopecks[expense.PayerID] += toKopecks(expense.Amount)
+	}
+	for _, share := range shares {
+		kopecks[share.UserID] -= toKopecks(share.Amount)
+	}
+
+	balances := make(map[int64]float64, len(kopecks))
+	for userID, value := range kopecks {
+		if value != 0 {
+			balances[userID] = fromKopecks(value)
+		}
+	}
+	return balances
+}
+
+type party struct {
+	userID  int64
+	kopecks int64
+}
+
+// Simplify строит короткий список переводов, закрывающий все балансы.
+// На каждом шаге самый крупный должник платит самому крупному кредитору,
+// поэтому переводов получается не больше, чем участников минус один.
+func Simplify(balances map[int64]float64) []Transfer {
+	var debtors, creditors []*party
+	for userID, balance := range balances {
+		value := toKopecks(balance)
+		switch {
+		case value < 0:
+			debtors = append(debtors, &party{userID: userID, kopecks: -value})
+		case value > 0:
+			creditors = append(creditors, &party{userID: userID, kopecks: value})
+		}
+	}
+
+	transfers := []Transfer{}
+	for len(debtors) > 0 && len(creditors) > 0 {
+		sortParties(debtors)
+		sortParties(creditors)
+
+		debtor, creditor := debtors[0], creditors[0]
+		amount := debtor.kopecks
+		if creditor.kopecks < amount {
+			amount = creditor.kopecks
+		}
+		transfers = append(transfers, Transfer{From: debtor.userID, To: creditor.userID, Amount: fromKopecks(amount)})
+
+		debtor.kopecks -= amount
+		creditor.kopecks -= amount
+		if debtor.kopecks == 0 {
+			debtors = debtors[1:]
+		}
+		if creditor.kopecks == 0 {
+			creditors = creditors[1:]
+		}
+	}
+	return transfers
+}
+
+// Сортировка по убыванию суммы; при равенстве - по ID, чтобы результат был стабильным
+func sortParties(parties []*party) {
+	sort.Slice(parties, func(i, j int) bool {
+		if parties[i].kopecks != parties[j].kopecks {
+			return parties[i].kopecks > parties[j].kopecks
+		}
+		return parties[i].userID < parties[j].userID
+	})
+}
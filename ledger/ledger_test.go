@@ -0,0 +1,152 @@
+package ledger
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestSplitEqually(t *testing.T) {
+	tests := []struct {
+		amount float64
+		n      int
+		want   []float64
+	}{
+		{300, 3, []float64{100, 100, 100}},
+		{100, 3, []float64{33.34, 33.33, 33.33}},
+		{100.01, 3, []float64{33.34, 33.34, 33.33}},
+		{0.05, 7, []float64{0.01, 0.01, 0.01, 0.01, 0.01, 0, 0}}, // Копеек меньше, чем участников
+		{1500, 1, []float64{1500}},
+		{0, 2, []float64{0, 0}},
+		{100, 0, nil},
+		{100, -1, nil},
+	}
+	for _, tt := range tests {
+		if got := SplitEqually(tt.amount, tt.n); !slices.Equal(got, tt.want) {
+			t.Errorf("SplitEqually(%v, %d) = %v, want %v", tt.amount, tt.n, got, tt.want)
+		}
+	}
+}
+
+// Сумма долей в копейках всегда в точности равна исходной сумме
+func TestSplitEquallySumsExactly(t *testing.T) {
+	for _, amount := range []float64{0.01, 0.1, 0.3, 99.99, 100, 1234.57, 999999.99} {
+		for n := 1; n <= 13; n++ {
+			shares := SplitEqually(amount, n)
+			var total, maxShare, minShare int64
+			for i, share := range shares {
+				kopecks := toKopecks(share)
+				total += kopecks
+				if i == 0 || kopecks > maxShare {
+					maxShare = kopecks
+				}
+				if i == 0 || kopecks < minShare {
+					minShare = kopecks
+				}
+			}
+			if total != toKopecks(amount) {
+				t.Errorf("SplitEqually(%v, %d): shares sum to %d kopecks, want %d", amount, n, total, toKopecks(amount))
+			}
+			if maxShare-minShare > 1 {
+				t.Errorf("SplitEqually(%v, %d) = %v: shares differ by more than a kopeck", amount, n, shares)
+			}
+		}
+	}
+}
+
+func TestSimplify(t *testing.T) {
+	tests := []struct {
+		name     string
+		balances map[int64]float64
+		want     []Transfer
+	}{
+		{"пустая группа", nil, []Transfer{}},
+		{"все в расчете", map[int64]float64{1: 0, 2: 0}, []Transfer{}},
+		{"один должник", map[int64]float64{1: 200, 2: -200}, []Transfer{{From: 2, To: 1, Amount: 200}}},
+		{
+			"нулевой баланс пропускается",
+			map[int64]float64{1: 150.5, 2: 0, 3: -150.5},
+			[]Transfer{{From: 3, To: 1, Amount: 150.5}},
+		},
+		{
+			"два должника, один кредитор",
+			map[int64]float64{1: 1000, 2: -600, 3: -400},
+			[]Transfer{{From: 2, To: 1, Amount: 600}, {From: 3, To: 1, Amount: 400}},
+		},
+		{
+			"один должник, два кредитора",
+			map[int64]float64{1: 300, 2: 700, 3: -1000},
+			[]Transfer{{From: 3, To: 2, Amount: 700}, {From: 3, To: 1, Amount: 300}},
+		},
+		{
+			"равные суммы - по возрастанию ID",
+			map[int64]float64{5: 100, 4: 100, 2: -100, 1: -100},
+			[]Transfer{{From: 1, To: 4, Amount: 100}, {From: 2, To: 5, Amount: 100}},
+		},
+		{
+			"копейки",
+			map[int64]float64{1: 66.67, 2: -33.33, 3: -33.34},
+			[]Transfer{{From: 3, To: 1, Amount: 33.34}, {From: 2, To: 1, Amount: 33.33}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Simplify(tt.balances); !slices.Equal(got, tt.want) {
+				t.Errorf("Simplify = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Для n должников и m кредиторов переводов не больше n+m-1, и после них все балансы нулевые
+func TestSimplifyClosesBalances(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for n := 1; n <= 8; n++ {
+		for m := 1; m <= 8; m++ {
+			for round := 0; round < 20; round++ {
+				balances := make(map[int64]float64)
+				var debt int64
+				for i := 0; i < n; i++ {
+					kopecks := random.Int63n(1000000) + 1
+					balances[int64(i+1)] = -fromKopecks(kopecks)
+					debt += kopecks
+				}
+				// Долг раздается кредиторам так, чтобы у каждого осталось больше нуля
+				if debt < int64(m) {
+					balances[1] -= fromKopecks(int64(m) - debt)
+					debt = int64(m)
+				}
+				rest := debt
+				for j := 0; j < m; j++ {
+					kopecks := rest - int64(m-j-1)
+					if j < m-1 {
+						kopecks = random.Int63n(rest-int64(m-j-1)) + 1
+					}
+					balances[int64(100+j)] = fromKopecks(kopecks)
+					rest -= kopecks
+				}
+
+				transfers := Simplify(balances)
+				if len(transfers) > n+m-1 {
+					t.Errorf("n=%d m=%d: %d transfers, want at most %d", n, m, len(transfers), n+m-1)
+				}
+				left := make(map[int64]int64, len(balances))
+				for userID, balance := range balances {
+					left[userID] = toKopecks(balance)
+				}
+				for _, transfer := range transfers {
+					if transfer.Amount <= 0 || balances[transfer.From] >= 0 || balances[transfer.To] <= 0 {
+						t.Fatalf("n=%d m=%d: unexpected transfer %+v", n, m, transfer)
+					}
+					left[transfer.From] += toKopecks(transfer.Amount)
+					left[transfer.To] -= toKopecks(transfer.Amount)
+				}
+				for userID, kopecks := range left {
+					if kopecks != 0 {
+						t.Fatalf("n=%d m=%d: user %d left with %d kopecks", n, m, userID, kopecks)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,198 @@
+// Package logging - структурированный журнал на log/slog.
+//
+// У каждого пакета свой логгер (For("bot"), For("db")) со своим уровнем: уровень по умолчанию
+// задает log_level, отдельные пакеты переопределяются в log_levels. Пока уровень пакета выше
+// debug, значения с персональными данными (текст сообщений, суммы, названия банков, имена)
+// заменяются на "[redacted]" - их ключи перечислены в sensitiveKeys.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// Форматы журнала
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Ключи атрибутов, значения которых скрываются вне режима debug. Текст ошибок не скрывается:
+// в сообщениях об ошибках не должно быть пользовательских данных.
+var sensitiveKeys = map[string]bool{
+	"text":     true, // Текст сообщения пользователя
+	"args":     true, // Аргументы команды
+	"amount":   true,
+	"bank":     true,
+	"name":     true,
+	"username": true,
+	"title":    true, // Название группового чата
+	"file":     true, // Имя присланного файла
+}
+
+const redacted = "[redacted]"
+
+// Options - настройки журнала
+type Options struct {
+	Format string            // FormatText или FormatJSON
+	Level  string            // Уровень по умолчанию: debug, info, warn, error
+	Levels map[string]string // Уровни отдельных пакетов: {"db": "warn"}
+}
+
+type state struct {
+	handler slog.Handler
+	level   slog.Level
+	levels  map[string]slog.Level
+}
+
+// Пока Setup не вызван, журнал пишется текстом в stderr с уровнем info
+var current atomic.Pointer[state]
+
+func init() {
+	current.Store(&state{
+		handler: slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}),
+		level:   slog.LevelInfo,
+	})
+}
+
+// ParseLevel разбирает уровень журнала: debug, info, warn, error
+func ParseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("%q - допустимы debug, info, warn, error", s)
+}
+
+// Setup настраивает журнал всех пакетов, в том числе уже созданных логгеров, и направляет
+// в него стандартный log (сообщения библиотек пишутся с уровнем info)
+func Setup(w io.Writer, opts Options) error {
+	level, err := ParseLevel(opts.Level)
+	if err != nil {
+		return err
+	}
+	levels := make(map[string]slog.Level, len(opts.Levels))
+	for pkg, s := range opts.Levels {
+		if levels[pkg], err = ParseLevel(s); err != nil {
+			return fmt.Errorf("%s: %w", pkg, err)
+		}
+	}
+
+	// Уровни проверяет handler пакета, поэтому общий обработчик пропускает все записи
+	handlerOpts := &slog.HandlerOptions{Level: slog.LevelDebug}
+	var handler slog.Handler
+	switch opts.Format {
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, handlerOpts)
+	case FormatText, "":
+		handler = slog.NewTextHandler(w, handlerOpts)
+	default:
+		return fmt.Errorf("неизвестный формат журнала %q", opts.Format)
+	}
+
+	current.Store(&state{handler: handler, level: level, levels: levels})
+	slog.SetDefault(For("main"))
+	return nil
+}
+
+// For возвращает логгер пакета. Его можно создать до Setup: настройки применяются при записи.
+func For(pkg string) *slog.Logger {
+	return slog.New(&handler{pkg: pkg})
+}
+
+// handler применяет уровень пакета и скрывает персональные данные, затем передает запись
+// общему обработчику из Setup
+type handler struct {
+	pkg string
+	ops []op // WithAttrs и WithGroup в порядке вызова
+}
+
+type op struct {
+	group string
+	attrs []slog.Attr
+}
+
+func (h *handler) level(s *state) slog.Level {
+	if level, ok := s.levels[h.pkg]; ok {
+		return level
+	}
+	return s.level
+}
+
+func (h *handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level(current.Load())
+}
+
+func (h *handler) Handle(ctx context.Context, r slog.Record) error {
+	s := current.Load()
+	redact := h.level(s) > slog.LevelDebug
+
+	out := s.handler.WithAttrs([]slog.Attr{slog.String("pkg", h.pkg)})
+	for _, o := range h.ops {
+		if o.group != "" {
+			out = out.WithGroup(o.group)
+			continue
+		}
+		out = out.WithAttrs(redactAttrs(o.attrs, redact))
+	}
+
+	if redact {
+		clean := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+		r.Attrs(func(a slog.Attr) bool {
+			clean.AddAttrs(redactAttr(a))
+			return true
+		})
+		r = clean
+	}
+	return out.Handle(ctx, r)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(op{attrs: attrs})
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.with(op{group: name})
+}
+
+func (h *handler) with(o op) *handler {
+	ops := make([]op, len(h.ops), len(h.ops)+1)
+	copy(ops, h.ops)
+	return &handler{pkg: h.pkg, ops: append(ops, o)}
+}
+
+func redactAttrs(attrs []slog.Attr, redact bool) []slog.Attr {
+	if !redact {
+		return attrs
+	}
+	clean := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		clean[i] = redactAttr(a)
+	}
+	return clean
+}
+
+func redactAttr(a slog.Attr) slog.Attr {
+	value := a.Value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(redactAttrs(value.Group(), true)...)}
+	}
+	if sensitiveKeys[a.Key] {
+		return slog.String(a.Key, redacted)
+	}
+	return a
+}
@@ -1,47 +1,257 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
+	"os"
+	"path/filepath"
 	"time"
+	_ "time/tzdata" // Часовые пояса пользователей не зависят от tzdata на сервере
 
+	"DebtBot/backup"
 	"DebtBot/bot"
 	"DebtBot/config"
 	"DebtBot/db"
+	"DebtBot/export"
+	"DebtBot/logging"
+	"DebtBot/metrics"
+	"DebtBot/scheduler"
+	"DebtBot/web"
 )
 
+var logger = logging.For("main")
+
 func main() {
-	cfg := config.LoadConfig()
+	cfg, args, err := config.Load(os.Args[1:])
+	if err != nil {
+		log.Fatalf("Invalid configuration:\n%v", err)
+	}
+	if err := logging.Setup(os.Stderr, cfg.Logging()); err != nil {
+		log.Fatalf("Invalid logging configuration: %v", err)
+	}
+	if cfg.Timezone != "" {
+		time.Local = cfg.Location()
+	}
+
+	// Восстановление - до открытия базы: файл базы будет заменен
+	// DebtBot [флаги настроек] restore [-file <копия> | -at <время>] [-check]
+	if len(args) > 0 && args[0] == "restore" {
+		if err := runRestore(cfg, args[1:]); err != nil {
+			fatal("Restore failed", err)
+		}
+		return
+	}
+
 	database := db.NewDB(cfg)
 	defer database.Close()
 
-	err := database.InitSchema()
+	err = database.InitSchema()
 	if err != nil {
-		log.Fatalf("Error initializing database schema: %v", err)
+		fatal("Error initializing database schema", err)
 	}
 
-	debtBot, err := bot.NewBot(cfg, database)
+	// Подкоманды CLI: DebtBot [флаги настроек] export -user <id> -dir <папка>
+	if len(args) > 0 && args[0] == "export" {
+		if err := runExport(database, args[1:]); err != nil {
+			fatal("Export failed", err)
+		}
+		return
+	}
+	// DebtBot [флаги настроек] rotate-keys -batch <строк>
+	if len(args) > 0 && args[0] == "rotate-keys" {
+		if err := runRotateKeys(database, args[1:]); err != nil {
+			fatal("Key rotation failed", err)
+		}
+		return
+	}
+
+	backupOptions, err := backup.FromConfig(cfg)
 	if err != nil {
-		log.Fatalf("Error creating bot: %v", err)
+		fatal("Invalid backup configuration", err)
+	}
+	backups := backup.New(database, backupOptions)
+	// DebtBot [флаги настроек] backup
+	if len(args) > 0 && args[0] == "backup" {
+		path, err := backups.Create()
+		if err != nil {
+			fatal("Backup failed", err)
+		}
+		fmt.Println(path)
+		return
 	}
 
-	// Запуск горутины для отправки уведомлений каждый день в 9 утра
-	go func() {
-		for {
-			now := time.Now()
-			nextNotificationTime := time.Date(now.Year(), now.Month(), now.Day(), 9, 0, 0, 0, time.Local)
-			if nextNotificationTime.Before(now) {
-				nextNotificationTime = nextNotificationTime.Add(24 * time.Hour) // Если 9 утра уже прошло, то на завтра
-			}
-			waitDuration := nextNotificationTime.Sub(now)
-			time.Sleep(waitDuration)
+	// Периодические задачи регистрируются ниже, бот показывает их состояние в /jobs
+	jobs := scheduler.New(time.Local)
 
-			log.Println("Sending daily notifications...")
-			debtBot.SendNotifications()
+	debtBot, err := bot.NewBot(cfg, database, jobs, backups)
+	if err != nil {
+		fatal("Error creating bot", err)
+	}
+
+	// HTTP-сервер для подписки на календарь платежей и вебхука Telegram
+	if cfg.HTTPAddr != "" {
+		server := web.NewServer(cfg.HTTPAddr, database)
+		if cfg.Mode == config.ModeWebhook {
+			server.Handle("POST "+cfg.WebhookPath(), debtBot.WebhookHandler())
 		}
-	}()
+		go func() {
+			if err := server.Start(); err != nil {
+				fatal("Error starting HTTP server", err)
+			}
+		}()
+	}
+
+	// Внутренний сервер метрик и проверок состояния: не должен быть доступен снаружи
+	if cfg.MetricsAddr != "" {
+		metricsServer := metrics.NewServer(cfg.MetricsAddr, database.Ping, cfg.Mode == config.ModePolling)
+		go func() {
+			if err := metricsServer.Start(); err != nil {
+				fatal("Error starting metrics server", err)
+			}
+		}()
+	}
 
-	log.Println("Bot started. Listening for updates...")
+	// Время задач считается в часовом поясе сервера (timezone в настройках)
+	notificationHour, notificationMinute := cfg.NotificationClock()
+	jobs.Daily("notifications", notificationHour, notificationMinute, debtBot.SendNotifications)
+	// Отложенные кнопками "Через 2 часа" / "Завтра" напоминания
+	jobs.Every("snoozes", time.Minute, debtBot.SendSnoozedReminders)
+	// Групповые напоминания о взаиморасчетах - раз в неделю, по понедельникам во время напоминаний
+	jobs.Weekly("group_reminders", time.Monday, notificationHour, notificationMinute, debtBot.SendGroupReminders)
+	// Выписка за прошедший месяц - 1-го числа во время напоминаний
+	jobs.Monthly("monthly_statements", 1, notificationHour, notificationMinute, debtBot.SendMonthlyStatements)
+	// Сводки проверяются каждый час: у каждого пользователя свой часовой пояс
+	jobs.Every("digests", time.Hour, debtBot.SendDigests)
+	// Окончательное удаление кредитов, давно лежащих в корзине
+	jobs.Daily("trash_purge", 4, 0, debtBot.PurgeTrash)
+	// Резервные копии базы - если задан backup_interval
+	if interval := cfg.BackupEvery(); interval > 0 {
+		jobs.Every("backup", interval, backups.Run)
+	}
+	jobs.Start()
+
+	logger.Info("Bot started. Listening for updates", "mode", cfg.Mode)
 	if err := debtBot.Start(); err != nil {
-		log.Fatalf("Error starting bot: %v", err)
+		fatal("Error starting bot", err)
+	}
+}
+
+// fatal записывает ошибку в журнал и завершает программу
+func fatal(msg string, err error) {
+	logger.Error(msg, "err", err)
+	os.Exit(1)
+}
+
+// runExport сохраняет выгрузку пользователя в XLSX и CSV-файлы в указанную папку
+func runExport(database *db.DB, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	userID := flags.Int64("user", 0, "Telegram ID пользователя")
+	dir := flags.String("dir", ".", "папка для файлов выгрузки")
+	flags.Parse(args)
+
+	if *userID == 0 {
+		return fmt.Errorf("-user is required")
+	}
+
+	data, err := export.Load(database, *userID)
+	if err != nil {
+		return err
+	}
+	tables := export.Tables(data)
+
+	workbook, err := export.XLSX(tables)
+	if err != nil {
+		return err
+	}
+	files := map[string][]byte{"debtbot.xlsx": workbook}
+	for _, table := range tables {
+		content, err := export.CSV(table)
+		if err != nil {
+			return err
+		}
+		files[table.Name+".csv"] = content
+	}
+
+	for name, content := range files {
+		path := filepath.Join(*dir, name)
+		if err := os.WriteFile(path, content, 0o600); err != nil {
+			return err
+		}
+		logger.Info("Saved", "path", path)
+	}
+	return nil
+}
+
+// runRotateKeys перешифровывает данные в базе текущим ключом шифрования (первым в настройках)
+func runRotateKeys(database *db.DB, args []string) error {
+	flags := flag.NewFlagSet("rotate-keys", flag.ExitOnError)
+	batch := flags.Int("batch", 500, "строк в одной транзакции")
+	flags.Parse(args)
+
+	if *batch <= 0 {
+		return fmt.Errorf("-batch must be positive")
+	}
+	rows, err := database.RotateKeys(*batch)
+	if err != nil {
+		return err
+	}
+	logger.Info("Key rotation finished", "rows", rows)
+	return nil
+}
+
+// runRestore заменяет файл базы резервной копией. Бот на это время должен быть остановлен.
+// Без -file и -at выводит список копий; с -check только проверяет копию.
+func runRestore(cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("restore", flag.ExitOnError)
+	file := flags.String("file", "", "файл резервной копии")
+	at := flags.String("at", "", "восстановить последнюю копию не позже этого времени, ГГГГ-ММ-ДД ЧЧ:ММ")
+	check := flags.Bool("check", false, "только проверить целостность копии")
+	flags.Parse(args)
+
+	path := *file
+	switch {
+	case path != "":
+	case *at != "":
+		moment, err := time.ParseInLocation("2006-01-02 15:04", *at, time.Local)
+		if err != nil {
+			return fmt.Errorf("-at: %w", err)
+		}
+		snapshot, err := backup.At(cfg.BackupDir, moment)
+		if err != nil {
+			return err
+		}
+		path = snapshot.Path
+	default:
+		snapshots, err := backup.List(cfg.BackupDir)
+		if err != nil {
+			return err
+		}
+		for _, snapshot := range snapshots {
+			fmt.Printf("%s  %s\n", snapshot.CreatedAt.Format("2006-01-02 15:04:05"), snapshot.Path)
+		}
+		return nil
+	}
+
+	// Копию могли зашифровать прежним ключом, поэтому нужны все ключи, а не только при backup_encrypt
+	keys, err := cfg.EncryptionKeys()
+	if err != nil {
+		return err
+	}
+	cipher, err := db.NewCipher(keys)
+	if err != nil {
+		return err
+	}
+	if *check {
+		if err := backup.Verify(path, cipher); err != nil {
+			return err
+		}
+		logger.Info("Backup is valid", "path", path)
+		return nil
+	}
+	if err := backup.Restore(path, db.FilePath(cfg.DBDSN), cipher); err != nil {
+		return err
 	}
+	logger.Info("Database restored", "path", path)
+	return nil
 }
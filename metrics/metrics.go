@@ -0,0 +1,105 @@
+// Package metrics - метрики Prometheus и состояние бота для проверок /healthz и /readyz
+package metrics
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Статусы напоминаний для ReminderEvent
+const (
+	ReminderScheduled = "scheduled" // Найдено к отправке или отложено кнопкой
+	ReminderSent      = "sent"
+	ReminderFailed    = "failed"
+)
+
+var (
+	updates = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "debtbot_updates_total",
+		Help: "Обновления от Telegram по типу: command, message, callback_query, group_message, other.",
+	}, []string{"type"})
+
+	commandDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "debtbot_command_duration_seconds",
+		Help:    "Время обработки команды бота.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"command"})
+
+	sendErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "debtbot_telegram_send_errors_total",
+		Help: "Ошибки отправки сообщений и запросов в Telegram.",
+	})
+
+	dbDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "debtbot_db_query_duration_seconds",
+		Help:    "Время запросов к базе по виду операции: get, select, exec.",
+		Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"operation"})
+
+	reminders = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "debtbot_reminders_total",
+		Help: "Напоминания о платежах: scheduled, sent, failed.",
+	}, []string{"status"})
+
+	activeDialogs = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "debtbot_active_dialogs",
+		Help: "Пользователи, находящиеся в середине диалога (добавление кредита, долга и т.п.).",
+	})
+
+	lastPollGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "debtbot_last_update_poll_timestamp_seconds",
+		Help: "Время последнего успешного получения обновлений от Telegram (Unix).",
+	})
+)
+
+// Время последнего успешного получения обновлений, Unix-наносекунды; 0 - еще не было
+var lastPoll atomic.Int64
+
+// UpdateReceived учитывает обновление от Telegram
+func UpdateReceived(kind string) {
+	updates.WithLabelValues(kind).Inc()
+}
+
+// ObserveCommand учитывает время обработки команды
+func ObserveCommand(command string, duration time.Duration) {
+	commandDuration.WithLabelValues(command).Observe(duration.Seconds())
+}
+
+// SendFailed учитывает ошибку запроса к Telegram
+func SendFailed() {
+	sendErrors.Inc()
+}
+
+// ObserveQuery учитывает время запроса к базе
+func ObserveQuery(operation string, started time.Time) {
+	dbDuration.WithLabelValues(operation).Observe(time.Since(started).Seconds())
+}
+
+// ReminderEvent учитывает напоминание со статусом ReminderScheduled, ReminderSent или ReminderFailed
+func ReminderEvent(status string) {
+	reminders.WithLabelValues(status).Inc()
+}
+
+// SetActiveDialogs обновляет число незавершенных диалогов
+func SetActiveDialogs(n int) {
+	activeDialogs.Set(float64(n))
+}
+
+// PollSucceeded отмечает успешное получение обновлений (в том числе пустой ответ long polling)
+func PollSucceeded() {
+	now := time.Now()
+	lastPoll.Store(now.UnixNano())
+	lastPollGauge.Set(float64(now.Unix()))
+}
+
+// LastPoll - время последнего успешного получения обновлений; нулевое, если его еще не было
+func LastPoll() time.Time {
+	nanos := lastPoll.Load()
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
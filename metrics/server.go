@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"DebtBot/logging"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var logger = logging.For("metrics")
+
+// Сколько может не быть успешного опроса Telegram. Long polling длится до минуты,
+// поэтому при исправной связи опрос проходит хотя бы раз в минуту.
+const (
+	readyPollAge = 2 * time.Minute  // Дольше - бот не получает сообщения, трафик на него не направляем
+	alivePollAge = 10 * time.Minute // Дольше - опрос, вероятно, завис, процесс пора перезапустить
+)
+
+// Server - внутренний HTTP-сервер: /metrics, /healthz, /readyz. Его не нужно открывать наружу.
+type Server struct {
+	pingDB  func() error
+	polling bool // В режиме webhook обновления могут не приходить часами, возраст опроса не проверяется
+	server  *http.Server
+}
+
+// NewServer создает внутренний сервер. pingDB проверяет соединение с базой.
+func NewServer(addr string, pingDB func() error, polling bool) *Server {
+	s := &Server{pingDB: pingDB, polling: polling}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", promhttp.Handler())
+	mux.HandleFunc("GET /healthz", s.handleCheck(alivePollAge, false))
+	mux.HandleFunc("GET /readyz", s.handleCheck(readyPollAge, true))
+
+	s.server = &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return s
+}
+
+// Start запускает сервер и блокируется до его остановки
+func (s *Server) Start() error {
+	logger.Info("Metrics server listening", "addr", s.server.Addr)
+	err := s.server.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// handleCheck проверяет базу и опрос Telegram. requirePoll - до первого успешного опроса
+// бот не готов (при старте это нормально, поэтому для /healthz не требуется).
+func (s *Server) handleCheck(maxPollAge time.Duration, requirePoll bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		checks := map[string]string{"db": "ok", "telegram": "ok"}
+		healthy := true
+
+		if err := s.pingDB(); err != nil {
+			checks["db"] = err.Error()
+			healthy = false
+		}
+
+		last := LastPoll()
+		switch {
+		case last.IsZero() && requirePoll:
+			checks["telegram"] = "no successful poll yet"
+			healthy = false
+		case !last.IsZero() && s.polling && time.Since(last) > maxPollAge:
+			checks["telegram"] = fmt.Sprintf("last successful poll %s ago", time.Since(last).Round(time.Second))
+			healthy = false
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(checks)
+	}
+}
@@ -3,8 +3,41 @@ package models
 import "time"
 
 type User struct {
-	ID        int64     `db:"id"` // Telegram User ID
-	CreatedAt time.Time `db:"created_at"`
+	ID            int64     `db:"id"` // Telegram User ID
+	CreatedAt     time.Time `db:"created_at"`
+	CalendarToken string    `db:"calendar_token"` // Секрет ссылки на подписку календаря платежей
+	MonthlyIncome float64   `db:"monthly_income"` // Среднемесячный доход для расчета ПДН; 0 - не указан
+
+	Timezone          string `db:"timezone"` // Часовой пояс IANA; пусто - часовой пояс сервера
+	WeeklyDigest      bool   `db:"weekly_digest"`
+	MonthlyDigest     bool   `db:"monthly_digest"`
+	LastWeeklyDigest  string `db:"last_weekly_digest"`  // Локальная дата последней недельной сводки, ГГГГ-ММ-ДД
+	LastMonthlyDigest string `db:"last_monthly_digest"` // Локальная дата последней месячной сводки, ГГГГ-ММ-ДД
+
+	Language         string `db:"language"`          // Язык, выбранный в /language; пусто - язык Telegram
+	TelegramLanguage string `db:"telegram_language"` // Последний LanguageCode из Telegram
+
+	Blocked    bool      `db:"blocked"`      // Пользователь заблокировал бота; уведомления не отправляются, пока он снова не напишет
+	Banned     bool      `db:"banned"`       // Доступ закрыт администратором (/ban)
+	LastSeenAt time.Time `db:"last_seen_at"` // Последнее сообщение или нажатие кнопки; нулевое - до появления учета
+}
+
+// Lang возвращает код языка интерфейса пользователя
+func (u *User) Lang() string {
+	if u.Language != "" {
+		return u.Language
+	}
+	return u.TelegramLanguage
+}
+
+// Location возвращает часовой пояс пользователя
+func (u *User) Location() *time.Location {
+	if u.Timezone != "" {
+		if location, err := time.LoadLocation(u.Timezone); err == nil {
+			return location
+		}
+	}
+	return time.Local
 }
 
 type Credit struct {
@@ -13,5 +46,206 @@ type Credit struct {
 	BankName   string    `db:"bank_name"`
 	LoanAmount float64   `db:"loan_amount"`
 	DueDate    time.Time `db:"due_date"`
+	Muted      bool      `db:"muted"` // Напоминания по кредиту отключены
 	CreatedAt  time.Time `db:"created_at"`
+
+	InterestRate float64 `db:"interest_rate"` // Ставка, % годовых; 0 - не указана
+	TermMonths   int     `db:"term_months"`   // Срок в месяцах; 0 - не указан
+	BankID       string  `db:"bank_id"`       // Банк из справочника banks; пусто - банка нет в справочнике
+
+	DeletedAt time.Time `db:"deleted_at"` // Время удаления в корзину; нулевое значение - кредит не удален
+}
+
+// Deleted проверяет, что кредит в корзине
+func (c *Credit) Deleted() bool {
+	return !c.DeletedAt.IsZero()
+}
+
+// Snooze - отложенное напоминание по кредиту ("напомнить позже")
+type Snooze struct {
+	ID        int       `db:"id"`
+	UserID    int64     `db:"user_id"`
+	CreditID  int       `db:"credit_id"`
+	RemindAt  time.Time `db:"remind_at"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// Payment - внесенный платеж по кредиту
+type Payment struct {
+	ID        int       `db:"id"`
+	UserID    int64     `db:"user_id"`
+	CreditID  int       `db:"credit_id"`
+	Amount    float64   `db:"amount"`
+	PaidAt    time.Time `db:"paid_at"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// Group - групповой чат, в который добавлен бот
+type Group struct {
+	ID        int64     `db:"id"` // Telegram Chat ID
+	Title     string    `db:"title"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// GroupMember - участник группового чата, известный боту
+type GroupMember struct {
+	GroupID   int64     `db:"group_id"`
+	UserID    int64     `db:"user_id"`
+	Username  string    `db:"username"`
+	FirstName string    `db:"first_name"`
+	JoinedAt  time.Time `db:"joined_at"`
+}
+
+// GroupExpense - общая трата (или возврат долга) в группе
+type GroupExpense struct {
+	ID          int       `db:"id"`
+	GroupID     int64     `db:"group_id"`
+	PayerID     int64     `db:"payer_id"`
+	Amount      float64   `db:"amount"`
+	Description string    `db:"description"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// GroupExpenseShare - доля участника в общей трате
+type GroupExpenseShare struct {
+	ExpenseID int     `db:"expense_id"`
+	UserID    int64   `db:"user_id"`
+	Amount    float64 `db:"amount"`
+}
+
+// Статусы личного долга между двумя пользователями
+const (
+	DebtPending         = "pending"          // Создан одной стороной, ждет подтверждения второй
+	DebtActive          = "active"           // Подтвержден обеими сторонами
+	DebtRejected        = "rejected"         // Вторая сторона отклонила долг
+	DebtSettleRequested = "settle_requested" // Одна из сторон отметила долг погашенным, ждем вторую
+	DebtSettled         = "settled"          // Погашение подтверждено обеими сторонами
+)
+
+// Debt - долг между двумя пользователями бота.
+// Пока вторая сторона не приняла приглашение, LenderID или BorrowerID равен 0.
+type Debt struct {
+	ID                int       `db:"id"`
+	Token             string    `db:"token"` // Секрет для deep link приглашения
+	CreatorID         int64     `db:"creator_id"`
+	LenderID          int64     `db:"lender_id"`   // Кто дал в долг
+	BorrowerID        int64     `db:"borrower_id"` // Кто должен
+	Amount            float64   `db:"amount"`
+	Description       string    `db:"description"`
+	DueDate           time.Time `db:"due_date"`
+	Status            string    `db:"status"`
+	SettleRequestedBy int64     `db:"settle_requested_by"`
+	CreatedAt         time.Time `db:"created_at"`
+}
+
+// Counterparty возвращает вторую сторону долга для userID
+func (d *Debt) Counterparty(userID int64) int64 {
+	if d.LenderID == userID {
+		return d.BorrowerID
+	}
+	return d.LenderID
+}
+
+// IsParty проверяет, является ли пользователь одной из сторон долга
+func (d *Debt) IsParty(userID int64) bool {
+	return userID != 0 && (d.LenderID == userID || d.BorrowerID == userID)
+}
+
+// DebtStatusChange - запись истории статусов долга
+type DebtStatusChange struct {
+	ID        int       `db:"id"`
+	DebtID    int       `db:"debt_id"`
+	Status    string    `db:"status"`
+	ChangedBy int64     `db:"changed_by"`
+	ChangedAt time.Time `db:"changed_at"`
+}
+
+// Статусы исходящего сообщения в очереди
+const (
+	OutboxPending = "pending" // Ждет отправки или повтора
+	OutboxSent    = "sent"
+	OutboxFailed  = "failed"  // Попытки исчерпаны или Telegram отклонил сообщение
+	OutboxBlocked = "blocked" // Пользователь заблокировал бота
+)
+
+// Виды исходящих уведомлений
+const (
+	OutboxReminder      = "reminder"       // Напоминание о платеже по кредиту
+	OutboxDebtReminder  = "debt_reminder"  // Напоминание о долге между друзьями
+	OutboxGroupReminder = "group_reminder" // Взаиморасчеты в групповом чате
+	OutboxDigest        = "digest"         // Недельная или месячная сводка
+	OutboxBroadcast     = "broadcast"      // Рассылка администратора
+)
+
+// OutboxMessage - уведомление в очереди отправки
+type OutboxMessage struct {
+	ID            int       `db:"id"`
+	ChatID        int64     `db:"chat_id"`
+	Kind          string    `db:"kind"`
+	CreditID      int       `db:"credit_id"` // Для напоминаний о платеже; 0 - не относится к кредиту
+	Text          string    `db:"text"`
+	ParseMode     string    `db:"parse_mode"`
+	ReplyMarkup   string    `db:"reply_markup"` // JSON inline-клавиатуры; пусто - без кнопок
+	Status        string    `db:"status"`
+	Attempts      int       `db:"attempts"`
+	NextAttemptAt time.Time `db:"next_attempt_at"`
+	LastError     string    `db:"last_error"`
+	MessageID     int       `db:"message_id"` // ID доставленного сообщения в Telegram
+	CreatedAt     time.Time `db:"created_at"`
+	SentAt        time.Time `db:"sent_at"` // Нулевое значение - еще не доставлено
+}
+
+// AdminLogEntry - действие администратора
+type AdminLogEntry struct {
+	ID        int       `db:"id"`
+	AdminID   int64     `db:"admin_id"`
+	Action    string    `db:"action"`    // Команда: stats, broadcast, user, ban, unban, jobs, invite, backup
+	TargetID  int64     `db:"target_id"` // Пользователь, к которому относится действие; 0 - нет
+	Details   string    `db:"details"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// Stats - сводка по боту для администратора
+type Stats struct {
+	Users              int `db:"users"`
+	ActiveUsers        int `db:"active_users"` // Писали боту за последние 30 дней и не заблокировали его
+	BlockedUsers       int `db:"blocked_users"`
+	BannedUsers        int `db:"banned_users"`
+	Credits            int `db:"credits"`
+	RemindersSentToday int `db:"reminders_sent_today"`
+	OutboxPending      int `db:"outbox_pending"` // Уведомления, ждущие отправки или повтора
+}
+
+// Invite - код приглашения для режима доступа invite
+type Invite struct {
+	Code      string    `db:"code"`
+	CreatedBy int64     `db:"created_by"` // Администратор, выпустивший код
+	MaxUses   int       `db:"max_uses"`
+	Uses      int       `db:"uses"`
+	ExpiresAt time.Time `db:"expires_at"` // Нулевое значение - бессрочный
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// Действия в журнале изменений
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"  // Перемещение в корзину
+	AuditRestore = "restore" // Восстановление из корзины
+	AuditPurge   = "purge"   // Окончательное удаление из корзины
+)
+
+// Виды записей в журнале изменений
+const AuditCredit = "credit"
+
+// AuditEntry - изменение данных пользователя со снимками до и после
+type AuditEntry struct {
+	ID        int       `db:"id"`
+	UserID    int64     `db:"user_id"`
+	Entity    string    `db:"entity"`
+	EntityID  int       `db:"entity_id"`
+	Action    string    `db:"action"`
+	Before    string    `db:"before_data"` // JSON до изменения; пусто - записи не было
+	After     string    `db:"after_data"`  // JSON после изменения; пусто - запись удалена
+	CreatedAt time.Time `db:"created_at"`
 }
@@ -0,0 +1,74 @@
+package outbox
+
+import "time"
+
+// Ограничения Telegram: не больше 30 сообщений в секунду всего, не чаще раза в секунду
+// в личный чат и не больше 20 сообщений в минуту в группу
+const (
+	globalInterval  = time.Second / 30
+	privateInterval = time.Second
+	groupInterval   = time.Minute / 20
+)
+
+// limiter распределяет отправку во времени. Используется одним отправителем, поэтому
+// без блокировок.
+type limiter struct {
+	now       func() time.Time    // Текущее время; в тестах подменяется
+	next      time.Time           // Когда можно отправить следующее сообщение
+	chatNext  map[int64]time.Time // Когда можно писать в чат
+	lastSweep time.Time
+}
+
+func newLimiter(now func() time.Time) *limiter {
+	return &limiter{now: now, chatNext: make(map[int64]time.Time)}
+}
+
+// globalReady - сколько ждать до следующей отправки
+func (l *limiter) globalReady() time.Duration {
+	return max(0, l.next.Sub(l.now()))
+}
+
+// chatReady - сколько ждать до следующей отправки в чат
+func (l *limiter) chatReady(chatID int64) time.Duration {
+	return max(0, l.chatNext[chatID].Sub(l.now()))
+}
+
+// busyChats - чаты, в которые сейчас писать нельзя
+func (l *limiter) busyChats() []int64 {
+	now := l.now()
+	var chats []int64
+	for id, next := range l.chatNext {
+		if next.After(now) {
+			chats = append(chats, id)
+		}
+	}
+	return chats
+}
+
+// sent отмечает отправку сообщения в чат
+func (l *limiter) sent(chatID int64) {
+	now := l.now()
+	l.next = now.Add(globalInterval)
+	interval := privateInterval
+	if chatID < 0 {
+		interval = groupInterval
+	}
+	l.chatNext[chatID] = now.Add(interval)
+
+	// Записи о чатах, в которые уже можно писать, больше не нужны
+	if now.Sub(l.lastSweep) > time.Minute {
+		for id, next := range l.chatNext {
+			if next.Before(now) {
+				delete(l.chatNext, id)
+			}
+		}
+		l.lastSweep = now
+	}
+}
+
+// pause приостанавливает всю отправку: Telegram ответил 429 с retry_after
+func (l *limiter) pause(d time.Duration) {
+	if next := l.now().Add(d); next.After(l.next) {
+		l.next = next
+	}
+}
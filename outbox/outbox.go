@@ -0,0 +1,271 @@
+// Package outbox - надежная отправка уведомлений. Уведомление сначала сохраняется в базе,
+// затем фоновый отправитель доставляет его с учетом ограничений Telegram на частоту сообщений
+// и повторяет попытки при сетевых сбоях и ответах 429. Сбой во время рассылки напоминаний
+// больше не теряет их: неотправленное остается в очереди.
+package outbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"DebtBot/db"
+	"DebtBot/logging"
+	"DebtBot/metrics"
+	"DebtBot/models"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+)
+
+var logger = logging.For("outbox")
+
+const (
+	batchSize    = 50
+	pollInterval = 5 * time.Second // Как часто проверять очередь, если новых уведомлений не было
+	maxAttempts  = 8
+	baseBackoff  = 10 * time.Second
+	maxBackoff   = time.Hour
+)
+
+// API - часть tgbotapi.BotAPI, через которую отправляются сообщения
+type API interface {
+	Send(c tgbotapi.Chattable) (tgbotapi.Message, error)
+}
+
+// Sender - очередь уведомлений и ее фоновый отправитель
+type Sender struct {
+	api     API
+	db      *db.DB
+	limiter *limiter
+	wake    chan struct{}
+
+	// Часы и ожидание; в тестах подменяются, чтобы не ждать лимитов Telegram
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+// New создает отправителя. Отправка начинается после вызова Run.
+func New(api API, database *db.DB) *Sender {
+	return &Sender{
+		api:     api,
+		db:      database,
+		limiter: newLimiter(time.Now),
+		wake:    make(chan struct{}, 1),
+		now:     time.Now,
+		sleep:   time.Sleep,
+	}
+}
+
+// Enqueue ставит сообщение в очередь. kind - вид уведомления (models.OutboxReminder и т.п.),
+// creditID - кредит, к которому относится напоминание, или 0.
+func (s *Sender) Enqueue(kind string, creditID int, msg tgbotapi.MessageConfig) error {
+	item := &models.OutboxMessage{
+		ChatID:        msg.ChatID,
+		Kind:          kind,
+		CreditID:      creditID,
+		Text:          msg.Text,
+		ParseMode:     msg.ParseMode,
+		Status:        models.OutboxPending,
+		NextAttemptAt: s.now().UTC(),
+	}
+	if msg.ReplyMarkup != nil {
+		markup, err := json.Marshal(msg.ReplyMarkup)
+		if err != nil {
+			return fmt.Errorf("error encoding reply markup: %w", err)
+		}
+		item.ReplyMarkup = string(markup)
+	}
+	if err := s.db.AddOutboxMessage(item); err != nil {
+		return err
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default: // Отправитель и так проснется
+	}
+	return nil
+}
+
+// Run отправляет уведомления из очереди; блокируется навсегда
+func (s *Sender) Run() {
+	for {
+		wait := s.sendDue()
+		select {
+		case <-s.wake:
+		case <-time.After(wait):
+		}
+	}
+}
+
+// sendDue отправляет уведомления, время которых наступило, и возвращает, сколько ждать
+// следующего прохода
+func (s *Sender) sendDue() time.Duration {
+	// Чаты, в которые пока нельзя писать, не выбираются вовсе: иначе полсотни сообщений
+	// в один чат заняли бы всю порцию и задержали остальные чаты
+	busy := s.limiter.busyChats()
+	messages, err := s.db.GetDueOutboxMessages(s.now(), batchSize, busy)
+	if err != nil {
+		logger.Error("Error getting outbox messages", "err", err)
+		return pollInterval
+	}
+
+	wait := pollInterval
+	for _, id := range busy {
+		wait = min(wait, s.limiter.chatReady(id))
+	}
+	delivered := 0
+	for _, msg := range messages {
+		// В один чат нельзя писать чаще лимита: такое сообщение ждет следующего прохода,
+		// остальные чаты не задерживаются
+		if ready := s.limiter.chatReady(msg.ChatID); ready > 0 {
+			wait = min(wait, ready)
+			continue
+		}
+		s.sleep(s.limiter.globalReady())
+		s.deliver(msg)
+		delivered++
+	}
+	if len(messages) == batchSize && delivered > 0 {
+		return 0 // Очередь еще не разобрана
+	}
+	return wait
+}
+
+// deliver отправляет одно уведомление и сохраняет результат
+func (s *Sender) deliver(msg *models.OutboxMessage) {
+	msgLog := logger.With("outbox_id", msg.ID, "chat_id", msg.ChatID, "kind", msg.Kind)
+
+	if msg.ChatID > 0 {
+		if user, err := s.db.GetUser(msg.ChatID); err == nil && (user.Blocked || user.Banned) {
+			reason := "bot was blocked by the user"
+			if user.Banned {
+				reason = "user is banned"
+			}
+			s.finish(msg, models.OutboxBlocked, reason)
+			return
+		}
+	}
+
+	config, err := messageConfig(msg)
+	if err != nil {
+		msgLog.Error("Error decoding outbox message", "err", err)
+		s.finish(msg, models.OutboxFailed, err.Error())
+		return
+	}
+
+	s.limiter.sent(msg.ChatID)
+	msg.Attempts++
+	sent, err := s.api.Send(config)
+	if err == nil {
+		msg.Status = models.OutboxSent
+		msg.MessageID = sent.MessageID
+		msg.SentAt = s.now().UTC()
+		msg.LastError = ""
+		s.save(msg)
+		if msg.Kind == models.OutboxReminder {
+			metrics.ReminderEvent(metrics.ReminderSent)
+		}
+		return
+	}
+	metrics.SendFailed()
+
+	switch kind, retryAfter := classify(err); kind {
+	case errorBlocked:
+		msgLog.Info("Пользователь заблокировал бота", "err", err)
+		if msg.ChatID > 0 {
+			if err := s.db.SetUserBlocked(msg.ChatID, true); err != nil {
+				msgLog.Error("Error marking user as blocked", "err", err)
+			}
+		}
+		s.finish(msg, models.OutboxBlocked, err.Error())
+	case errorPermanent:
+		msgLog.Error("Telegram rejected message", "err", err)
+		s.finish(msg, models.OutboxFailed, err.Error())
+	case errorRateLimited:
+		// Попытка не засчитывается: сообщение корректно, Telegram просит подождать
+		msgLog.Warn("Rate limited by Telegram", "retry_after", retryAfter)
+		msg.Attempts--
+		s.limiter.pause(retryAfter)
+		s.retry(msg, retryAfter, err)
+	default:
+		if msg.Attempts >= maxAttempts {
+			msgLog.Error("Giving up on outbox message", "attempts", msg.Attempts, "err", err)
+			s.finish(msg, models.OutboxFailed, err.Error())
+			return
+		}
+		delay := backoff(msg.Attempts)
+		msgLog.Warn("Error sending outbox message, will retry", "attempts", msg.Attempts, "retry_in", delay, "err", err)
+		s.retry(msg, delay, err)
+	}
+}
+
+func (s *Sender) retry(msg *models.OutboxMessage, delay time.Duration, err error) {
+	msg.NextAttemptAt = s.now().Add(delay).UTC()
+	msg.LastError = err.Error()
+	s.save(msg)
+}
+
+func (s *Sender) finish(msg *models.OutboxMessage, status, reason string) {
+	msg.Status = status
+	msg.LastError = reason
+	s.save(msg)
+	if msg.Kind == models.OutboxReminder {
+		metrics.ReminderEvent(metrics.ReminderFailed)
+	}
+}
+
+func (s *Sender) save(msg *models.OutboxMessage) {
+	if err := s.db.UpdateOutboxMessage(msg); err != nil {
+		logger.Error("Error saving outbox message", "outbox_id", msg.ID, "err", err)
+	}
+}
+
+// messageConfig восстанавливает сообщение для отправки из записи очереди
+func messageConfig(msg *models.OutboxMessage) (tgbotapi.MessageConfig, error) {
+	config := tgbotapi.NewMessage(msg.ChatID, msg.Text)
+	config.ParseMode = msg.ParseMode
+	if msg.ReplyMarkup != "" {
+		var markup tgbotapi.InlineKeyboardMarkup
+		if err := json.Unmarshal([]byte(msg.ReplyMarkup), &markup); err != nil {
+			return config, err
+		}
+		config.ReplyMarkup = markup
+	}
+	return config, nil
+}
+
+// backoff - пауза перед повтором: 10 с, 20 с, 40 с... но не больше часа
+func backoff(attempts int) time.Duration {
+	delay := baseBackoff << (attempts - 1)
+	if delay <= 0 || delay > maxBackoff {
+		return maxBackoff
+	}
+	return delay
+}
+
+type errorKind int
+
+const (
+	errorTemporary   errorKind = iota // Сеть, 5xx - повторить позже
+	errorRateLimited                  // 429 - повторить через retry_after
+	errorBlocked                      // 403 - пользователь заблокировал бота или удалил аккаунт
+	errorPermanent                    // 400 - повтор не поможет
+)
+
+// classify определяет по ответу Telegram, стоит ли повторять отправку. Библиотека не
+// возвращает код ответа, поэтому 403 и 400 распознаются по началу описания ошибки.
+func classify(err error) (errorKind, time.Duration) {
+	apiErr, ok := err.(tgbotapi.Error)
+	if !ok {
+		return errorTemporary, 0
+	}
+	switch {
+	case apiErr.RetryAfter > 0:
+		return errorRateLimited, time.Duration(apiErr.RetryAfter) * time.Second
+	case strings.HasPrefix(apiErr.Message, "Forbidden"):
+		return errorBlocked, 0
+	case strings.HasPrefix(apiErr.Message, "Bad Request"):
+		return errorPermanent, 0
+	}
+	return errorTemporary, 0
+}
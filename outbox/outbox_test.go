@@ -0,0 +1,309 @@
+package outbox
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"DebtBot/db"
+	"DebtBot/models"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api"
+	"github.com/jmoiron/sqlx"
+)
+
+var start = time.Date(2026, time.October, 19, 9, 0, 0, 0, time.UTC)
+
+// fakeClock - часы, которые идут только при ожидании
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time        { return c.t }
+func (c *fakeClock) sleep(d time.Duration) { c.t = c.t.Add(d) }
+func (c *fakeClock) advanceTo(t time.Time) { c.t = t }
+
+type sentMessage struct {
+	chatID int64
+	at     time.Time
+}
+
+// fakeAPI запоминает отправленное и возвращает ошибки из errs по очереди; nil - успех
+type fakeAPI struct {
+	clock *fakeClock
+	errs  []error
+	sent  []sentMessage
+}
+
+func (a *fakeAPI) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	msg := c.(tgbotapi.MessageConfig)
+	if len(a.errs) > 0 {
+		err := a.errs[0]
+		a.errs = a.errs[1:]
+		if err != nil {
+			return tgbotapi.Message{}, err
+		}
+	}
+	a.sent = append(a.sent, sentMessage{chatID: msg.ChatID, at: a.clock.now()})
+	return tgbotapi.Message{MessageID: len(a.sent)}, nil
+}
+
+func newTestSender(t *testing.T, errs ...error) (*Sender, *fakeAPI, *fakeClock) {
+	t.Helper()
+	database, err := sqlx.Connect("sqlite3", filepath.Join(t.TempDir(), "test.db")+"?_foreign_keys=on")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { database.Close() })
+	d := &db.DB{DB: database}
+	if err := d.InitSchema(); err != nil {
+		t.Fatal(err)
+	}
+
+	clock := &fakeClock{t: start}
+	api := &fakeAPI{clock: clock, errs: errs}
+	s := New(api, d)
+	s.now, s.sleep = clock.now, clock.sleep
+	s.limiter.now = clock.now
+	return s, api, clock
+}
+
+func enqueue(t *testing.T, s *Sender, chatIDs ...int64) {
+	t.Helper()
+	for _, chatID := range chatIDs {
+		if err := s.Enqueue(models.OutboxBroadcast, 0, tgbotapi.NewMessage(chatID, "текст")); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func outboxMessage(t *testing.T, s *Sender, chatID int64) *models.OutboxMessage {
+	t.Helper()
+	messages, err := s.db.GetOutboxByChat(chatID)
+	if err != nil || len(messages) != 1 {
+		t.Fatalf("GetOutboxByChat(%d) = %d messages, %v", chatID, len(messages), err)
+	}
+	return messages[0]
+}
+
+func TestLimiter(t *testing.T) {
+	clock := &fakeClock{t: start}
+	l := newLimiter(clock.now)
+
+	l.sent(1)
+	l.sent(-100)
+	if got := l.globalReady(); got != globalInterval {
+		t.Errorf("globalReady = %v, want %v", got, globalInterval)
+	}
+	if got := l.chatReady(1); got != time.Second {
+		t.Errorf("private chatReady = %v, want 1s", got)
+	}
+	if got := l.chatReady(-100); got != 3*time.Second {
+		t.Errorf("group chatReady = %v, want 3s", got)
+	}
+	if got := l.chatReady(2); got != 0 {
+		t.Errorf("untouched chatReady = %v, want 0", got)
+	}
+
+	clock.sleep(time.Second)
+	if got := l.chatReady(1); got != 0 {
+		t.Errorf("private chatReady after 1s = %v, want 0", got)
+	}
+	if got := l.busyChats(); len(got) != 1 || got[0] != -100 {
+		t.Errorf("busyChats after 1s = %v, want [-100]", got)
+	}
+
+	// pause продлевает общую паузу, но не сокращает ее
+	l.pause(5 * time.Second)
+	if got := l.globalReady(); got != 5*time.Second {
+		t.Errorf("globalReady after pause = %v, want 5s", got)
+	}
+	l.pause(time.Second)
+	if got := l.globalReady(); got != 5*time.Second {
+		t.Errorf("globalReady after shorter pause = %v, want 5s", got)
+	}
+
+	// Раз в минуту записи о свободных чатах удаляются
+	clock.sleep(2 * time.Minute)
+	l.sent(2)
+	if _, ok := l.chatNext[1]; ok || len(l.chatNext) != 1 {
+		t.Errorf("chatNext after sweep = %v, want only chat 2", l.chatNext)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 10 * time.Second},
+		{2, 20 * time.Second},
+		{3, 40 * time.Second},
+		{7, 640 * time.Second},
+		{9, 2560 * time.Second},
+		{10, time.Hour},
+		{64, time.Hour}, // Переполнение сдвига
+		{200, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := backoff(tt.attempts); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		kind       errorKind
+		retryAfter time.Duration
+	}{
+		{"сеть", errors.New("dial tcp: connection reset by peer"), errorTemporary, 0},
+		{"429", tgbotapi.Error{Message: "Too Many Requests: retry after 7", ResponseParameters: tgbotapi.ResponseParameters{RetryAfter: 7}}, errorRateLimited, 7 * time.Second},
+		{"заблокирован", tgbotapi.Error{Message: "Forbidden: bot was blocked by the user"}, errorBlocked, 0},
+		{"аккаунт удален", tgbotapi.Error{Message: "Forbidden: user is deactivated"}, errorBlocked, 0},
+		{"неверный запрос", tgbotapi.Error{Message: "Bad Request: chat not found"}, errorPermanent, 0},
+		{"ошибка сервера", tgbotapi.Error{Message: "Internal Server Error"}, errorTemporary, 0},
+	}
+	for _, tt := range tests {
+		kind, retryAfter := classify(tt.err)
+		if kind != tt.kind || retryAfter != tt.retryAfter {
+			t.Errorf("%s: classify = %v, %v, want %v, %v", tt.name, kind, retryAfter, tt.kind, tt.retryAfter)
+		}
+	}
+}
+
+// Сообщения в один чат и в группу не отправляются чаще лимитов, остальные чаты не ждут
+func TestSendDueRespectsLimits(t *testing.T) {
+	s, api, clock := newTestSender(t)
+	enqueue(t, s, 1, 1, 1, -100, -100, 2)
+
+	for i := 0; i < 100 && len(api.sent) < 6; i++ {
+		clock.sleep(s.sendDue())
+	}
+	if len(api.sent) != 6 {
+		t.Fatalf("sent %d messages, want 6", len(api.sent))
+	}
+
+	last := make(map[int64]time.Time)
+	for i, msg := range api.sent {
+		if i > 0 && msg.at.Sub(api.sent[i-1].at) < globalInterval {
+			t.Errorf("message %d sent %v after the previous one", i, msg.at.Sub(api.sent[i-1].at))
+		}
+		interval := privateInterval
+		if msg.chatID < 0 {
+			interval = groupInterval
+		}
+		if prev, ok := last[msg.chatID]; ok && msg.at.Sub(prev) < interval {
+			t.Errorf("chat %d: message sent %v after the previous one, want at least %v", msg.chatID, msg.at.Sub(prev), interval)
+		}
+		last[msg.chatID] = msg.at
+	}
+	// Первый проход отправляет по одному сообщению в каждый чат, не дожидаясь занятых
+	if api.sent[2].chatID != 2 || api.sent[2].at.Sub(start) > time.Second {
+		t.Errorf("chat 2 waited for busy chats: sent %+v", api.sent[2])
+	}
+}
+
+// При временной ошибке попытки повторяются с растущей паузой, после maxAttempts - отказ
+func TestDeliverBackoff(t *testing.T) {
+	errs := make([]error, maxAttempts)
+	for i := range errs {
+		errs[i] = errors.New("connection reset by peer")
+	}
+	s, api, clock := newTestSender(t, errs...)
+	enqueue(t, s, 1)
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		s.sendDue()
+		msg := outboxMessage(t, s, 1)
+		if msg.Attempts != attempt {
+			t.Fatalf("attempt %d: Attempts = %d", attempt, msg.Attempts)
+		}
+		if attempt == maxAttempts {
+			if msg.Status != models.OutboxFailed {
+				t.Errorf("after %d attempts Status = %q, want failed", attempt, msg.Status)
+			}
+			break
+		}
+		if msg.Status != models.OutboxPending || msg.LastError == "" {
+			t.Errorf("attempt %d: Status = %q, LastError = %q", attempt, msg.Status, msg.LastError)
+		}
+		if got := msg.NextAttemptAt.Sub(clock.now()); got != backoff(attempt) {
+			t.Errorf("attempt %d: retry in %v, want %v", attempt, got, backoff(attempt))
+		}
+
+		// До назначенного времени сообщение не отправляется повторно
+		clock.advanceTo(msg.NextAttemptAt.Add(-2 * time.Second))
+		s.sendDue()
+		if got := outboxMessage(t, s, 1).Attempts; got != attempt {
+			t.Fatalf("attempt %d: retried before %v", attempt, msg.NextAttemptAt)
+		}
+		clock.advanceTo(msg.NextAttemptAt)
+	}
+	if len(api.sent) != 0 {
+		t.Errorf("sent %d messages, want 0", len(api.sent))
+	}
+}
+
+// Ответ 429 приостанавливает всю отправку на retry_after и не расходует попытку
+func TestDeliverRetryAfter(t *testing.T) {
+	retryAfter := tgbotapi.Error{Message: "Too Many Requests: retry after 7", ResponseParameters: tgbotapi.ResponseParameters{RetryAfter: 7}}
+	s, api, clock := newTestSender(t, retryAfter)
+	enqueue(t, s, 1, 2)
+
+	s.sendDue()
+	msg := outboxMessage(t, s, 1)
+	if msg.Status != models.OutboxPending || msg.Attempts != 0 {
+		t.Errorf("after 429: Status = %q, Attempts = %d, want pending, 0", msg.Status, msg.Attempts)
+	}
+	if !msg.NextAttemptAt.Equal(start.Add(7 * time.Second)) {
+		t.Errorf("after 429: NextAttemptAt = %v, want %v", msg.NextAttemptAt, start.Add(7*time.Second))
+	}
+	// Сообщение в другой чат ушло только после паузы
+	if len(api.sent) != 1 || api.sent[0].chatID != 2 || !api.sent[0].at.Equal(start.Add(7*time.Second)) {
+		t.Errorf("after 429: sent %+v, want chat 2 at +7s", api.sent)
+	}
+
+	clock.sleep(time.Second)
+	s.sendDue()
+	if msg := outboxMessage(t, s, 1); msg.Status != models.OutboxSent || msg.Attempts != 1 || msg.LastError != "" {
+		t.Errorf("after retry: Status = %q, Attempts = %d, LastError = %q", msg.Status, msg.Attempts, msg.LastError)
+	}
+}
+
+// Отказ Telegram не повторяется; 403 от пользователя отмечает его заблокировавшим бота
+func TestDeliverPermanentErrors(t *testing.T) {
+	s, api, clock := newTestSender(t,
+		tgbotapi.Error{Message: "Forbidden: bot was blocked by the user"},
+		tgbotapi.Error{Message: "Bad Request: chat not found"},
+	)
+	if _, err := s.db.CreateUserIfNotExist(1); err != nil {
+		t.Fatal(err)
+	}
+	enqueue(t, s, 1, 2)
+
+	s.sendDue()
+	if msg := outboxMessage(t, s, 1); msg.Status != models.OutboxBlocked || msg.Attempts != 1 {
+		t.Errorf("403: Status = %q, Attempts = %d, want blocked, 1", msg.Status, msg.Attempts)
+	}
+	if user, err := s.db.GetUser(1); err != nil || !user.Blocked {
+		t.Errorf("403: user blocked = %v, %v, want true", user.Blocked, err)
+	}
+	if msg := outboxMessage(t, s, 2); msg.Status != models.OutboxFailed || msg.Attempts != 1 {
+		t.Errorf("400: Status = %q, Attempts = %d, want failed, 1", msg.Status, msg.Attempts)
+	}
+
+	// Заблокировавшему бота новые сообщения не отправляются вовсе
+	enqueue(t, s, 1)
+	clock.sleep(time.Second)
+	s.sendDue()
+	messages, err := s.db.GetOutboxByChat(1)
+	if err != nil || len(messages) != 2 || messages[1].Status != models.OutboxBlocked || messages[1].Attempts != 0 {
+		t.Errorf("message to blocked user = %+v, %v", messages, err)
+	}
+	if len(api.sent) != 0 {
+		t.Errorf("sent %d messages, want 0", len(api.sent))
+	}
+}
@@ -0,0 +1,140 @@
+package schedule
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"DebtBot/banks"
+	"DebtBot/models"
+)
+
+// Installment - один плановый платеж по кредиту
+type Installment struct {
+	CreditID  int
+	BankName  string // Название из справочника банков, если банк в нем есть
+	Number    int    // Порядковый номер платежа, начиная с 1
+	Date      time.Time
+	Amount    float64
+	Principal float64 // Погашение основного долга
+	Interest  float64 // Проценты
+}
+
+// ForCredit строит график платежей по кредиту. Если указан срок, это аннуитетные платежи
+// раз в месяц: первый - в дату платежа кредита, следующие - в тот же день месяца
+// (в коротких месяцах - в последний день). Без срока - один платеж на всю сумму.
+func ForCredit(credit *models.Credit) []Installment {
+	bankName := banks.Canonical(credit.BankID, credit.BankName)
+	if credit.TermMonths <= 0 {
+		return []Installment{{
+			CreditID:  credit.ID,
+			BankName:  bankName,
+			Number:    1,
+			Date:      credit.DueDate,
+			Amount:    credit.LoanAmount,
+			Principal: credit.LoanAmount,
+		}}
+	}
+
+	rate := credit.InterestRate / 100 / 12
+	payment := MonthlyPayment(credit.LoanAmount, credit.InterestRate, credit.TermMonths)
+	balance := credit.LoanAmount
+	installments := make([]Installment, 0, credit.TermMonths)
+	for i := 0; i < credit.TermMonths; i++ {
+		interest := round(balance * rate)
+		principal := round(payment - interest)
+		// Последний платеж закрывает остаток, накопившийся из-за округления до копеек
+		if i == credit.TermMonths-1 || principal > balance {
+			principal = balance
+		}
+		balance = round(balance - principal)
+		installments = append(installments, Installment{
+			CreditID:  credit.ID,
+			BankName:  bankName,
+			Number:    i + 1,
+			Date:      addMonths(credit.DueDate, i),
+			Amount:    round(principal + interest),
+			Principal: principal,
+			Interest:  interest,
+		})
+	}
+	return installments
+}
+
+// MonthlyPayment - аннуитетный платеж по кредиту на сумму amount под rate % годовых
+// на months месяцев, округленный до копеек. Без ставки - сумма, поделенная поровну.
+func MonthlyPayment(amount, rate float64, months int) float64 {
+	if months <= 0 {
+		return amount
+	}
+	r := rate / 100 / 12
+	if r <= 0 {
+		return round(amount / float64(months))
+	}
+	return round(amount * r / (1 - math.Pow(1+r, -float64(months))))
+}
+
+// Next возвращает первый платеж по графику в день from или позже.
+// false - все платежи уже прошли.
+func Next(credit *models.Credit, from time.Time) (Installment, bool) {
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	for _, installment := range ForCredit(credit) {
+		if !installment.Date.Before(day) {
+			return installment, true
+		}
+	}
+	return Installment{}, false
+}
+
+// NextOrLast - ближайший платеж начиная с from, а если все платежи прошли - последний
+func NextOrLast(credit *models.Credit, from time.Time) Installment {
+	if installment, ok := Next(credit, from); ok {
+		return installment
+	}
+	installments := ForCredit(credit)
+	return installments[len(installments)-1]
+}
+
+// Outstanding - остаток основного долга после платежей на сумму paid. Внесенное гасит платежи
+// графика по порядку, внутри платежа - сначала проценты, потом основной долг;
+// переплата сверх графика целиком уходит в основной долг.
+func Outstanding(credit *models.Credit, paid float64) float64 {
+	principal := credit.LoanAmount
+	for _, installment := range ForCredit(credit) {
+		if paid <= 0 {
+			break
+		}
+		covered := min(paid, installment.Amount)
+		principal -= max(covered-installment.Interest, 0)
+		paid -= covered
+	}
+	if paid > 0 {
+		principal -= paid
+	}
+	return max(round(principal), 0)
+}
+
+// ForCredits строит общий график по всем кредитам, отсортированный по дате
+func ForCredits(credits []*models.Credit) []Installment {
+	installments := []Installment{}
+	for _, credit := range credits {
+		installments = append(installments, ForCredit(credit)...)
+	}
+	sort.SliceStable(installments, func(i, j int) bool {
+		return installments[i].Date.Before(installments[j].Date)
+	})
+	return installments
+}
+
+// addMonths сдвигает дату на months месяцев, не перескакивая в следующий месяц:
+// 31 января + 1 месяц = 28/29 февраля, но 31 января + 2 месяца = 31 марта
+func addMonths(t time.Time, months int) time.Time {
+	month := time.Date(t.Year(), t.Month()+time.Month(months), 1, 0, 0, 0, 0, t.Location())
+	lastDay := time.Date(month.Year(), month.Month()+1, 0, 0, 0, 0, 0, t.Location()).Day()
+	return time.Date(month.Year(), month.Month(), min(t.Day(), lastDay), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+// round округляет сумму до копеек
+func round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
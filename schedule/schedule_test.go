@@ -0,0 +1,202 @@
+package schedule
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"DebtBot/models"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestMonthlyPayment(t *testing.T) {
+	tests := []struct {
+		amount float64
+		rate   float64
+		months int
+		want   float64
+	}{
+		{500000, 12, 36, 16607.15},
+		{100000, 10, 12, 8791.59},
+		{1000000, 7.5, 240, 8055.93},
+		{120000, 0, 12, 10000},   // Без ставки - поровну
+		{100000, 0, 3, 33333.33}, // Поровну с округлением до копеек
+		{100000, 12, 1, 101000},  // Один месяц - сумма и проценты за месяц
+		{100000, 12, 0, 100000},  // Без срока - вся сумма
+		{100000, 12, -1, 100000}, // Отрицательный срок - как без срока
+		{50000, 29.9, 24, 2793.07},
+	}
+	for _, tt := range tests {
+		if got := MonthlyPayment(tt.amount, tt.rate, tt.months); got != tt.want {
+			t.Errorf("MonthlyPayment(%v, %v, %d) = %v, want %v", tt.amount, tt.rate, tt.months, got, tt.want)
+		}
+	}
+}
+
+func TestForCredit(t *testing.T) {
+	tests := []struct {
+		name      string
+		credit    models.Credit
+		count     int
+		payment   float64 // Сумма каждого платежа, кроме последнего
+		last      float64 // Сумма последнего платежа
+		interest  float64 // Переплата по процентам за весь срок
+		firstDate time.Time
+		lastDate  time.Time
+	}{
+		{
+			name:      "без срока - один платеж",
+			credit:    models.Credit{LoanAmount: 150000, InterestRate: 12, DueDate: date(2026, time.March, 15)},
+			count:     1,
+			last:      150000,
+			firstDate: date(2026, time.March, 15),
+			lastDate:  date(2026, time.March, 15),
+		},
+		{
+			name:      "аннуитет 36 месяцев",
+			credit:    models.Credit{LoanAmount: 500000, InterestRate: 12, TermMonths: 36, DueDate: date(2026, time.January, 15)},
+			count:     36,
+			payment:   16607.15,
+			last:      16607.38,
+			interest:  97857.63,
+			firstDate: date(2026, time.January, 15),
+			lastDate:  date(2028, time.December, 15),
+		},
+		{
+			name:      "без ставки - поровну, остаток копеек в последнем платеже",
+			credit:    models.Credit{LoanAmount: 100000, TermMonths: 3, DueDate: date(2026, time.January, 31)},
+			count:     3,
+			payment:   33333.33,
+			last:      33333.34,
+			firstDate: date(2026, time.January, 31),
+			lastDate:  date(2026, time.March, 31),
+		},
+		{
+			name:      "один месяц",
+			credit:    models.Credit{LoanAmount: 100000, InterestRate: 12, TermMonths: 1, DueDate: date(2026, time.May, 1)},
+			count:     1,
+			last:      101000,
+			interest:  1000,
+			firstDate: date(2026, time.May, 1),
+			lastDate:  date(2026, time.May, 1),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installments := ForCredit(&tt.credit)
+			if len(installments) != tt.count {
+				t.Fatalf("got %d installments, want %d", len(installments), tt.count)
+			}
+			principal, interest := 0.0, 0.0
+			for i, installment := range installments {
+				if installment.Number != i+1 {
+					t.Errorf("installment %d: Number = %d", i, installment.Number)
+				}
+				if math.Abs(installment.Principal+installment.Interest-installment.Amount) > 0.001 {
+					t.Errorf("installment %d: principal %v + interest %v != amount %v", i+1, installment.Principal, installment.Interest, installment.Amount)
+				}
+				if i < len(installments)-1 && installment.Amount != tt.payment {
+					t.Errorf("installment %d: Amount = %v, want %v", i+1, installment.Amount, tt.payment)
+				}
+				principal += installment.Principal
+				interest += installment.Interest
+			}
+			// Основной долг гасится ровно до копейки, проценты считаются отдельно
+			if math.Abs(principal-tt.credit.LoanAmount) > 0.001 {
+				t.Errorf("principal sum = %.2f, want %.2f", principal, tt.credit.LoanAmount)
+			}
+			if math.Abs(interest-tt.interest) > 0.001 {
+				t.Errorf("interest sum = %.2f, want %.2f", interest, tt.interest)
+			}
+			if got := installments[len(installments)-1].Amount; got != tt.last {
+				t.Errorf("last Amount = %v, want %v", got, tt.last)
+			}
+			if got := installments[0].Date; !got.Equal(tt.firstDate) {
+				t.Errorf("first Date = %s, want %s", got.Format(time.DateOnly), tt.firstDate.Format(time.DateOnly))
+			}
+			if got := installments[len(installments)-1].Date; !got.Equal(tt.lastDate) {
+				t.Errorf("last Date = %s, want %s", got.Format(time.DateOnly), tt.lastDate.Format(time.DateOnly))
+			}
+		})
+	}
+}
+
+func TestAddMonths(t *testing.T) {
+	tests := []struct {
+		from   time.Time
+		months int
+		want   time.Time
+	}{
+		{date(2026, time.January, 15), 0, date(2026, time.January, 15)},
+		{date(2026, time.January, 15), 1, date(2026, time.February, 15)},
+		{date(2026, time.January, 31), 1, date(2026, time.February, 28)},
+		{date(2028, time.January, 31), 1, date(2028, time.February, 29)},
+		{date(2026, time.January, 31), 2, date(2026, time.March, 31)},
+		{date(2026, time.January, 31), 3, date(2026, time.April, 30)},
+		{date(2026, time.March, 30), 11, date(2027, time.February, 28)},
+		{date(2026, time.November, 30), 2, date(2027, time.January, 30)},
+		{date(2026, time.December, 31), 12, date(2027, time.December, 31)},
+		{date(2026, time.October, 19), 36, date(2029, time.October, 19)},
+	}
+	for _, tt := range tests {
+		if got := addMonths(tt.from, tt.months); !got.Equal(tt.want) {
+			t.Errorf("addMonths(%s, %d) = %s, want %s", tt.from.Format(time.DateOnly), tt.months, got.Format(time.DateOnly), tt.want.Format(time.DateOnly))
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	credit := &models.Credit{LoanAmount: 90000, TermMonths: 3, DueDate: date(2026, time.January, 31)}
+	tests := []struct {
+		from       time.Time
+		wantNumber int
+		wantOK     bool
+	}{
+		{date(2025, time.December, 1), 1, true},
+		{date(2026, time.January, 31), 1, true}, // Платеж в сам день from
+		{time.Date(2026, time.January, 31, 18, 45, 0, 0, time.UTC), 1, true},
+		{date(2026, time.February, 1), 2, true},
+		{date(2026, time.February, 28), 2, true},
+		{date(2026, time.March, 1), 3, true},
+		{date(2026, time.March, 31), 3, true},
+		{date(2026, time.April, 1), 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := Next(credit, tt.from)
+		if ok != tt.wantOK || got.Number != tt.wantNumber {
+			t.Errorf("Next(%s) = #%d, %v, want #%d, %v", tt.from.Format(time.DateTime), got.Number, ok, tt.wantNumber, tt.wantOK)
+		}
+	}
+
+	if got := NextOrLast(credit, date(2027, time.January, 1)); got.Number != 3 {
+		t.Errorf("NextOrLast after schedule = #%d, want #3", got.Number)
+	}
+}
+
+func TestOutstanding(t *testing.T) {
+	annuity := &models.Credit{LoanAmount: 500000, InterestRate: 12, TermMonths: 36, DueDate: date(2026, time.January, 15)}
+	single := &models.Credit{LoanAmount: 150000, DueDate: date(2026, time.March, 15)}
+	tests := []struct {
+		name   string
+		credit *models.Credit
+		paid   float64
+		want   float64
+	}{
+		{"ничего не внесено", annuity, 0, 500000},
+		{"первый платеж: проценты 5000, долг 11607.15", annuity, 16607.15, 488392.85},
+		{"внесено меньше процентов", annuity, 3000, 500000},
+		{"часть платежа сверх процентов", annuity, 6000, 499000},
+		{"весь график", annuity, 597857.63, 0},
+		{"переплата", annuity, 700000, 0},
+		{"без срока", single, 50000, 100000},
+		{"без срока, переплата", single, 200000, 0},
+	}
+	for _, tt := range tests {
+		if got := Outstanding(tt.credit, tt.paid); got != tt.want {
+			t.Errorf("%s: Outstanding(%v) = %v, want %v", tt.name, tt.paid, got, tt.want)
+		}
+	}
+}
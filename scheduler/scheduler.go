@@ -0,0 +1,154 @@
+// Package scheduler запускает периодические задачи бота (напоминания, выписки)
+package scheduler
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"DebtBot/logging"
+)
+
+var logger = logging.For("scheduler")
+
+// Job - периодическая задача
+type Job struct {
+	Name string
+	next func(now time.Time) time.Time // Время следующего запуска после now
+	run  func()
+
+	mu      sync.Mutex
+	lastRun time.Time
+	nextRun time.Time
+	running bool
+}
+
+// JobInfo - состояние задачи для просмотра
+type JobInfo struct {
+	Name    string
+	LastRun time.Time // Нулевое значение - задача еще не запускалась
+	NextRun time.Time
+	Running bool
+}
+
+type Scheduler struct {
+	location *time.Location
+
+	mu   sync.Mutex
+	jobs []*Job
+}
+
+// New создает планировщик; время задач считается в часовом поясе location
+func New(location *time.Location) *Scheduler {
+	if location == nil {
+		location = time.Local
+	}
+	return &Scheduler{location: location}
+}
+
+// Daily - каждый день в hour:minute
+func (s *Scheduler) Daily(name string, hour, minute int, run func()) {
+	s.add(name, run, func(now time.Time) time.Time {
+		next := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, s.location)
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 1)
+		}
+		return next
+	})
+}
+
+// Weekly - каждую неделю в указанный день недели в hour:minute
+func (s *Scheduler) Weekly(name string, weekday time.Weekday, hour, minute int, run func()) {
+	s.add(name, run, func(now time.Time) time.Time {
+		days := (int(weekday) - int(now.Weekday()) + 7) % 7
+		next := time.Date(now.Year(), now.Month(), now.Day()+days, hour, minute, 0, 0, s.location)
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 7)
+		}
+		return next
+	})
+}
+
+// Monthly - каждый месяц в указанный день (1-28) в hour:minute
+func (s *Scheduler) Monthly(name string, day, hour, minute int, run func()) {
+	s.add(name, run, func(now time.Time) time.Time {
+		next := time.Date(now.Year(), now.Month(), day, hour, minute, 0, 0, s.location)
+		if !next.After(now) {
+			next = time.Date(now.Year(), now.Month()+1, day, hour, minute, 0, 0, s.location)
+		}
+		return next
+	})
+}
+
+// Every - с заданным интервалом, выровненным по началу часа (например, каждый час в :00)
+func (s *Scheduler) Every(name string, interval time.Duration, run func()) {
+	s.add(name, run, func(now time.Time) time.Time {
+		hour := time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), 0, 0, 0, s.location)
+		next := hour.Add(now.Sub(hour).Truncate(interval) + interval)
+		return next
+	})
+}
+
+func (s *Scheduler) add(name string, run func(), next func(now time.Time) time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs = append(s.jobs, &Job{Name: name, next: next, run: run})
+}
+
+// Start запускает все задачи, каждую в своей горутине
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, job := range s.jobs {
+		go s.loop(job)
+	}
+}
+
+func (s *Scheduler) loop(job *Job) {
+	for {
+		now := time.Now().In(s.location)
+		next := job.next(now)
+
+		job.mu.Lock()
+		job.nextRun = next
+		job.mu.Unlock()
+
+		time.Sleep(next.Sub(now))
+		s.runJob(job)
+	}
+}
+
+func (s *Scheduler) runJob(job *Job) {
+	job.mu.Lock()
+	job.running = true
+	job.lastRun = time.Now()
+	job.mu.Unlock()
+
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error("Job panicked", "job", job.Name, "panic", r)
+		}
+		job.mu.Lock()
+		job.running = false
+		job.mu.Unlock()
+	}()
+
+	// Обычный запуск - Debug: задачи вроде snoozes запускаются каждую минуту
+	logger.Debug("Running job", "job", job.Name)
+	job.run()
+}
+
+// Jobs возвращает состояние всех задач, отсортированное по времени следующего запуска
+func (s *Scheduler) Jobs() []JobInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	infos := make([]JobInfo, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		job.mu.Lock()
+		infos = append(infos, JobInfo{Name: job.Name, LastRun: job.lastRun, NextRun: job.nextRun, Running: job.running})
+		job.mu.Unlock()
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].NextRun.Before(infos[j].NextRun) })
+	return infos
+}
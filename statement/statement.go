@@ -0,0 +1,204 @@
+// Package statement формирует ежемесячную PDF-выписку пользователя по кредитам
+package statement
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"DebtBot/fonts"
+	"DebtBot/i18n"
+	"DebtBot/models"
+	"DebtBot/schedule"
+	"github.com/go-pdf/fpdf"
+)
+
+// На сколько дней вперед показывать предстоящие платежи
+const upcomingDays = 30
+
+// CreditLine - строка таблицы кредитов
+type CreditLine struct {
+	BankName   string
+	LoanAmount float64
+	Paid       float64 // Всего внесено по кредиту на конец периода
+	Remaining  float64
+	DueDate    time.Time
+}
+
+// PaymentLine - платеж, внесенный за период
+type PaymentLine struct {
+	Date     time.Time
+	BankName string
+	Amount   float64
+}
+
+// Data - содержимое выписки
+type Data struct {
+	Month     time.Time // Первое число месяца выписки
+	Generated time.Time
+	Credits   []CreditLine
+	Payments  []PaymentLine
+	Upcoming  []schedule.Installment
+
+	TotalLoan      float64
+	TotalPaid      float64 // Внесено за месяц
+	TotalRemaining float64
+	TotalUpcoming  float64
+}
+
+// MonthStart возвращает первое число месяца, в который попадает t
+func MonthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// MonthTitle - название месяца для заголовков: "октябрь 2024", "October 2024"
+func MonthTitle(tr *i18n.Localizer, month time.Time) string {
+	return fmt.Sprintf("%s %d", tr.Month(month.Month()), month.Year())
+}
+
+// Build собирает данные выписки за месяц month на момент now
+func Build(credits []*models.Credit, payments []*models.Payment, month, now time.Time) Data {
+	month = MonthStart(month)
+	monthEnd := month.AddDate(0, 1, 0)
+	data := Data{Month: month, Generated: now}
+
+	banks := make(map[int]string, len(credits))
+	paidByCredit := make(map[int]float64)
+	for _, credit := range credits {
+		banks[credit.ID] = credit.BankName
+	}
+	for _, payment := range payments {
+		if !payment.PaidAt.Before(monthEnd) {
+			continue
+		}
+		paidByCredit[payment.CreditID] += payment.Amount
+		if !payment.PaidAt.Before(month) {
+			data.Payments = append(data.Payments, PaymentLine{Date: payment.PaidAt, BankName: banks[payment.CreditID], Amount: payment.Amount})
+			data.TotalPaid += payment.Amount
+		}
+	}
+
+	for _, credit := range credits {
+		remaining := schedule.Outstanding(credit, paidByCredit[credit.ID])
+		data.Credits = append(data.Credits, CreditLine{
+			BankName:   credit.BankName,
+			LoanAmount: credit.LoanAmount,
+			Paid:       paidByCredit[credit.ID],
+			Remaining:  remaining,
+			DueDate:    schedule.NextOrLast(credit, now).Date,
+		})
+		data.TotalLoan += credit.LoanAmount
+		data.TotalRemaining += remaining
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	horizon := today.AddDate(0, 0, upcomingDays)
+	for _, installment := range schedule.ForCredits(credits) {
+		if installment.Date.Before(today) || installment.Date.After(horizon) {
+			continue
+		}
+		data.Upcoming = append(data.Upcoming, installment)
+		data.TotalUpcoming += installment.Amount
+	}
+	return data
+}
+
+// Render рисует выписку в PDF на языке tr
+func Render(tr *i18n.Localizer, data Data) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes("DejaVu", "", fonts.Regular)
+	pdf.AddUTF8FontFromBytes("DejaVu", "B", fonts.Bold)
+	pdf.SetTitle(tr.T("statement.pdf_title", MonthTitle(tr, data.Month)), true)
+	pdf.SetAuthor("DebtBot", true)
+	pdf.SetMargins(15, 15, 15)
+	pdf.AddPage()
+
+	pdf.SetFont("DejaVu", "B", 16)
+	pdf.CellFormat(0, 10, tr.T("statement.pdf_heading", MonthTitle(tr, data.Month)), "", 1, "L", false, 0, "")
+	pdf.SetFont("DejaVu", "", 9)
+	pdf.SetTextColor(110, 110, 110)
+	pdf.CellFormat(0, 6, tr.T("statement.pdf_generated", data.Generated.Format("02.01.2006 15:04")), "", 1, "L", false, 0, "")
+	pdf.SetTextColor(0, 0, 0)
+	pdf.Ln(4)
+
+	section(pdf, tr.T("statement.pdf_credits"))
+	creditRows := [][]string{}
+	for _, credit := range data.Credits {
+		creditRows = append(creditRows, []string{credit.BankName, money(credit.LoanAmount), money(credit.Paid), money(credit.Remaining), credit.DueDate.Format("02.01.2006")})
+	}
+	header := []string{tr.T("statement.pdf_bank"), tr.T("statement.pdf_amount"), tr.T("statement.pdf_paid"), tr.T("statement.pdf_remaining"), tr.T("statement.pdf_due_date")}
+	table(tr, pdf, header, []float64{60, 30, 30, 30, 30}, creditRows,
+		[]string{tr.T("statement.pdf_total"), money(data.TotalLoan), "", money(data.TotalRemaining), ""})
+
+	section(pdf, tr.T("statement.pdf_payments"))
+	paymentRows := [][]string{}
+	for _, payment := range data.Payments {
+		paymentRows = append(paymentRows, []string{payment.Date.Format("02.01.2006"), payment.BankName, money(payment.Amount)})
+	}
+	header = []string{tr.T("statement.pdf_date"), tr.T("statement.pdf_bank"), tr.T("statement.pdf_amount")}
+	table(tr, pdf, header, []float64{30, 110, 40}, paymentRows,
+		[]string{tr.T("statement.pdf_total"), "", money(data.TotalPaid)})
+
+	section(pdf, tr.N("statement.pdf_upcoming", upcomingDays))
+	upcomingRows := [][]string{}
+	for _, installment := range data.Upcoming {
+		upcomingRows = append(upcomingRows, []string{installment.Date.Format("02.01.2006"), installment.BankName, money(installment.Amount)})
+	}
+	table(tr, pdf, header, []float64{30, 110, 40}, upcomingRows,
+		[]string{tr.T("statement.pdf_total"), "", money(data.TotalUpcoming)})
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func section(pdf *fpdf.Fpdf, title string) {
+	pdf.Ln(3)
+	pdf.SetFont("DejaVu", "B", 12)
+	pdf.CellFormat(0, 8, title, "", 1, "L", false, 0, "")
+}
+
+// table рисует таблицу; первая колонка выравнивается влево, остальные - вправо
+func table(tr *i18n.Localizer, pdf *fpdf.Fpdf, header []string, widths []float64, rows [][]string, footer []string) {
+	align := func(i int) string {
+		if i == 0 {
+			return "L"
+		}
+		return "R"
+	}
+
+	pdf.SetFont("DejaVu", "B", 9)
+	pdf.SetFillColor(235, 235, 235)
+	for i, title := range header {
+		pdf.CellFormat(widths[i], 7, title, "1", 0, align(i), true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("DejaVu", "", 9)
+	if len(rows) == 0 {
+		total := 0.0
+		for _, width := range widths {
+			total += width
+		}
+		pdf.CellFormat(total, 7, tr.T("statement.pdf_no_data"), "1", 1, "C", false, 0, "")
+		return
+	}
+	for _, row := range rows {
+		for i, value := range row {
+			pdf.CellFormat(widths[i], 7, value, "1", 0, align(i), false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+
+	pdf.SetFont("DejaVu", "B", 9)
+	for i, value := range footer {
+		pdf.CellFormat(widths[i], 7, value, "1", 0, align(i), false, 0, "")
+	}
+	pdf.Ln(-1)
+}
+
+func money(amount float64) string {
+	return fmt.Sprintf("%.2f ₽", amount)
+}
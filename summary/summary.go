@@ -0,0 +1,136 @@
+// Package summary считает сводку по долгам пользователя и показатель долговой нагрузки (ПДН)
+package summary
+
+import (
+	"sort"
+	"time"
+
+	"DebtBot/banks"
+	"DebtBot/models"
+	"DebtBot/schedule"
+)
+
+// Пороги ПДН, которые банки и ЦБ считают высокой и очень высокой нагрузкой
+const (
+	WarningLoad  = 0.5
+	CriticalLoad = 0.8
+)
+
+// Уровни долговой нагрузки
+const (
+	LevelUnknown  = "unknown" // Доход не указан
+	LevelNormal   = "normal"
+	LevelWarning  = "warning"
+	LevelCritical = "critical"
+)
+
+// Summary - сводка по долгам пользователя
+type Summary struct {
+	Credits         int
+	Outstanding     float64 // Непогашенный остаток по всем кредитам
+	ThisMonth       float64 // Платежи в текущем календарном месяце
+	Next30Days      float64 // Платежи в ближайшие 30 дней
+	MonthlyPayments float64 // Среднемесячный платеж по всем кредитам (числитель ПДН)
+	Income          float64 // Среднемесячный доход; 0 - не указан
+	Load            float64 // ПДН: MonthlyPayments / Income
+	Level           string
+	ByBank          []BankTotal // Остаток по банкам, от большего к меньшему
+}
+
+// BankTotal - остаток долга в одном банке. Кредиты "Сбер" и "Сбербанк" попадают в одну строку.
+type BankTotal struct {
+	Name        string
+	Emoji       string // Пусто, если банка нет в справочнике
+	Credits     int
+	Outstanding float64
+}
+
+// Calculate строит сводку на момент now
+func Calculate(credits []*models.Credit, payments []*models.Payment, income float64, now time.Time) Summary {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	monthEnd := monthStart.AddDate(0, 1, 0)
+	horizon := today.AddDate(0, 0, 30)
+
+	paid := make(map[int]float64)
+	for _, payment := range payments {
+		paid[payment.CreditID] += payment.Amount
+	}
+
+	s := Summary{Credits: len(credits), Income: income, Level: LevelUnknown}
+	byBank := make(map[string]*BankTotal)
+	for _, credit := range credits {
+		outstanding := schedule.Outstanding(credit, paid[credit.ID])
+		s.Outstanding += outstanding
+		name := banks.Canonical(credit.BankID, credit.BankName)
+		total, ok := byBank[name]
+		if !ok {
+			total = &BankTotal{Name: name}
+			if bank, found := banks.ByID(credit.BankID); found {
+				total.Emoji = bank.Emoji
+			}
+			byBank[name] = total
+		}
+		total.Credits++
+		total.Outstanding += outstanding
+		s.MonthlyPayments += averageMonthlyPayment(credit, today)
+
+		for _, installment := range schedule.ForCredit(credit) {
+			if !installment.Date.Before(monthStart) && installment.Date.Before(monthEnd) {
+				s.ThisMonth += installment.Amount
+			}
+			if !installment.Date.Before(today) && installment.Date.Before(horizon) {
+				s.Next30Days += installment.Amount
+			}
+		}
+	}
+
+	for _, total := range byBank {
+		s.ByBank = append(s.ByBank, *total)
+	}
+	sort.Slice(s.ByBank, func(i, j int) bool {
+		if s.ByBank[i].Outstanding != s.ByBank[j].Outstanding {
+			return s.ByBank[i].Outstanding > s.ByBank[j].Outstanding
+		}
+		return s.ByBank[i].Name < s.ByBank[j].Name
+	})
+
+	if income > 0 {
+		s.Load = s.MonthlyPayments / income
+		switch {
+		case s.Load >= CriticalLoad:
+			s.Level = LevelCritical
+		case s.Load >= WarningLoad:
+			s.Level = LevelWarning
+		default:
+			s.Level = LevelNormal
+		}
+	}
+	return s
+}
+
+// averageMonthlyPayment - как в методике ПДН: оставшиеся платежи по кредиту,
+// равномерно распределенные на оставшийся срок в месяцах (не меньше одного месяца).
+func averageMonthlyPayment(credit *models.Credit, today time.Time) float64 {
+	total := 0.0
+	count := 0
+	last := today
+	for _, installment := range schedule.ForCredit(credit) {
+		if installment.Date.Before(today) {
+			continue
+		}
+		total += installment.Amount
+		count++
+		if installment.Date.After(last) {
+			last = installment.Date
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+
+	months := (last.Year()-today.Year())*12 + int(last.Month()) - int(today.Month())
+	// Ежемесячные платежи по графику: n платежей - это n месяцев, даже если последний в этом месяце
+	months = max(months, count, 1)
+	return total / float64(months)
+}
@@ -1 +1,78 @@
 package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"DebtBot/input"
+)
+
+// Формат даты, в котором пользователь вводит даты платежей
+const DateLayout = "2006-01-02"
+
+// Правила проверки ввода общие для диалогов бота и импорта из файлов. Ошибки проверки -
+// значения ниже; пользователю они показываются переведенными (см. importer).
+var (
+	ErrNoBankName        = errors.New("не указано название банка")
+	ErrInvalidAmount     = errors.New("некорректная сумма")
+	ErrNonPositiveAmount = errors.New("сумма должна быть больше нуля")
+	ErrInvalidDate       = errors.New("некорректная дата")
+)
+
+// ValidateBankName проверяет название банка
+func ValidateBankName(s string) (string, error) {
+	name := strings.TrimSpace(s)
+	if name == "" {
+		return "", ErrNoBankName
+	}
+	return name, nil
+}
+
+// ParseAmount разбирает сумму кредита: "150000", "150 000 ₽", "150к" (см. пакет input)
+func ParseAmount(s string) (float64, error) {
+	amount, err := input.Amount(s)
+	if err != nil {
+		return 0, ErrInvalidAmount
+	}
+	if amount <= 0 {
+		return 0, ErrNonPositiveAmount
+	}
+	return amount, nil
+}
+
+// ParseDate разбирает дату платежа: "2024-12-31", "31.12.2024", "31 декабря", "через месяц" (см. пакет input)
+func ParseDate(s string) (time.Time, error) {
+	date, err := input.Date(s, time.Now())
+	if err != nil {
+		return time.Time{}, ErrInvalidDate
+	}
+	return date, nil
+}
+
+// FormatMoney форматирует сумму в рублях с разделением разрядов: "1 234 567.89 ₽"
+func FormatMoney(amount float64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	s := fmt.Sprintf("%.2f", amount)
+	integer, fraction := s[:len(s)-3], s[len(s)-3:]
+
+	var b strings.Builder
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteRune(' ')
+		}
+		b.WriteRune(digit)
+	}
+	return sign + b.String() + fraction + " ₽"
+}
+
+// EscapeMarkdown экранирует спецсимволы Markdown в пользовательском тексте (usernames часто содержат "_")
+func EscapeMarkdown(s string) string {
+	replacer := strings.NewReplacer("_", "\\_", "*", "\\*", "`", "\\`", "[", "\\[")
+	return replacer.Replace(s)
+}
@@ -0,0 +1,15 @@
+package utils
+
+import "testing"
+
+// Разбор сумм проверяется в пакете input; здесь - что сумма кредита должна быть больше нуля
+func TestParseAmountRejectsNonPositive(t *testing.T) {
+	for _, in := range []string{"0", "0,00", "0 ₽", "0к", "-100", "−100"} {
+		if got, err := ParseAmount(in); err == nil {
+			t.Errorf("ParseAmount(%q) = %v, want error", in, got)
+		}
+	}
+	if got, err := ParseAmount("150 000 ₽"); err != nil || got != 150000 {
+		t.Errorf("ParseAmount(%q) = %v, %v, want 150000", "150 000 ₽", got, err)
+	}
+}
@@ -0,0 +1,82 @@
+// Package web - HTTP-сервер бота для ресурсов, доступных по ссылке (подписка на календарь)
+package web
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"DebtBot/db"
+	"DebtBot/i18n"
+	"DebtBot/ical"
+	"DebtBot/logging"
+	"DebtBot/schedule"
+)
+
+var logger = logging.For("web")
+
+type Server struct {
+	db     *db.DB
+	mux    *http.ServeMux
+	server *http.Server
+}
+
+func NewServer(addr string, database *db.DB) *Server {
+	s := &Server{db: database, mux: http.NewServeMux()}
+	s.mux.HandleFunc("GET /calendar/{file}", s.handleCalendar)
+
+	s.server = &http.Server{
+		Addr:              addr,
+		Handler:           s.mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return s
+}
+
+// Handle подключает к серверу обработчик другого компонента (например, вебхук бота)
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// Start запускает сервер и блокируется до его остановки
+func (s *Server) Start() error {
+	logger.Info("HTTP server listening", "addr", s.server.Addr)
+	err := s.server.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// GET /calendar/<token>.ics - календарь платежей пользователя для подписки
+func (s *Server) handleCalendar(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimSuffix(r.PathValue("file"), ".ics")
+	if token == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	user, err := s.db.GetUserByCalendarToken(token)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		logger.Error("Error getting user by calendar token", "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	credits, err := s.db.GetCreditsByUser(user.ID)
+	if err != nil {
+		logger.Error("Error getting credits for calendar", "user_id", user.ID, "err", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(ical.PaymentsCalendar(i18n.New(user.Lang()), schedule.ForCredits(credits), time.Now()))
+}